@@ -1,20 +1,13 @@
 package main
 
-import "vk-test-spring/internal/app"
-
-// @title Films library API
-// @version 1.0
-// @description API Server for Films library
-
-// @host localhost:8080
-// @BasePath /films
-
-// @SecurityDefinitions basicAuth
-// @SecurityScheme basic
-// @Security BasicAuth
-
-const configPath = "../../configs/main"
+import (
+	"flag"
+	"vk-test-spring/internal/app"
+)
 
 func main() {
-	app.Run(configPath)
+	configPath := flag.String("config", "configs/main.yaml", "path to the config file")
+	flag.Parse()
+
+	app.Run(*configPath)
 }
@@ -0,0 +1,10 @@
+// Package docs holds the OpenAPI 3 spec of the API. It is written by hand; TestSpecMatchesRoutes in
+// internal/controller fails when it and the handlers disagree on paths or methods.
+package docs
+
+import _ "embed"
+
+// OpenAPI is served at /openapi.json.
+//
+//go:embed openapi.json
+var OpenAPI []byte
@@ -1,70 +1,209 @@
 package app
 
 import (
+	"context"
+	"expvar"
 	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 	"vk-test-spring/internal/config"
 	"vk-test-spring/internal/controller"
+	"vk-test-spring/internal/controller/grpcv1"
+	"vk-test-spring/internal/health"
+	"vk-test-spring/internal/metrics"
 	"vk-test-spring/internal/repository"
 	"vk-test-spring/internal/server"
 	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/cache"
 	"vk-test-spring/pkg/database/postgresql"
 	"vk-test-spring/pkg/logger"
+	"vk-test-spring/pkg/tracing"
 )
 
-// @title Films library API
-// @version 1.0
-// @description API Server for Films library
-
-// @host localhost:8080
-// @BasePath /films
-
-// @SecurityDefinitions basicAuth
-// @SecurityScheme basic
-// @Security BasicAuth
+// cleanupTimeout bounds the trace flush and the admin server stop at the end of the shutdown.
+const cleanupTimeout = 5 * time.Second
 
 func Run(configPath string) {
-	// TODO add config for logger
-	logs := logger.InitLogs("../../pkg/logger/logger.json", 5, 3, 30)
+	cfg, err := config.Init(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error while reading config: %v\n", err)
+		return
+	}
+
+	logs, err := logger.InitLogs(loggerOptions(cfg.Logger))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error while initializing logger: %v\n", err)
+		return
+	}
 
 	logs.Info().Msg("Starting app")
 
-	cfg, err := config.Init(configPath)
+	healthChecker := health.New(cfg.Health.Timeout)
+
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", metrics.Handler())
+	adminMux.Handle("/log/level", logger.LevelHandler())
+	adminMux.Handle("/healthz", healthChecker.LivenessHandler())
+	adminMux.Handle("/readyz", healthChecker.ReadinessHandler())
+
+	adminSrv := server.NewAdminServer(cfg, adminMux)
+	go func() {
+		if err := adminSrv.Run(); err != nil {
+			logs.Error().Msg(fmt.Sprintf("error while starting admin server: %v", err.Error()))
+		}
+	}()
+
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
 	if err != nil {
+		logs.Error().Msg(fmt.Sprintf("error while initializing tracing: %v", err.Error()))
 		return
 	}
+	logs.Info().Msg("Initialized tracing")
+
+	workers, stopWorkers := context.WithCancel(logs.WithContext(context.Background()))
+	defer stopWorkers()
+
+	var repos *repository.Repositories
+	closeStorage := func() {}
+
+	switch cfg.Storage.Driver {
+	case config.StorageMemory:
+		repos = repository.NewMemoryRepositories(cfg.Storage.Users, cfg.Search)
+		logs.Warn().Msg("Initialized in-memory repos, data is lost on restart")
+	default:
+		primaryPool, err := postgresql.NewConnectionPool(context.Background(), cfg.PostgreSQL)
+		if err != nil {
+			logs.Fatal().Err(err).Msg("cannot connect to PostgreSQL")
+		}
+		logs.Info().Msg("Initialized connection pool DB")
+
+		metrics.Registry.MustRegister(metrics.NewPoolCollector(primaryPool, "primary"))
+		healthChecker.AddCheck("database", health.DatabasePing(primaryPool))
+		healthChecker.AddCheck("schema", health.SchemaVersion(primaryPool, postgresql.SchemaVersion))
+		healthChecker.AddCheck("database_pool", health.PoolSaturation(primaryPool, cfg.Health.PoolSaturationThreshold))
+
+		var replicaPool *pgxpool.Pool
+		if cfg.PostgreSQL.Replica.Host != "" {
+			replicaPool, err = postgresql.NewReplicaPool(cfg.PostgreSQL)
+			if err != nil {
+				logs.Fatal().Err(err).Msg("cannot configure PostgreSQL replica")
+			}
+			metrics.Registry.MustRegister(metrics.NewPoolCollector(replicaPool, "replica"))
+			logs.Info().Msg("Initialized replica connection pool DB")
+		}
+
+		dbHandler := postgresql.NewCluster(primaryPool, replicaPool, cfg.PostgreSQL.Replica.CheckInterval, cfg.PostgreSQL.Replica.MaxLag)
+		go dbHandler.RunHealthChecks(workers)
+		closeStorage = dbHandler.Close
 
-	dbHandler := postgresql.NewConnectionPool(cfg.PostgreSQL)
-	logs.Info().Msg("Initialized connection pool DB")
+		repos = repository.NewRepositories(dbHandler, cfg.Search, cfg.PostgreSQL.Transaction)
+		logs.Info().Msg("Initialized repos")
+	}
 
-	repos := repository.NewRepositories(dbHandler)
-	logs.Info().Msg("Initialized repos")
+	var servicesCache cache.Cache
+	if cfg.Cache.Enabled {
+		lru := cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL)
+		expvar.Publish("cache", expvar.Func(func() any { return lru.Stats() }))
+		metrics.Registry.MustRegister(metrics.NewCacheCollector(lru))
+		servicesCache = lru
+	}
 
-	services := service.NewServices(repos)
+	services := service.NewServices(repos, servicesCache)
 	logs.Info().Msg("Initialized services")
 
-	handlers := controller.NewHandler()
+	handlers := controller.NewHandler(cfg, logger.NewRedactor(requestLogOptions(cfg.Logger.Request)))
 	mux := handlers.Init(services, logs)
 	logs.Info().Msg("Initialized handlers")
 
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
+
 	srv := server.NewServer(cfg, mux)
+	serverErr := make(chan error, 2)
 	go func() {
-		if err := srv.Run(); err != nil {
-			logs.Error().Msg(fmt.Sprintf("error while starting server: %v", err.Error()))
-		}
+		serverErr <- srv.Run()
 	}()
 
+	grpcSrv := server.NewGRPCServer(cfg, grpcv1.NewServer(services, logs))
+	go func() {
+		serverErr <- grpcSrv.Run()
+	}()
+
+	healthChecker.MarkStarted()
 	logs.Info().Msg("server started")
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 
-	<-quit
+	select {
+	case sig := <-quit:
+		logs.Info().Msg(fmt.Sprintf("Shutting down on %v", sig))
+	case err := <-serverErr:
+		logs.Error().Msg(fmt.Sprintf("error while running server: %v, shutting down", err))
+	}
+
+	healthChecker.MarkShuttingDown()
+
+	if cfg.Shutdown.ReadinessDelay > 0 {
+		logs.Info().Msg(fmt.Sprintf("Reported not ready, serving for %v more", cfg.Shutdown.ReadinessDelay))
+		time.Sleep(cfg.Shutdown.ReadinessDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
+	defer cancel()
+
+	// Both drains share the shutdown timeout, so they run side by side rather than one after the other.
+	var drains sync.WaitGroup
+	drains.Add(2)
+
+	go func() {
+		defer drains.Done()
+
+		if err := srv.Stop(ctx); err != nil {
+			logs.Error().Msg(fmt.Sprintf("error while draining requests: %v", err.Error()))
+		} else {
+			logs.Info().Msg("Drained in-flight requests")
+		}
+	}()
+
+	go func() {
+		defer drains.Done()
+
+		if err := grpcSrv.Stop(ctx); err != nil {
+			logs.Error().Msg(fmt.Sprintf("error while draining gRPC calls: %v", err.Error()))
+		} else {
+			logs.Info().Msg("Drained in-flight gRPC calls")
+		}
+	}()
+
+	drains.Wait()
+
+	// The drains may use up the shutdown timeout, the flush and the admin server get their own.
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancelFlush()
+
+	if err := shutdownTracing(flushCtx); err != nil {
+		logs.Error().Msg(fmt.Sprintf("error while flushing traces: %v", err.Error()))
+	} else {
+		logs.Info().Msg("Flushed traces")
+	}
+
+	adminCtx, cancelAdmin := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancelAdmin()
+
+	if err := adminSrv.Stop(adminCtx); err != nil {
+		logs.Error().Msg(fmt.Sprintf("error while stopping admin server: %v", err.Error()))
+	} else {
+		logs.Info().Msg("Stopped admin server")
+	}
+
+	stopWorkers()
+	logs.Info().Msg("Stopped background workers")
 
-	dbHandler.Close()
+	closeStorage()
+	logs.Info().Msg("Closed storage")
 
-	//if err := dbHandler.Close(context.Background()); err != nil {
-	//	logger.Error(err.Error())
-	//}
+	logs.Info().Msg("Shutdown complete")
 }
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"vk-test-spring/internal/config"
+	"vk-test-spring/pkg/logger"
+)
+
+// loggerOptions maps the logger section of the config to the options of pkg/logger.
+func loggerOptions(cfg config.LoggerConfig) logger.Options {
+	return logger.Options{
+		Level:  cfg.Level,
+		Format: cfg.Format,
+		Output: cfg.Output,
+		File: logger.FileOptions{
+			Path:       cfg.File.Path,
+			MaxSize:    cfg.File.MaxSize,
+			MaxBackups: cfg.File.MaxBackups,
+			MaxAge:     cfg.File.MaxAge,
+			Compress:   cfg.File.Compress,
+		},
+	}
+}
+
+// requestLogOptions maps logger.request of the config to the options of the request log redactor.
+func requestLogOptions(cfg config.RequestLogConfig) logger.RequestOptions {
+	return logger.RequestOptions{
+		RedactHeaders:      cfg.RedactHeaders,
+		RedactQueryParams:  cfg.RedactQueryParams,
+		RedactBodyFields:   cfg.RedactBodyFields,
+		MaxBodySize:        cfg.MaxBodySize,
+		FullBodySampleRate: cfg.FullBodySampleRate,
+	}
+}
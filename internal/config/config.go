@@ -2,34 +2,199 @@ package config
 
 import (
 	"github.com/spf13/viper"
-	filepath2 "path/filepath"
-	"runtime"
 	"time"
 )
 
+// EnvPrefix starts the name of every environment variable read by Init, e.g. FILMS_POSTGRESQL_PASSWORD.
+const EnvPrefix = "FILMS"
+
 const (
 	defaultHttpPort      = "8080"
 	defaultHttpRWTimeout = 10 * time.Second
-	defaultLoggerLevel   = 5
+	defaultCacheControl  = "private, no-cache"
+	defaultAdminPort     = "9090"
+	defaultGRPCPort      = "9091"
+
+	defaultLoggerLevel          = "info"
+	defaultLoggerFormat         = "json"
+	defaultLoggerOutput         = "file"
+	defaultLoggerFilePath       = "logs/app.log"
+	defaultLoggerFileMaxSize    = 5
+	defaultLoggerFileMaxBackups = 3
+	defaultLoggerFileMaxAge     = 30
+
+	defaultRequestLogMaxBodySize = 1024
+
+	defaultSearchSimilarityThreshold = 0.3
+
+	defaultGraphQLMaxDepth      = 8
+	defaultGraphQLMaxComplexity = 1000
+
+	defaultTracingServiceName = "films-library"
+	defaultTracingExporter    = "stdout"
+	defaultTracingSampleRatio = 1.0
+
+	defaultHealthTimeout                 = 2 * time.Second
+	defaultHealthPoolSaturationThreshold = 0.9
+
+	defaultShutdownReadinessDelay = 5 * time.Second
+	defaultShutdownTimeout        = 15 * time.Second
+
+	defaultPostgreSQLSSLMode           = "prefer"
+	defaultPostgreSQLConnectAttempts   = 5
+	defaultPostgreSQLConnectBackoff    = 500 * time.Millisecond
+	defaultPostgreSQLConnectMaxBackoff = 10 * time.Second
+
+	defaultPostgreSQLReplicaCheckInterval = 5 * time.Second
+	defaultPostgreSQLReplicaMaxLag        = 10 * time.Second
+
+	defaultPostgreSQLTransactionIsolation    = "read committed"
+	defaultPostgreSQLTransactionMaxRetries   = 3
+	defaultPostgreSQLTransactionRetryBackoff = 20 * time.Millisecond
+
+	defaultStorageDriver = StoragePostgreSQL
+
+	defaultCacheSize = 1000
+	defaultCacheTTL  = time.Minute
+)
+
+// Storage drivers selectable in StorageConfig.
+const (
+	StoragePostgreSQL = "postgresql"
+	StorageMemory     = "memory"
 )
 
 type Config struct {
+	Storage    StorageConfig
 	PostgreSQL PostgreSQLConfig
 	HTTP       HTTPConfig
+	Admin      AdminConfig
+	GRPC       GRPCConfig
 	Logger     LoggerConfig
+	Search     SearchConfig
+	GraphQL    GraphQLConfig
+	Cache      CacheConfig
+	Tracing    TracingConfig
+	Health     HealthConfig
+	Shutdown   ShutdownConfig
+}
+
+// StorageConfig selects where films, actors and users are kept: "postgresql", or "memory" for demos
+// and tests without a database. The memory storage starts with Users only, loses everything on
+// restart and has no user lists or full-text search.
+type StorageConfig struct {
+	Driver string
+	Users  []StorageUserConfig
+}
+
+// StorageUserConfig is a user of the memory storage. PasswordHash is a bcrypt hash of the password,
+// as PostgreSQL stores it; the password itself is never written in the config.
+type StorageUserConfig struct {
+	Name         string
+	PasswordHash string
+	Role         string
 }
 
+// LoggerConfig selects the log level (trace, debug, info, warn, error), the format (json or console)
+// and where logs go: a rotated file, stdout or both.
 type LoggerConfig struct {
-	LoggerLevel    int
-	LoggerFilePath string
+	Level   string
+	Format  string
+	Output  string
+	File    LogFileConfig
+	Request RequestLogConfig
+}
+
+// LogFileConfig is passed to lumberjack. MaxSize is in megabytes, MaxAge in days.
+type LogFileConfig struct {
+	Path       string
+	MaxSize    int
+	MaxBackups int
+	MaxAge     int
+	Compress   bool
+}
+
+// RequestLogConfig controls what the request log keeps. Authorization and cookie headers, password
+// and token fields are always redacted, the Redact* lists add more names on top of them.
+// Bodies are cut to MaxBodySize bytes (0 disables body logging); FullBodySampleRate is the share
+// of requests whose body is logged without the cut.
+type RequestLogConfig struct {
+	RedactHeaders      []string
+	RedactQueryParams  []string
+	RedactBodyFields   []string
+	MaxBodySize        int
+	FullBodySampleRate float64
 }
 
 type HTTPConfig struct {
 	Port         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// CacheControl maps a route group (films, actors, lists, shared_lists, search, suggest)
+	// to its Cache-Control header. The "default" entry applies to groups without their own policy.
+	CacheControl map[string]string
+}
+
+// AdminConfig describes the internal listener serving operational endpoints such as /metrics.
+type AdminConfig struct {
+	Port string
+}
+
+// GRPCConfig describes the listener of the gRPC API for internal consumers.
+type GRPCConfig struct {
+	Port string
+}
+
+// HealthConfig tunes the readiness probe. PoolSaturationThreshold is the share of connections
+// in use at which the app reports itself as not ready.
+type HealthConfig struct {
+	Timeout                 time.Duration
+	PoolSaturationThreshold float64
 }
 
+// ShutdownConfig bounds the graceful shutdown. ReadinessDelay keeps serving after /readyz turns
+// unready, so load balancers stop routing before the listener closes; Timeout limits the drain
+// of in-flight requests.
+type ShutdownConfig struct {
+	ReadinessDelay time.Duration
+	Timeout        time.Duration
+}
+
+type SearchConfig struct {
+	SimilarityThreshold float64
+}
+
+// GraphQLConfig limits the queries of /graphql. MaxDepth is the deepest selection a query may nest;
+// MaxComplexity is its cost, where every field costs 1 and the fields under a list count once per
+// element, taken as the limit argument of the list (or of the field returning it) or 10 without one.
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type CacheConfig struct {
+	Enabled bool
+	Size    int
+	TTL     time.Duration
+}
+
+// TracingConfig selects where spans are exported: "otlp" sends them to Endpoint over HTTP,
+// "stdout" and "file" write them as JSON, which is handy for offline debugging.
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	File        string
+	SampleRatio float64
+}
+
+// PostgreSQLConfig describes the connection pool. SSLMode takes the libpq modes (disable, allow,
+// prefer, require, verify-ca, verify-full); SSLRootCert is the CA bundle for the verify modes and
+// SSLCert/SSLKey a client certificate. StatementTimeout is set on every connection when positive.
+// A failed first connection is retried ConnectAttempts times, starting after ConnectBackoff and
+// doubling up to ConnectMaxBackoff.
 type PostgreSQLConfig struct {
 	Host                  string
 	Port                  string
@@ -37,73 +202,112 @@ type PostgreSQLConfig struct {
 	Password              string
 	DBName                string
 	SSLMode               string
+	SSLRootCert           string
+	SSLCert               string
+	SSLKey                string
 	MaxIdleConnections    int
 	MaxOpenConnections    int
 	ConnectionMaxLifetime time.Duration
+	StatementTimeout      time.Duration
+	ConnectAttempts       int
+	ConnectBackoff        time.Duration
+	ConnectMaxBackoff     time.Duration
 	DriverName            string
+	Replica               PostgreSQLReplicaConfig
+	Transaction           TransactionConfig
 }
 
-func Init(path string) (*Config, error) {
-	setDefaults()
+// PostgreSQLReplicaConfig enables a read replica when Host is set. It shares the credentials,
+// database and pool settings of the primary. The replica is pinged every CheckInterval and
+// skipped while it fails or lags more than MaxLag behind (0 disables the lag check).
+type PostgreSQLReplicaConfig struct {
+	Host          string
+	Port          string
+	CheckInterval time.Duration
+	MaxLag        time.Duration
+}
 
-	var cfg Config
+// TransactionConfig applies to transactions spanning several repository calls. Isolation is
+// "read committed", "repeatable read" or "serializable". A transaction failing with a
+// serialization failure or a deadlock is retried MaxRetries times, waiting RetryBackoff,
+// doubled after every attempt.
+type TransactionConfig struct {
+	Isolation    string
+	MaxRetries   int
+	RetryBackoff time.Duration
+}
 
-	if err := parseConfigFile(path); err != nil {
-		return nil, err
-	}
+// Init reads the config file at path and applies environment overrides on top of it.
+// Every key can be set with FILMS_<SECTION>_<KEY>, and with FILMS_<SECTION>_<KEY>_FILE naming
+// a file that holds the value, which is how Docker and Kubernetes secrets are mounted.
+// The result is validated before it is returned.
+func Init(path string) (*Config, error) {
+	v := viper.New()
+	setDefaults(v)
 
-	if err := unmarshal(&cfg); err != nil {
+	if err := parseConfigFile(v, path); err != nil {
 		return nil, err
 	}
 
-	return &cfg, nil
-}
-
-func unmarshal(cfg *Config) error {
-	if err := viper.UnmarshalKey("logger.level", &cfg.Logger.LoggerLevel); err != nil {
-		return err
-	}
-
-	if err := viper.UnmarshalKey("loggger.file", &cfg.Logger.LoggerFilePath); err != nil {
-		return err
+	if err := bindEnv(v); err != nil {
+		return nil, err
 	}
 
-	if err := viper.UnmarshalKey("http", &cfg.HTTP); err != nil {
-		return err
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, err
 	}
 
-	if err := viper.UnmarshalKey("postgresql", &cfg.PostgreSQL); err != nil {
-		return err
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &cfg, nil
 }
 
-// TODO Refactor configPath
-func parseConfigFile(filepath string) error {
-
-	//dir := filepath2.Dir(filepath)
-	//
-	//viper.AddConfigPath(dir)
-	//
-	//name := filepath2.Base(filepath)
-	//viper.SetConfigName(name)
-	if os := runtime.GOOS; os == "linux" {
-		viper.SetConfigFile("/app/configs/main.yaml")
-	} else {
-		path := filepath2.Dir(filepath)
-		name := filepath2.Base(filepath)
-
-		viper.AddConfigPath(path)
-		viper.SetConfigName(name)
-	}
+func parseConfigFile(v *viper.Viper, path string) error {
+	v.SetConfigFile(path)
 
-	return viper.ReadInConfig()
+	return v.ReadInConfig()
 }
 
-func setDefaults() {
-	viper.SetDefault("http.port", defaultHttpPort)
-	viper.SetDefault("http.timeouts.read", defaultHttpRWTimeout)
-	viper.SetDefault("http.timeouts.write", defaultHttpRWTimeout)
-	viper.SetDefault("logger.level", defaultLoggerLevel)
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("storage.driver", defaultStorageDriver)
+	v.SetDefault("http.port", defaultHttpPort)
+	v.SetDefault("http.readTimeout", defaultHttpRWTimeout)
+	v.SetDefault("http.writeTimeout", defaultHttpRWTimeout)
+	v.SetDefault("http.cacheControl.default", defaultCacheControl)
+	v.SetDefault("admin.port", defaultAdminPort)
+	v.SetDefault("grpc.port", defaultGRPCPort)
+	v.SetDefault("logger.level", defaultLoggerLevel)
+	v.SetDefault("logger.format", defaultLoggerFormat)
+	v.SetDefault("logger.output", defaultLoggerOutput)
+	v.SetDefault("logger.file.path", defaultLoggerFilePath)
+	v.SetDefault("logger.file.maxSize", defaultLoggerFileMaxSize)
+	v.SetDefault("logger.file.maxBackups", defaultLoggerFileMaxBackups)
+	v.SetDefault("logger.file.maxAge", defaultLoggerFileMaxAge)
+	v.SetDefault("logger.file.compress", true)
+	v.SetDefault("logger.request.maxBodySize", defaultRequestLogMaxBodySize)
+	v.SetDefault("logger.request.fullBodySampleRate", 0)
+	v.SetDefault("search.similarityThreshold", defaultSearchSimilarityThreshold)
+	v.SetDefault("graphql.maxDepth", defaultGraphQLMaxDepth)
+	v.SetDefault("graphql.maxComplexity", defaultGraphQLMaxComplexity)
+	v.SetDefault("cache.size", defaultCacheSize)
+	v.SetDefault("cache.ttl", defaultCacheTTL)
+	v.SetDefault("tracing.serviceName", defaultTracingServiceName)
+	v.SetDefault("tracing.exporter", defaultTracingExporter)
+	v.SetDefault("tracing.sampleRatio", defaultTracingSampleRatio)
+	v.SetDefault("health.timeout", defaultHealthTimeout)
+	v.SetDefault("postgresql.sslmode", defaultPostgreSQLSSLMode)
+	v.SetDefault("postgresql.connectAttempts", defaultPostgreSQLConnectAttempts)
+	v.SetDefault("postgresql.connectBackoff", defaultPostgreSQLConnectBackoff)
+	v.SetDefault("postgresql.connectMaxBackoff", defaultPostgreSQLConnectMaxBackoff)
+	v.SetDefault("postgresql.replica.checkInterval", defaultPostgreSQLReplicaCheckInterval)
+	v.SetDefault("postgresql.replica.maxLag", defaultPostgreSQLReplicaMaxLag)
+	v.SetDefault("postgresql.transaction.isolation", defaultPostgreSQLTransactionIsolation)
+	v.SetDefault("postgresql.transaction.maxRetries", defaultPostgreSQLTransactionMaxRetries)
+	v.SetDefault("postgresql.transaction.retryBackoff", defaultPostgreSQLTransactionRetryBackoff)
+	v.SetDefault("shutdown.readinessDelay", defaultShutdownReadinessDelay)
+	v.SetDefault("shutdown.timeout", defaultShutdownTimeout)
+	v.SetDefault("health.poolSaturationThreshold", defaultHealthPoolSaturationThreshold)
 }
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testConfig = `
+http:
+  port: 8080
+  readTimeout: 5s
+postgresql:
+  host: localhost
+  port: 5432
+  user: postgres
+  password: from-file
+  dbname: films_library
+`
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestInit(t *testing.T) {
+	t.Run("file and defaults", func(t *testing.T) {
+		cfg, err := Init(writeFile(t, "main.yaml", testConfig))
+		require.NoError(t, err)
+
+		assert.Equal(t, "8080", cfg.HTTP.Port)
+		assert.Equal(t, 5*time.Second, cfg.HTTP.ReadTimeout)
+		assert.Equal(t, defaultHttpRWTimeout, cfg.HTTP.WriteTimeout)
+		assert.Equal(t, "from-file", cfg.PostgreSQL.Password)
+		assert.Equal(t, defaultAdminPort, cfg.Admin.Port)
+	})
+
+	t.Run("environment overrides", func(t *testing.T) {
+		t.Setenv("FILMS_POSTGRESQL_PASSWORD", "from-env")
+		t.Setenv("FILMS_HTTP_READTIMEOUT", "7s")
+		t.Setenv("FILMS_CACHE_ENABLED", "true")
+		t.Setenv("FILMS_LOGGER_REQUEST_REDACTBODYFIELDS", "note,email")
+
+		cfg, err := Init(writeFile(t, "main.yaml", testConfig))
+		require.NoError(t, err)
+
+		assert.Equal(t, "from-env", cfg.PostgreSQL.Password)
+		assert.Equal(t, 7*time.Second, cfg.HTTP.ReadTimeout)
+		assert.True(t, cfg.Cache.Enabled)
+		assert.Equal(t, []string{"note", "email"}, cfg.Logger.Request.RedactBodyFields)
+	})
+
+	t.Run("secret file", func(t *testing.T) {
+		t.Setenv("FILMS_POSTGRESQL_PASSWORD_FILE", writeFile(t, "db_password", "from-secret\n"))
+
+		cfg, err := Init(writeFile(t, "main.yaml", testConfig))
+		require.NoError(t, err)
+
+		assert.Equal(t, "from-secret", cfg.PostgreSQL.Password)
+	})
+
+	t.Run("secret file and variable together", func(t *testing.T) {
+		t.Setenv("FILMS_POSTGRESQL_PASSWORD", "from-env")
+		t.Setenv("FILMS_POSTGRESQL_PASSWORD_FILE", writeFile(t, "db_password", "from-secret"))
+
+		_, err := Init(writeFile(t, "main.yaml", testConfig))
+		assert.ErrorContains(t, err, "postgresql.password")
+	})
+
+	t.Run("missing secret file", func(t *testing.T) {
+		t.Setenv("FILMS_POSTGRESQL_PASSWORD_FILE", filepath.Join(t.TempDir(), "absent"))
+
+		_, err := Init(writeFile(t, "main.yaml", testConfig))
+		assert.ErrorContains(t, err, "postgresql.password")
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := Init(filepath.Join(t.TempDir(), "absent.yaml"))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid values name the key", func(t *testing.T) {
+		t.Setenv("FILMS_HTTP_PORT", "http")
+		t.Setenv("FILMS_LOGGER_LEVEL", "loud")
+		t.Setenv("FILMS_POSTGRESQL_HOST", "")
+
+		_, err := Init(writeFile(t, "main.yaml", testConfig))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "http.port")
+		assert.ErrorContains(t, err, "logger.level")
+	})
+
+	t.Run("memory users need a password hash", func(t *testing.T) {
+		_, err := Init(writeFile(t, "main.yaml", testConfig+`
+storage:
+  users:
+    - name: admin
+      passwordHash: admin
+      role: администратор
+`))
+		assert.ErrorContains(t, err, "storage.users.0: must have a name and a bcrypt passwordHash")
+	})
+
+	t.Run("the shipped config is valid", func(t *testing.T) {
+		cfg, err := Init(filepath.Join("..", "..", "configs", "main.yaml"))
+		require.NoError(t, err)
+		assert.Len(t, cfg.Storage.Users, 2)
+	})
+}
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "FILMS_POSTGRESQL_PASSWORD", EnvVar("postgresql.password"))
+	assert.Equal(t, "FILMS_LOGGER_FILE_MAXSIZE", EnvVar("logger.file.maxSize"))
+}
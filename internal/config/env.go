@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"reflect"
+	"strings"
+)
+
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+// bindEnv makes every key of Config overridable from the environment, including keys that are
+// absent from the config file, and resolves *_FILE variables into values.
+func bindEnv(v *viper.Viper) error {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv()
+
+	keys := configKeys(reflect.TypeOf(Config{}), "")
+	keys = append(keys, v.AllKeys()...)
+
+	for _, key := range keys {
+		if err := v.BindEnv(key); err != nil {
+			return err
+		}
+
+		if err := bindSecretFile(v, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindSecretFile sets key from the file named by its _FILE variable. Surrounding whitespace is
+// trimmed, since secret files usually end with a newline.
+func bindSecretFile(v *viper.Viper, key string) error {
+	variable := EnvVar(key) + "_FILE"
+
+	path, ok := os.LookupEnv(variable)
+	if !ok {
+		return nil
+	}
+
+	if _, set := os.LookupEnv(EnvVar(key)); set {
+		return fmt.Errorf("%s: both %s and %s are set", key, EnvVar(key), variable)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s: reading %s: %w", key, variable, err)
+	}
+
+	v.Set(key, strings.TrimSpace(string(data)))
+	return nil
+}
+
+// EnvVar returns the environment variable that overrides key, e.g. FILMS_POSTGRESQL_PASSWORD for postgresql.password.
+func EnvVar(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// configKeys lists the dotted keys of every leaf field of t. Maps are skipped: their entries
+// are only known from the config file, and those are bound through AllKeys.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.ToLower(field.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Type.Kind() == reflect.Map {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == t.PkgPath() {
+			keys = append(keys, configKeys(field.Type, key)...)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"slices"
+	"strconv"
+	"vk-test-spring/internal/models"
+)
+
+// Validate checks the whole config and reports every invalid key, not just the first one.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key string, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(validPort(c.HTTP.Port), "http.port", "must be a port number, got %q", c.HTTP.Port)
+	check(c.HTTP.ReadTimeout > 0, "http.readTimeout", "must be positive, got %v", c.HTTP.ReadTimeout)
+	check(c.HTTP.WriteTimeout > 0, "http.writeTimeout", "must be positive, got %v", c.HTTP.WriteTimeout)
+
+	check(validPort(c.Admin.Port), "admin.port", "must be a port number, got %q", c.Admin.Port)
+	check(c.Admin.Port != c.HTTP.Port, "admin.port", "must differ from http.port")
+
+	check(validPort(c.GRPC.Port), "grpc.port", "must be a port number, got %q", c.GRPC.Port)
+	check(c.GRPC.Port != c.HTTP.Port && c.GRPC.Port != c.Admin.Port, "grpc.port", "must differ from http.port and admin.port")
+
+	check(slices.Contains([]string{"trace", "debug", "info", "warn", "error"}, c.Logger.Level),
+		"logger.level", "must be one of trace, debug, info, warn, error, got %q", c.Logger.Level)
+	check(slices.Contains([]string{"json", "console"}, c.Logger.Format),
+		"logger.format", "must be json or console, got %q", c.Logger.Format)
+	check(slices.Contains([]string{"file", "stdout", "both"}, c.Logger.Output),
+		"logger.output", "must be file, stdout or both, got %q", c.Logger.Output)
+	if c.Logger.Output != "stdout" {
+		check(c.Logger.File.Path != "", "logger.file.path", "must be set when logging to a file")
+		check(c.Logger.File.MaxSize > 0, "logger.file.maxSize", "must be positive, got %d", c.Logger.File.MaxSize)
+	}
+	check(c.Logger.Request.MaxBodySize >= 0, "logger.request.maxBodySize", "must not be negative, got %d", c.Logger.Request.MaxBodySize)
+	check(c.Logger.Request.FullBodySampleRate >= 0 && c.Logger.Request.FullBodySampleRate <= 1,
+		"logger.request.fullBodySampleRate", "must be between 0 and 1, got %v", c.Logger.Request.FullBodySampleRate)
+
+	check(slices.Contains([]string{StoragePostgreSQL, StorageMemory}, c.Storage.Driver),
+		"storage.driver", "must be postgresql or memory, got %q", c.Storage.Driver)
+	for i, u := range c.Storage.Users {
+		_, err := bcrypt.Cost([]byte(u.PasswordHash))
+		check(u.Name != "" && err == nil, fmt.Sprintf("storage.users.%d", i), "must have a name and a bcrypt passwordHash")
+		check(slices.Contains([]string{models.RoleUser, models.RoleAdmin}, u.Role),
+			fmt.Sprintf("storage.users.%d.role", i), "must be пользователь or администратор, got %q", u.Role)
+	}
+
+	if c.Storage.Driver != StorageMemory {
+		check(c.PostgreSQL.Host != "", "postgresql.host", "must be set")
+		check(validPort(c.PostgreSQL.Port), "postgresql.port", "must be a port number, got %q", c.PostgreSQL.Port)
+		check(c.PostgreSQL.User != "", "postgresql.user", "must be set")
+		check(c.PostgreSQL.DBName != "", "postgresql.dbname", "must be set")
+		check(slices.Contains([]string{"disable", "disabled", "allow", "prefer", "require", "verify-ca", "verify-full"}, c.PostgreSQL.SSLMode),
+			"postgresql.sslmode", "must be one of disable, allow, prefer, require, verify-ca, verify-full, got %q", c.PostgreSQL.SSLMode)
+		check(c.PostgreSQL.MaxOpenConnections >= 0, "postgresql.maxOpenConnections", "must not be negative, got %d", c.PostgreSQL.MaxOpenConnections)
+		check(c.PostgreSQL.MaxIdleConnections >= 0, "postgresql.maxIdleConnections", "must not be negative, got %d", c.PostgreSQL.MaxIdleConnections)
+		check(c.PostgreSQL.StatementTimeout >= 0, "postgresql.statementTimeout", "must not be negative, got %v", c.PostgreSQL.StatementTimeout)
+		check(c.PostgreSQL.ConnectAttempts > 0, "postgresql.connectAttempts", "must be positive, got %d", c.PostgreSQL.ConnectAttempts)
+		if c.PostgreSQL.Replica.Host != "" {
+			check(validPort(c.PostgreSQL.Replica.Port), "postgresql.replica.port", "must be a port number, got %q", c.PostgreSQL.Replica.Port)
+			check(c.PostgreSQL.Replica.CheckInterval > 0, "postgresql.replica.checkInterval", "must be positive, got %v", c.PostgreSQL.Replica.CheckInterval)
+			check(c.PostgreSQL.Replica.MaxLag >= 0, "postgresql.replica.maxLag", "must not be negative, got %v", c.PostgreSQL.Replica.MaxLag)
+		}
+		check(c.PostgreSQL.ConnectBackoff >= 0, "postgresql.connectBackoff", "must not be negative, got %v", c.PostgreSQL.ConnectBackoff)
+		check(slices.Contains([]string{"read committed", "repeatable read", "serializable"}, c.PostgreSQL.Transaction.Isolation),
+			"postgresql.transaction.isolation", "must be read committed, repeatable read or serializable, got %q", c.PostgreSQL.Transaction.Isolation)
+		check(c.PostgreSQL.Transaction.MaxRetries >= 0, "postgresql.transaction.maxRetries", "must not be negative, got %d", c.PostgreSQL.Transaction.MaxRetries)
+		check(c.PostgreSQL.Transaction.RetryBackoff >= 0, "postgresql.transaction.retryBackoff", "must not be negative, got %v", c.PostgreSQL.Transaction.RetryBackoff)
+	}
+
+	check(c.Search.SimilarityThreshold >= 0 && c.Search.SimilarityThreshold <= 1,
+		"search.similarityThreshold", "must be between 0 and 1, got %v", c.Search.SimilarityThreshold)
+
+	check(c.GraphQL.MaxDepth > 0, "graphql.maxDepth", "must be positive, got %d", c.GraphQL.MaxDepth)
+	check(c.GraphQL.MaxComplexity > 0, "graphql.maxComplexity", "must be positive, got %d", c.GraphQL.MaxComplexity)
+
+	if c.Cache.Enabled {
+		check(c.Cache.Size > 0, "cache.size", "must be positive, got %d", c.Cache.Size)
+		check(c.Cache.TTL > 0, "cache.ttl", "must be positive, got %v", c.Cache.TTL)
+	}
+
+	if c.Tracing.Enabled {
+		check(slices.Contains([]string{"otlp", "stdout", "file"}, c.Tracing.Exporter),
+			"tracing.exporter", "must be otlp, stdout or file, got %q", c.Tracing.Exporter)
+		check(c.Tracing.Exporter != "otlp" || c.Tracing.Endpoint != "", "tracing.endpoint", "must be set for the otlp exporter")
+		check(c.Tracing.Exporter != "file" || c.Tracing.File != "", "tracing.file", "must be set for the file exporter")
+		check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+			"tracing.sampleRatio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	check(c.Health.Timeout > 0, "health.timeout", "must be positive, got %v", c.Health.Timeout)
+	check(c.Health.PoolSaturationThreshold > 0 && c.Health.PoolSaturationThreshold <= 1,
+		"health.poolSaturationThreshold", "must be above 0 and at most 1, got %v", c.Health.PoolSaturationThreshold)
+
+	check(c.Shutdown.ReadinessDelay >= 0, "shutdown.readinessDelay", "must not be negative, got %v", c.Shutdown.ReadinessDelay)
+	check(c.Shutdown.Timeout > 0, "shutdown.timeout", "must be positive, got %v", c.Shutdown.Timeout)
+
+	return errors.Join(errs...)
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
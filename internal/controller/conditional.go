@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultCachePolicy = "default"
+
+// maxBufferedBody is the size up to which conditionalGet holds a response back to tag it with an
+// ETag hashed from the body. Longer responses without an ETag of their own are passed on as they are
+// written, without one.
+const maxBufferedBody = 1 << 20
+
+// conditionalGet tags successful GET responses with a strong ETag and the route's Cache-Control policy,
+// and answers 304 Not Modified when the client already holds the current representation. A handler that
+// sets the ETag itself, from a version of what it returns, is passed through without buffering.
+func (h *Handler) conditionalGet(route string, next http.Handler) http.Handler {
+	policy, ok := h.cacheControl[route]
+	if !ok {
+		policy = h.cacheControl[defaultCachePolicy]
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, policy: policy, notModified: func(etag string) bool {
+			return h.notModified(r, etag, w.Header().Get("Last-Modified"))
+		}}
+		next.ServeHTTP(bw, r)
+
+		if bw.passThrough {
+			return
+		}
+
+		if bw.status() == http.StatusOK && w.Header().Get("ETag") == "" {
+			sum := sha256.Sum256(bw.body.Bytes())
+			w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		}
+
+		bw.startPassThrough()
+	})
+}
+
+func setCachePolicy(w http.ResponseWriter, policy string) {
+	w.Header().Add("Vary", "Authorization")
+	if policy != "" {
+		w.Header().Set("Cache-Control", policy)
+	}
+}
+
+// bufferedWriter holds a response back until it is complete or grows over maxBufferedBody. Other
+// statuses than 200 OK, responses over the limit and responses with an ETag are passed on to the
+// client as they come.
+type bufferedWriter struct {
+	http.ResponseWriter
+	policy      string
+	notModified func(etag string) bool
+	code        int
+	body        bytes.Buffer
+	passThrough bool
+	// discard drops the body of a response answered with 304 Not Modified.
+	discard bool
+}
+
+func (b *bufferedWriter) status() int {
+	if b.code == 0 {
+		return http.StatusOK
+	}
+
+	return b.code
+}
+
+func (b *bufferedWriter) WriteHeader(code int) {
+	if b.code == 0 {
+		b.code = code
+	}
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	if !b.passThrough && (b.status() != http.StatusOK || b.Header().Get("ETag") != "" || b.body.Len()+len(p) > maxBufferedBody) {
+		b.startPassThrough()
+	}
+
+	if b.discard {
+		return len(p), nil
+	}
+
+	if b.passThrough {
+		return b.ResponseWriter.Write(p)
+	}
+
+	return b.body.Write(p)
+}
+
+// startPassThrough sends the status and what is held back. A 200 OK carrying an ETag the client
+// already holds is sent as 304 Not Modified, without the body.
+func (b *bufferedWriter) startPassThrough() {
+	b.passThrough = true
+
+	if b.status() == http.StatusOK {
+		setCachePolicy(b.ResponseWriter, b.policy)
+
+		if etag := b.Header().Get("ETag"); etag != "" && b.notModified(etag) {
+			b.discard = true
+			b.Header().Del("Content-Type")
+			b.Header().Del("Content-Length")
+			b.ResponseWriter.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	b.ResponseWriter.WriteHeader(b.status())
+	b.body.WriteTo(b.ResponseWriter)
+}
+
+// Flush is a no-op while the response is held back.
+func (b *bufferedWriter) Flush() {
+	if !b.passThrough {
+		return
+	}
+
+	if f, ok := b.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// notModified evaluates If-None-Match and, only when it is absent, If-Modified-Since (RFC 9110, section 13.2.2).
+func (h *Handler) notModified(r *http.Request, etag string, lastModified string) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditionalGet(t *testing.T) {
+	h := &Handler{cacheControl: map[string]string{defaultCachePolicy: "private, no-cache"}}
+
+	serve := func(size int, etag string, header map[string]string) *httptest.ResponseRecorder {
+		handler := h.conditionalGet("films", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/csv")
+			if etag != "" {
+				w.Header().Set("ETag", etag)
+			}
+			chunk := bytes.Repeat([]byte("x"), 64<<10)
+			for written := 0; written < size; written += len(chunk) {
+				w.Write(chunk[:min(len(chunk), size-written)])
+				w.(http.Flusher).Flush()
+			}
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/films", nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("small responses are tagged", func(t *testing.T) {
+		w := serve(100<<10, "", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+		assert.False(t, w.Flushed)
+		assert.Equal(t, 100<<10, w.Body.Len())
+
+		w = serve(100<<10, "", map[string]string{"If-None-Match": w.Header().Get("ETag")})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Type"))
+	})
+
+	t.Run("large responses stream untagged", func(t *testing.T) {
+		w := serve(3*maxBufferedBody, "", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+		assert.True(t, w.Flushed)
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Equal(t, 3*maxBufferedBody, w.Body.Len())
+	})
+
+	t.Run("versioned responses stream tagged", func(t *testing.T) {
+		w := serve(3*maxBufferedBody, `"v1"`, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+		assert.True(t, w.Flushed)
+		assert.Equal(t, 3*maxBufferedBody, w.Body.Len())
+
+		w = serve(3*maxBufferedBody, `"v1"`, map[string]string{"If-None-Match": `"v1"`})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Type"))
+		assert.Zero(t, w.Body.Len())
+	})
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"bytes"
+	swaggerFiles "github.com/swaggo/files"
+	"net/http"
+	"time"
+	"vk-test-spring/docs"
+)
+
+// swaggerInitializer replaces the one of swaggerFiles, which points Swagger UI at the petstore.
+const swaggerInitializer = `window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    url: "/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// openAPI serves the spec of the API.
+func (h *Handler) openAPI() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		http.ServeContent(w, r, "openapi.json", time.Time{}, bytes.NewReader(docs.OpenAPI))
+	})
+}
+
+// swaggerUI serves Swagger UI showing the spec under /docs/.
+func (h *Handler) swaggerUI() http.Handler {
+	files := http.StripPrefix("/docs", http.FileServer(swaggerFiles.HTTP))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/docs":
+			http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
+		case "/docs/swagger-initializer.js":
+			w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+			w.Write([]byte(swaggerInitializer))
+		default:
+			files.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,364 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"vk-test-spring/docs"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/controller/graph"
+	"vk-test-spring/internal/controller/httpv1"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository"
+	"vk-test-spring/internal/repository/repositorytest"
+	"vk-test-spring/internal/service"
+)
+
+// samples fill the path parameters of the spec when requesting its paths.
+var samples = map[string]string{
+	"{id}":      "3f1c2a9e-8d4b-4c2e-9a7f-1b2c3d4e5f60",
+	"{film_id}": "3f1c2a9e-8d4b-4c2e-9a7f-1b2c3d4e5f60",
+	"{list}":    "watchlist",
+	"{token}":   strings.Repeat("ab", 32),
+	"{file}":    "swagger-ui.css",
+}
+
+// methods are those a documented path is checked to accept or refuse.
+var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// schemaTypes are the types the handlers decode requests into and encode responses from, by the name
+// of the schema of the spec describing them.
+var schemaTypes = map[string]any{
+	"Error":               httpv1.ErrorResponse{},
+	"FilmActor":           models.FilmActors{},
+	"Film":                models.Film{},
+	"FilmInput":           httpv1.FilmCreateInput{},
+	"FilmUpdate":          httpv1.FilmUpdateInput{},
+	"ActorFilm":           models.ActorFilm{},
+	"Actor":               models.Actor{},
+	"ActorInput":          httpv1.ActorCreateInput{},
+	"ActorUpdate":         httpv1.ActorUpdateInput{},
+	"ListFilm":            models.ListFilm{},
+	"List":                models.UserList{},
+	"ListCreateInput":     httpv1.ListCreateInput{},
+	"ListUpdateInput":     httpv1.ListUpdateInput{},
+	"ListFilmInput":       httpv1.ListFilmInput{},
+	"ListFilmUpdateInput": httpv1.ListFilmUpdateInput{},
+	"SearchResult":        models.SearchResult{},
+	"SearchPage":          models.SearchPage{},
+	"Suggestion":          models.Suggestion{},
+	"GraphQLRequest":      graph.Request{},
+	"GraphQLResponse":     graphql.Result{},
+}
+
+type spec struct {
+	Paths      map[string]map[string]operation       `json:"paths"`
+	Components map[string]map[string]json.RawMessage `json:"components"`
+}
+
+type operation struct {
+	Responses map[string]response `json:"responses"`
+}
+
+type response struct {
+	Ref     string                     `json:"$ref"`
+	Content map[string]json.RawMessage `json:"content"`
+}
+
+type schema struct {
+	Ref        string            `json:"$ref"`
+	Type       string            `json:"type"`
+	Properties map[string]schema `json:"properties"`
+	Items      *schema           `json:"items"`
+}
+
+func loadSpec(t *testing.T) spec {
+	var s spec
+	require.NoError(t, json.Unmarshal(docs.OpenAPI, &s))
+
+	return s
+}
+
+// response resolves the response of an operation documented for status, following a reference
+// to the shared responses.
+func (s spec) response(t *testing.T, op operation, status int) (response, bool) {
+	res, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		res, ok = op.Responses["default"]
+	}
+
+	if ok && res.Ref != "" {
+		name := strings.TrimPrefix(res.Ref, "#/components/responses/")
+		require.NoError(t, json.Unmarshal(s.Components["responses"][name], &res))
+	}
+
+	return res, ok
+}
+
+func newTestHandler(t *testing.T) (*Handler, *http.ServeMux) {
+	users := []config.StorageUserConfig{{Name: "admin", PasswordHash: repositorytest.PasswordHash(t, "admin"), Role: models.RoleAdmin}}
+	services := service.NewServices(repository.NewMemoryRepositories(users, config.SearchConfig{SimilarityThreshold: 0.3}), nil)
+
+	h := NewHandler(&config.Config{}, nil)
+
+	return h, h.Init(services, zerolog.Nop())
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	_, mux := newTestHandler(t)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// sample fills the path parameters of a path of the spec.
+func sample(path string) string {
+	for placeholder, value := range samples {
+		path = strings.ReplaceAll(path, placeholder, value)
+	}
+
+	return path
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	s := loadSpec(t)
+
+	documented := make(map[string][]string)
+	for path, operations := range s.Paths {
+		for method := range operations {
+			documented[path] = append(documented[path], strings.ToUpper(method))
+		}
+		slices.Sort(documented[path])
+	}
+
+	t.Run("every registered pattern is documented", func(t *testing.T) {
+		h, mux := newTestHandler(t)
+
+		reached := make(map[string]bool)
+		for path := range s.Paths {
+			_, pattern := mux.Handler(httptest.NewRequest(http.MethodGet, sample(path), nil))
+			assert.NotEmpty(t, pattern, "%s is not served", path)
+
+			reached[pattern] = true
+		}
+
+		for _, pattern := range h.patterns {
+			assert.True(t, reached[pattern], "no documented path is served by %s", pattern)
+		}
+	})
+
+	t.Run("route tables", func(t *testing.T) {
+		for _, route := range httpv1.Routes() {
+			assert.Equal(t, route.Methods, documented[route.Path], route.Path)
+		}
+	})
+
+	t.Run("documented paths reach their handlers", func(t *testing.T) {
+		server := newTestServer(t)
+		client := server.Client()
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+		for path, operations := range s.Paths {
+			for _, method := range methods {
+				r, err := http.NewRequest(method, server.URL+sample(path), strings.NewReader(`{}`))
+				require.NoError(t, err)
+				r.SetBasicAuth("admin", "admin")
+
+				res, err := client.Do(r)
+				require.NoError(t, err)
+				body, _ := io.ReadAll(res.Body)
+				res.Body.Close()
+
+				op, ok := operations[strings.ToLower(method)]
+				if !ok {
+					assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode, "%s %s is not documented", method, path)
+					continue
+				}
+
+				assert.NotEqual(t, http.StatusMethodNotAllowed, res.StatusCode, "%s %s", method, path)
+				assert.NotContains(t, string(body), `"error":"Not found"`, "%s %s", method, path)
+
+				documented, ok := s.response(t, op, res.StatusCode)
+				if !assert.True(t, ok, "%s %s answered an undocumented %d", method, path, res.StatusCode) || len(documented.Content) == 0 {
+					continue
+				}
+
+				mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+				require.NoError(t, err, "%s %s", method, path)
+				assert.Contains(t, keys((documented.Content)), mediaType, "%s %s %d", method, path, res.StatusCode)
+			}
+		}
+	})
+
+	t.Run("references resolve", func(t *testing.T) {
+		for _, ref := range regexp.MustCompile(`"\$ref": *"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(docs.OpenAPI), -1) {
+			assert.Contains(t, s.Components[ref[1]], ref[2], ref[0])
+		}
+	})
+}
+
+// TestSpecSchemasMatchTypes checks every schema of the spec against the type the handlers use for it:
+// the same properties under the same names, with matching JSON types, down to nested objects.
+func TestSpecSchemasMatchTypes(t *testing.T) {
+	s := loadSpec(t)
+
+	names := make(map[reflect.Type]string)
+	for name, v := range schemaTypes {
+		names[reflect.TypeOf(v)] = name
+	}
+
+	assert.ElementsMatch(t, keys((s.Components["schemas"])), keys((schemaTypes)),
+		"every schema needs a type in schemaTypes")
+
+	for name, raw := range s.Components["schemas"] {
+		v, ok := schemaTypes[name]
+		if !ok {
+			continue
+		}
+
+		var sc schema
+		require.NoError(t, json.Unmarshal(raw, &sc))
+
+		t.Run(name, func(t *testing.T) {
+			checkSchema(t, name, sc, reflect.TypeOf(v), names)
+		})
+	}
+}
+
+func checkSchema(t *testing.T, at string, sc schema, typ reflect.Type, names map[reflect.Type]string) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if sc.Ref != "" {
+		assert.Equal(t, strings.TrimPrefix(sc.Ref, "#/components/schemas/"), names[typ], "%s: %s", at, typ)
+		return
+	}
+
+	kind := jsonType(typ)
+	if sc.Type == "" || kind == "" {
+		return
+	}
+
+	if !assert.Equal(t, sc.Type, kind, "%s: %s", at, typ) {
+		return
+	}
+
+	switch {
+	case kind == "array" && sc.Items != nil:
+		checkSchema(t, at+"[]", *sc.Items, typ.Elem(), names)
+	case kind == "object" && typ.Kind() == reflect.Struct:
+		fields := jsonFields(typ)
+		assert.ElementsMatch(t, keys((sc.Properties)), keys((fields)), "%s: %s", at, typ)
+
+		for property, field := range fields {
+			if nested, ok := sc.Properties[property]; ok {
+				checkSchema(t, at+"."+property, nested, field, names)
+			}
+		}
+	}
+}
+
+// jsonType is the JSON type encoding/json gives values of typ, or "" for interfaces, which can be anything.
+func jsonType(typ reflect.Type) string {
+	switch typ {
+	case reflect.TypeOf(uuid.UUID{}), reflect.TypeOf(time.Time{}):
+		return "string"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+// jsonFields are the exported fields of a struct by the names encoding/json writes them under.
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+
+		fields[name] = field.Type
+	}
+
+	return fields
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+
+	return result
+}
+
+func TestDocs(t *testing.T) {
+	server := newTestServer(t)
+
+	get := func(path string) (*http.Response, string) {
+		res, err := server.Client().Get(server.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return res, string(body)
+	}
+
+	res, body := get("/openapi.json")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.Equal(t, string(docs.OpenAPI), body)
+
+	res, body = get("/docs")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, body, `id="swagger-ui"`)
+
+	_, body = get("/docs/swagger-initializer.js")
+	assert.Contains(t, body, `url: "/openapi.json"`)
+
+	res, _ = get("/docs/swagger-ui-bundle.js")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
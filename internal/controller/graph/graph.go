@@ -0,0 +1,164 @@
+// Package graph serves films, actors, users and search over GraphQL. Queries read through the same
+// services as the REST API and mutations go through service.Films and service.Actors, so
+// validation and authorization stay the same.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"net/http"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/controller/httpv1"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/logger"
+	"vk-test-spring/pkg/requestid"
+)
+
+type Handler struct {
+	schema   graphql.Schema
+	services *service.Services
+	limits   config.GraphQLConfig
+}
+
+// NewHandler builds the schema over services. Limits that are not positive are not enforced.
+func NewHandler(services *service.Services, limits config.GraphQLConfig) *Handler {
+	schema, err := newSchema(services)
+	if err != nil {
+		panic(fmt.Sprintf("graph: invalid schema: %v", err))
+	}
+
+	return &Handler{
+		schema:   schema,
+		services: services,
+		limits:   limits,
+	}
+}
+
+// Request is a query as clients send it: the body of a POST, or the parameters of a GET.
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// ServeHTTP runs a query sent as JSON with POST, or in the query, variables and operationName
+// parameters of a GET. Mutations are only accepted with POST. Requests that cannot run at all are
+// answered with 400; once a query runs the status is 200 and failures are in its errors.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+
+	switch r.Method {
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpv1.WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
+			return
+		}
+	case http.MethodGet:
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if v := params.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				httpv1.WriteError(w, r, "variables must be a JSON object", http.StatusBadRequest)
+				return
+			}
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		httpv1.WriteError(w, r, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if req.Query == "" {
+		h.writeResult(w, r, http.StatusBadRequest, errorsResult(gqlerrors.NewFormattedError("query is empty")))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		h.writeResult(w, r, http.StatusBadRequest, errorsResult(gqlerrors.FormatError(err)))
+		return
+	}
+
+	validation := graphql.ValidateDocument(&h.schema, doc, nil)
+	if !validation.IsValid {
+		h.writeResult(w, r, http.StatusBadRequest, errorsResult(validation.Errors...))
+		return
+	}
+
+	depth, complexity, err := measure(h.schema, doc, req.OperationName, req.Variables)
+	if err != nil {
+		h.writeResult(w, r, http.StatusBadRequest, errorsResult(gqlerrors.FormatError(err)))
+		return
+	}
+	if h.limits.MaxDepth > 0 && depth > h.limits.MaxDepth {
+		h.writeResult(w, r, http.StatusBadRequest, errorsResult(gqlerrors.NewFormattedError(
+			fmt.Sprintf("query depth %d exceeds the limit of %d", depth, h.limits.MaxDepth))))
+		return
+	}
+	if h.limits.MaxComplexity > 0 && complexity > h.limits.MaxComplexity {
+		h.writeResult(w, r, http.StatusBadRequest, errorsResult(gqlerrors.NewFormattedError(
+			fmt.Sprintf("query complexity %d exceeds the limit of %d", complexity, h.limits.MaxComplexity))))
+		return
+	}
+
+	if r.Method == http.MethodGet && isMutation(doc, req.OperationName) {
+		w.Header().Set("Allow", http.MethodPost)
+		h.writeResult(w, r, http.StatusMethodNotAllowed, errorsResult(gqlerrors.NewFormattedError("mutations must be sent with POST")))
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context(), h.services),
+	})
+
+	h.writeResult(w, r, http.StatusOK, result)
+}
+
+func errorsResult(errs ...gqlerrors.FormattedError) *graphql.Result {
+	return &graphql.Result{Errors: errs}
+}
+
+// writeResult writes result with the request id in its extensions when it holds errors. Errors of
+// a query that ran are logged unless they carry a client error status, as httpv1.WriteError logs
+// server errors.
+func (h *Handler) writeResult(w http.ResponseWriter, r *http.Request, code int, result *graphql.Result) {
+	if result.HasErrors() {
+		result.Extensions = map[string]interface{}{"request_id": requestid.FromContext(r.Context())}
+	}
+
+	for _, e := range result.Errors {
+		if status, ok := e.Extensions["status"].(int); code == http.StatusOK && (!ok || status >= http.StatusInternalServerError) {
+			logger.FromContext(r.Context()).Error().Interface("path", e.Path).Msg(e.Message)
+		}
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		httpv1.WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+func isMutation(doc *ast.Document, operationName string) bool {
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && (operationName == "" || op.Name != nil && op.Name.Value == operationName) {
+			return op.Operation == ast.OperationTypeMutation
+		}
+	}
+
+	return false
+}
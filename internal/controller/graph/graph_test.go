@@ -0,0 +1,356 @@
+package graph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/controller"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository"
+	"vk-test-spring/internal/repository/repositorytest"
+	"vk-test-spring/internal/service"
+)
+
+// countingFilms and countingActors count the batched lookups going through them.
+type countingFilms struct {
+	service.Films
+	batches atomic.Int32
+}
+
+func (s *countingFilms) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error) {
+	s.batches.Add(1)
+	return s.Films.GetFilmsByIds(ctx, ids)
+}
+
+type countingActors struct {
+	service.Actors
+	batches atomic.Int32
+}
+
+func (s *countingActors) GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error) {
+	s.batches.Add(1)
+	return s.Actors.GetActorsByIds(ctx, ids)
+}
+
+type fixture struct {
+	server *httptest.Server
+	films  *countingFilms
+	actors *countingActors
+}
+
+func newFixture(t *testing.T, limits config.GraphQLConfig) *fixture {
+	users := []config.StorageUserConfig{
+		{Name: "admin", PasswordHash: repositorytest.PasswordHash(t, "admin"), Role: "администратор"},
+		{Name: "user", PasswordHash: repositorytest.PasswordHash(t, "user"), Role: "пользователь"},
+	}
+	services := service.NewServices(repository.NewMemoryRepositories(users, config.SearchConfig{SimilarityThreshold: 0.3}), nil)
+
+	f := &fixture{
+		films:  &countingFilms{Films: services.Films},
+		actors: &countingActors{Actors: services.Actors},
+	}
+	services.Films = f.films
+	services.Actors = f.actors
+
+	f.server = httptest.NewServer(controller.NewHandler(&config.Config{GraphQL: limits}, nil).Init(services, zerolog.Nop()))
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func (f *fixture) post(t *testing.T, user string, query string, variables map[string]any) (int, response) {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodPost, f.server.URL+"/graphql", bytes.NewReader(body))
+	require.NoError(t, err)
+	r.SetBasicAuth(user, user)
+
+	return f.do(t, r)
+}
+
+func (f *fixture) do(t *testing.T, r *http.Request) (int, response) {
+	t.Helper()
+
+	res, err := f.server.Client().Do(r)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var result response
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+
+	return res.StatusCode, result
+}
+
+// data runs query as admin and decodes its data into v.
+func (f *fixture) data(t *testing.T, query string, variables map[string]any, v any) {
+	t.Helper()
+
+	code, res := f.post(t, "admin", query, variables)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Errors)
+
+	raw, err := json.Marshal(res.Data)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, v))
+}
+
+// seed stores films sharing actors and returns the ids of the films by name.
+func (f *fixture) seed(t *testing.T) map[string]string {
+	ctx := context.WithValue(context.Background(), "role", models.RoleAdmin)
+
+	actors := make([]uuid.UUID, 0, 3)
+	for _, name := range []string{"Бодров", "Сухоруков", "Михалков"} {
+		require.NoError(t, f.actors.AddActor(ctx, service.ActorCreateInput{ActorInfo: service.ActorInfo{
+			Name: "Сергей", SecondName: name, Sex: "Мужчина", DateOfBirth: "1971-12-27",
+		}}))
+
+		found, err := f.actors.GetActorByName(ctx, name)
+		require.NoError(t, err)
+		actors = append(actors, found[0].ID)
+	}
+
+	for i, name := range []string{"Брат", "Брат 2", "Сибирский цирюльник"} {
+		require.NoError(t, f.films.AddNewFilm(ctx, service.FilmCreateInput{
+			FilmInfo: service.FilmInfo{Name: name, Description: name, Date: "1997-12-12", Rating: float64(8 - i)},
+			Actors:   actors[i : i+1+i%2],
+		}))
+	}
+
+	all, err := f.films.GetAllFilms(context.WithValue(context.WithValue(ctx, "sort", "name"), "order", "ASC"))
+	require.NoError(t, err)
+
+	ids := make(map[string]string)
+	for _, film := range all {
+		ids[film.Name] = film.ID.String()
+	}
+
+	return ids
+}
+
+func TestQuery(t *testing.T) {
+	f := newFixture(t, config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 1000})
+	ids := f.seed(t)
+
+	t.Run("film with its cast and their films", func(t *testing.T) {
+		var data struct {
+			Film struct {
+				Name   string
+				Actors []struct {
+					SecondName string
+					Films      []struct{ Name string }
+				}
+			}
+		}
+		f.data(t, `query($id: ID!) { film(id: $id) { name actors { secondName films { name } } } }`, map[string]any{"id": ids["Брат 2"]}, &data)
+
+		assert.Equal(t, "Брат 2", data.Film.Name)
+		require.Len(t, data.Film.Actors, 2)
+		assert.Equal(t, "Сухоруков", data.Film.Actors[0].SecondName)
+		assert.Equal(t, []struct{ Name string }{{"Брат 2"}}, data.Film.Actors[0].Films)
+		assert.Equal(t, "Михалков", data.Film.Actors[1].SecondName)
+		assert.Equal(t, []struct{ Name string }{{"Брат 2"}, {"Сибирский цирюльник"}}, data.Film.Actors[1].Films)
+	})
+
+	t.Run("films are sorted", func(t *testing.T) {
+		var data struct{ Films []struct{ Name string } }
+
+		f.data(t, `{ films { name } }`, nil, &data)
+		assert.Equal(t, []struct{ Name string }{{"Брат"}, {"Брат 2"}, {"Сибирский цирюльник"}}, data.Films)
+
+		f.data(t, `{ films(sort: NAME, order: DESC) { name } }`, nil, &data)
+		assert.Equal(t, []struct{ Name string }{{"Сибирский цирюльник"}, {"Брат 2"}, {"Брат"}}, data.Films)
+	})
+
+	t.Run("lists are paged", func(t *testing.T) {
+		var data struct {
+			Films []struct {
+				Name   string
+				Actors []struct{ SecondName string }
+			}
+		}
+
+		f.data(t, `{ films(sort: NAME, order: ASC, limit: 1, offset: 1) { name actors(limit: 1, offset: 1) { secondName } } }`, nil, &data)
+		require.Len(t, data.Films, 1)
+		assert.Equal(t, "Брат 2", data.Films[0].Name)
+		assert.Equal(t, []struct{ SecondName string }{{"Михалков"}}, data.Films[0].Actors)
+
+		code, res := f.post(t, "admin", `{ films(limit: 101) { name } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, float64(http.StatusBadRequest), res.Errors[0].Extensions["status"])
+	})
+
+	t.Run("missing film is null", func(t *testing.T) {
+		var data struct{ Film *struct{ Name string } }
+		f.data(t, `query($id: ID!) { film(id: $id) { name } }`, map[string]any{"id": uuid.NewString()}, &data)
+		assert.Nil(t, data.Film)
+	})
+
+	t.Run("me", func(t *testing.T) {
+		code, res := f.post(t, "user", `{ me { role } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, `{"role": "пользователь"}`, string(res.Data["me"]))
+	})
+
+	t.Run("service errors keep their status", func(t *testing.T) {
+		code, res := f.post(t, "admin", `{ search(query: "брат") { total } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, float64(http.StatusNotImplemented), res.Errors[0].Extensions["status"])
+	})
+}
+
+func TestBatching(t *testing.T) {
+	f := newFixture(t, config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 20000})
+	f.seed(t)
+
+	var data struct {
+		Films []struct {
+			Actors []struct {
+				Films []struct {
+					Actors []struct{ SecondName string }
+				}
+			}
+		}
+	}
+	f.data(t, `{ films(limit: 5) { actors(limit: 5) { films(limit: 5) { actors(limit: 5) { secondName } } } } }`, nil, &data)
+
+	require.Len(t, data.Films, 3)
+	assert.Len(t, data.Films[1].Actors[1].Films[0].Actors, 2)
+	// The actors of the third level were all loaded on the first one and are not asked for again.
+	assert.Equal(t, int32(1), f.actors.batches.Load())
+	assert.Equal(t, int32(1), f.films.batches.Load())
+}
+
+func TestLimits(t *testing.T) {
+	f := newFixture(t, config.GraphQLConfig{MaxDepth: 3, MaxComplexity: 50})
+
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{
+			name:    "depth",
+			query:   `{ films { actors { films { name } } } }`,
+			message: "query depth 4 exceeds the limit of 3",
+		},
+		{
+			name:    "depth through fragments",
+			query:   `{ films { ...cast } } fragment cast on Film { actors { films { name } } }`,
+			message: "query depth 4 exceeds the limit of 3",
+		},
+		{
+			name:    "complexity of nested lists",
+			query:   `{ films { name actors { name } } }`,
+			message: "query complexity 441 exceeds the limit of 50",
+		},
+		{
+			name:    "complexity by limit",
+			query:   `query($limit: Int) { search(query: "брат", limit: $limit) { items { id title } } }`,
+			message: "query complexity 202 exceeds the limit of 50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, res := f.post(t, "admin", tt.query, map[string]any{"limit": 100})
+			assert.Equal(t, http.StatusBadRequest, code)
+			require.Len(t, res.Errors, 1)
+			assert.Equal(t, tt.message, res.Errors[0].Message)
+		})
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		code, res := f.post(t, "admin", `{ films { name } __schema { types { name fields { name } } } }`, nil)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, res.Errors)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		code, res := f.post(t, "admin", `{ films { title } }`, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.NotEmpty(t, res.Errors)
+	})
+}
+
+func TestMutations(t *testing.T) {
+	f := newFixture(t, config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 1000})
+	ids := f.seed(t)
+
+	const createActor = `mutation($input: ActorInput!) { createActor(input: $input) }`
+	actor := map[string]any{"name": "Виктор", "secondName": "Цой", "sex": "Мужчина", "dateOfBirth": "1962-06-21", "films": []string{ids["Брат"]}}
+
+	t.Run("admin", func(t *testing.T) {
+		var created struct{ CreateActor bool }
+		f.data(t, createActor, map[string]any{"input": actor}, &created)
+		assert.True(t, created.CreateActor)
+
+		var updated struct {
+			UpdateFilm struct {
+				Rating float64
+				Actors []struct{ SecondName string }
+			}
+		}
+		f.data(t, `mutation($id: ID!) { updateFilm(id: $id, input: {rating: 9.5}) { rating actors { secondName } } }`,
+			map[string]any{"id": ids["Брат"]}, &updated)
+		assert.Equal(t, 9.5, updated.UpdateFilm.Rating)
+		assert.Equal(t, []struct{ SecondName string }{{"Бодров"}, {"Цой"}}, updated.UpdateFilm.Actors)
+	})
+
+	t.Run("user is forbidden", func(t *testing.T) {
+		code, res := f.post(t, "user", `mutation($id: ID!) { deleteFilm(id: $id) }`, map[string]any{"id": ids["Брат"]})
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, "Forbidden", res.Errors[0].Message)
+		assert.Equal(t, float64(http.StatusForbidden), res.Errors[0].Extensions["status"])
+
+		_, err := f.films.GetFilmById(context.Background(), uuid.MustParse(ids["Брат"]))
+		assert.NoError(t, err)
+	})
+
+	t.Run("service validation", func(t *testing.T) {
+		invalid := map[string]any{"name": "Виктор", "secondName": "Цой", "sex": "кот", "dateOfBirth": "1962-06-21"}
+
+		code, res := f.post(t, "admin", createActor, map[string]any{"input": invalid})
+		assert.Equal(t, http.StatusOK, code)
+		require.Len(t, res.Errors, 1)
+		assert.Equal(t, float64(http.StatusBadRequest), res.Errors[0].Extensions["status"])
+	})
+
+	t.Run("not with GET", func(t *testing.T) {
+		params := url.Values{"query": {`mutation { deleteFilm(id: "` + ids["Брат"] + `") }`}}
+		r, err := http.NewRequest(http.MethodGet, f.server.URL+"/graphql?"+params.Encode(), nil)
+		require.NoError(t, err)
+		r.SetBasicAuth("admin", "admin")
+
+		code, _ := f.do(t, r)
+		assert.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		code, _ := f.post(t, "intruder", `{ films { name } }`, nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
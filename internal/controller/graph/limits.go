@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"strconv"
+	"strings"
+)
+
+// unlimitedListSize is the number of elements a list is charged at when neither it nor the field
+// returning it has a limit: the most any list of the schema returns.
+const unlimitedListSize = maxPageSize
+
+// cost walks the selections of one operation. It works on a validated document, so every field
+// exists on its parent type and fragments do not cycle.
+type cost struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// measure returns the depth and the complexity of the operation. Every field costs 1 and the
+// fields under a list count once per element; the number of elements is the limit argument of the
+// list or of the field returning it, its default value, or unlimitedListSize. Introspection fields are free.
+func measure(schema graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (depth int, complexity int, err error) {
+	c := cost{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || def.Name != nil && def.Name.Value == operationName {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		return 0, 0, fmt.Errorf("unknown operation %q", operationName)
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	depth, complexity = c.selections(root, operation.SelectionSet, 0)
+
+	return depth, complexity, nil
+}
+
+// selections returns the depth and the cost of one element of parent with the given selections.
+// limit is the limit argument of the field the selections belong to, or 0.
+func (c cost) selections(parent *graphql.Object, set *ast.SelectionSet, limit int) (depth int, complexity int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, n int
+
+		switch s := selection.(type) {
+		case *ast.Field:
+			d, n = c.field(parent, s, limit)
+		case *ast.InlineFragment:
+			d, n = c.selections(c.fragmentType(parent, s.TypeCondition), s.SelectionSet, limit)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[s.Name.Value]; ok {
+				d, n = c.selections(c.fragmentType(parent, fragment.TypeCondition), fragment.SelectionSet, limit)
+			}
+		}
+
+		depth = max(depth, d)
+		complexity += n
+	}
+
+	return depth, complexity
+}
+
+func (c cost) field(parent *graphql.Object, f *ast.Field, limit int) (depth int, complexity int) {
+	if strings.HasPrefix(f.Name.Value, "__") {
+		return 0, 0
+	}
+
+	def, ok := parent.Fields()[f.Name.Value]
+	if !ok {
+		return 0, 0
+	}
+
+	if n := c.limit(def, f.Arguments); n > 0 {
+		limit = n
+	}
+
+	typ := def.Type
+	if nonNull, ok := typ.(*graphql.NonNull); ok {
+		typ = nonNull.OfType
+	}
+
+	elements := 1
+	if list, ok := typ.(*graphql.List); ok {
+		elements = unlimitedListSize
+		if limit > 0 {
+			elements = limit
+		}
+		limit = 0
+
+		typ = list.OfType
+		if nonNull, ok := typ.(*graphql.NonNull); ok {
+			typ = nonNull.OfType
+		}
+	}
+
+	object, _ := typ.(*graphql.Object)
+	depth, complexity = c.selections(object, f.SelectionSet, limit)
+
+	return depth + 1, 1 + elements*complexity
+}
+
+func (c cost) fragmentType(parent *graphql.Object, condition *ast.Named) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+
+	object, _ := c.schema.Type(condition.Name.Value).(*graphql.Object)
+	return object
+}
+
+// limit returns the value of the limit argument of def given in arguments or its default value,
+// or 0 when def has no limit.
+func (c cost) limit(def *graphql.FieldDefinition, arguments []*ast.Argument) int {
+	for _, arg := range arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			n, _ := strconv.Atoi(v.Value)
+			return n
+		case *ast.Variable:
+			switch n := c.variables[v.Name.Value].(type) {
+			case float64:
+				return int(n)
+			case int:
+				return n
+			}
+		}
+	}
+
+	for _, arg := range def.Args {
+		if n, ok := arg.DefaultValue.(int); ok && arg.Name() == "limit" {
+			return n
+		}
+	}
+
+	return 0
+}
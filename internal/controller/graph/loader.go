@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"sync"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+)
+
+// loader batches the lookups by id of one request. Resolvers ask for ids with load and get a thunk
+// back; graphql-go calls the thunks of a level of the query only after every resolver of that level
+// has run, so the first thunk fetches the ids of the whole level with one call.
+type loader[T any] struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, ids []uuid.UUID) ([]T, error)
+	id      func(T) uuid.UUID
+	pending []uuid.UUID
+	loaded  map[uuid.UUID]T
+	failed  map[uuid.UUID]error
+}
+
+func newLoader[T any](fetch func(ctx context.Context, ids []uuid.UUID) ([]T, error), id func(T) uuid.UUID) *loader[T] {
+	return &loader[T]{
+		fetch:  fetch,
+		id:     id,
+		loaded: make(map[uuid.UUID]T),
+		failed: make(map[uuid.UUID]error),
+	}
+}
+
+// load queues ids and returns a thunk resolving to the values found for them, in the order of ids.
+// Ids that do not exist any more are skipped.
+func (l *loader[T]) load(ctx context.Context, ids []uuid.UUID) func() (interface{}, error) {
+	l.mu.Lock()
+	for _, id := range ids {
+		if !l.known(id) {
+			l.pending = append(l.pending, id)
+		}
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			l.flush(ctx)
+		}
+
+		values := make([]T, 0, len(ids))
+		for _, id := range ids {
+			if err, ok := l.failed[id]; ok {
+				return nil, err
+			}
+			if v, ok := l.loaded[id]; ok {
+				values = append(values, v)
+			}
+		}
+
+		return values, nil
+	}
+}
+
+func (l *loader[T]) known(id uuid.UUID) bool {
+	_, loaded := l.loaded[id]
+	_, failed := l.failed[id]
+
+	return loaded || failed
+}
+
+func (l *loader[T]) flush(ctx context.Context) {
+	ids := make([]uuid.UUID, 0, len(l.pending))
+	seen := make(map[uuid.UUID]bool, len(l.pending))
+	for _, id := range l.pending {
+		if !seen[id] && !l.known(id) {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	l.pending = nil
+
+	values, err := l.fetch(ctx, ids)
+	if err != nil {
+		for _, id := range ids {
+			l.failed[id] = err
+		}
+		return
+	}
+
+	for _, v := range values {
+		l.loaded[l.id(v)] = v
+	}
+}
+
+type loaders struct {
+	films  *loader[models.Film]
+	actors *loader[models.Actor]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, services *service.Services) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		films:  newLoader(services.Films.GetFilmsByIds, func(f models.Film) uuid.UUID { return f.ID }),
+		actors: newLoader(services.Actors.GetActorsByIds, func(a models.Actor) uuid.UUID { return a.ID }),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
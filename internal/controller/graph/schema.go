@@ -0,0 +1,624 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"net/http"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+)
+
+// field resolves a field of a T source with get.
+func field[T any](typ graphql.Output, get func(T) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(T)), nil
+		},
+	}
+}
+
+func nonNullList(typ graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(typ)))
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageArgs adds the limit and offset arguments of a list field to args. Every list of films,
+// actors or user lists takes them, so the complexity of a query is known before it runs.
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	if args == nil {
+		args = graphql.FieldConfigArgument{}
+	}
+
+	args["limit"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize}
+	args["offset"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0}
+
+	return args
+}
+
+// page returns the part of items the limit and offset arguments ask for.
+func page[T any](args map[string]interface{}, items []T) ([]T, error) {
+	limit, offset := intArg(args, "limit"), intArg(args, "offset")
+
+	switch {
+	case limit < 1 || limit > maxPageSize:
+		return nil, Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("limit must be between 1 and %d", maxPageSize)}
+	case offset < 0:
+		return nil, Error{Status: http.StatusBadRequest, Message: "offset must not be negative"}
+	}
+
+	items = items[min(offset, len(items)):]
+	return items[:min(limit, len(items))], nil
+}
+
+var (
+	filmSortEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name: "FilmSort",
+		Values: graphql.EnumValueConfigMap{
+			"NAME":   {Value: "name"},
+			"DATE":   {Value: "date"},
+			"RATING": {Value: "rating"},
+		},
+	})
+	sortOrderEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name: "SortOrder",
+		Values: graphql.EnumValueConfigMap{
+			"ASC":  {Value: "ASC"},
+			"DESC": {Value: "DESC"},
+		},
+	})
+	suggestTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+		Name: "SuggestType",
+		Values: graphql.EnumValueConfigMap{
+			"FILMS":  {Value: service.SuggestTypeFilms},
+			"ACTORS": {Value: service.SuggestTypeActors},
+		},
+	})
+
+	searchResultType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.Fields{
+			"type":     field(graphql.NewNonNull(graphql.String), func(r models.SearchResult) interface{} { return r.Type }),
+			"id":       field(graphql.NewNonNull(graphql.ID), func(r models.SearchResult) interface{} { return r.ID.String() }),
+			"title":    field(graphql.NewNonNull(graphql.String), func(r models.SearchResult) interface{} { return r.Title }),
+			"headline": field(graphql.NewNonNull(graphql.String), func(r models.SearchResult) interface{} { return r.Headline }),
+			"rank":     field(graphql.NewNonNull(graphql.Float), func(r models.SearchResult) interface{} { return r.Rank }),
+		},
+	})
+	searchPageType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchPage",
+		Fields: graphql.Fields{
+			"items": field(nonNullList(searchResultType), func(p models.SearchPage) interface{} { return p.Items }),
+			"total": field(graphql.NewNonNull(graphql.Int), func(p models.SearchPage) interface{} { return p.Total }),
+			"page":  field(graphql.NewNonNull(graphql.Int), func(p models.SearchPage) interface{} { return p.Page }),
+			"limit": field(graphql.NewNonNull(graphql.Int), func(p models.SearchPage) interface{} { return p.Limit }),
+		},
+	})
+	suggestionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Suggestion",
+		Fields: graphql.Fields{
+			"id":   field(graphql.NewNonNull(graphql.ID), func(s models.Suggestion) interface{} { return s.ID.String() }),
+			"name": field(graphql.NewNonNull(graphql.String), func(s models.Suggestion) interface{} { return s.Name }),
+			"year": field(graphql.NewNonNull(graphql.Int), func(s models.Suggestion) interface{} { return s.Year }),
+		},
+	})
+	listType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "List",
+		Fields: graphql.Fields{
+			"id":         field(graphql.NewNonNull(graphql.ID), func(l models.UserList) interface{} { return l.ID.String() }),
+			"name":       field(graphql.NewNonNull(graphql.String), func(l models.UserList) interface{} { return l.Name }),
+			"kind":       field(graphql.NewNonNull(graphql.String), func(l models.UserList) interface{} { return l.Kind }),
+			"public":     field(graphql.NewNonNull(graphql.Boolean), func(l models.UserList) interface{} { return l.Public }),
+			"shareToken": field(graphql.String, func(l models.UserList) interface{} { return nullable(l.ShareToken) }),
+			"createdAt":  field(graphql.NewNonNull(graphql.String), func(l models.UserList) interface{} { return l.CreatedAt }),
+			"filmsCount": field(graphql.NewNonNull(graphql.Int), func(l models.UserList) interface{} { return l.FilmsCount }),
+		},
+	})
+
+	filmInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "FilmInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.NewNonNull(graphql.String)},
+			"date":        {Type: graphql.NewNonNull(graphql.String)},
+			"rating":      {Type: graphql.NewNonNull(graphql.Float)},
+			"actors":      {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		},
+	})
+	filmUpdateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "FilmUpdateInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.String},
+			"description": {Type: graphql.String},
+			"date":        {Type: graphql.String},
+			"rating":      {Type: graphql.Float},
+			"actorsToAdd": {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+			"actorsToDel": {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		},
+	})
+	actorInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ActorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"secondName":  {Type: graphql.NewNonNull(graphql.String)},
+			"patronymic":  {Type: graphql.String},
+			"sex":         {Type: graphql.NewNonNull(graphql.String)},
+			"dateOfBirth": {Type: graphql.NewNonNull(graphql.String)},
+			"films":       {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		},
+	})
+	actorUpdateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ActorUpdateInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.String},
+			"secondName":  {Type: graphql.String},
+			"patronymic":  {Type: graphql.String},
+			"sex":         {Type: graphql.String},
+			"dateOfBirth": {Type: graphql.String},
+			"filmsToAdd":  {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+			"filmsToDel":  {Type: graphql.NewList(graphql.NewNonNull(graphql.ID))},
+		},
+	})
+)
+
+// newSchema builds the schema over services. Film.actors and Actor.films go through the loaders of
+// the request, so nesting them costs one call per level of the query rather than one per film.
+func newSchema(services *service.Services) (graphql.Schema, error) {
+	filmType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Film",
+		Fields: graphql.Fields{
+			"id":          field(graphql.NewNonNull(graphql.ID), func(f models.Film) interface{} { return f.ID.String() }),
+			"name":        field(graphql.NewNonNull(graphql.String), func(f models.Film) interface{} { return f.Name }),
+			"description": field(graphql.NewNonNull(graphql.String), func(f models.Film) interface{} { return f.Description }),
+			"date":        field(graphql.NewNonNull(graphql.String), func(f models.Film) interface{} { return f.Date }),
+			"rating":      field(graphql.NewNonNull(graphql.Float), func(f models.Film) interface{} { return f.Rating }),
+			"updatedAt":   field(graphql.NewNonNull(graphql.String), func(f models.Film) interface{} { return f.UpdatedAt.Format(time.RFC3339) }),
+		},
+	})
+	actorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Actor",
+		Fields: graphql.Fields{
+			"id":          field(graphql.NewNonNull(graphql.ID), func(a models.Actor) interface{} { return a.ID.String() }),
+			"name":        field(graphql.NewNonNull(graphql.String), func(a models.Actor) interface{} { return a.Name }),
+			"secondName":  field(graphql.NewNonNull(graphql.String), func(a models.Actor) interface{} { return a.SecondName }),
+			"patronymic":  field(graphql.NewNonNull(graphql.String), func(a models.Actor) interface{} { return a.Patronymic }),
+			"sex":         field(graphql.NewNonNull(graphql.String), func(a models.Actor) interface{} { return a.Sex }),
+			"dateOfBirth": field(graphql.NewNonNull(graphql.String), func(a models.Actor) interface{} { return a.DateOfBirth }),
+			"updatedAt":   field(graphql.NewNonNull(graphql.String), func(a models.Actor) interface{} { return a.UpdatedAt.Format(time.RFC3339) }),
+		},
+	})
+
+	filmType.AddFieldConfig("actors", &graphql.Field{
+		Type: nonNullList(actorType),
+		Args: pageArgs(nil),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			cast, err := page(p.Args, p.Source.(models.Film).Actors)
+			if err != nil {
+				return nil, err
+			}
+
+			ids := make([]uuid.UUID, 0, len(cast))
+			for _, a := range cast {
+				ids = append(ids, a.ID)
+			}
+
+			return loadersFrom(p.Context).actors.load(p.Context, ids), nil
+		},
+	})
+	actorType.AddFieldConfig("films", &graphql.Field{
+		Type: nonNullList(filmType),
+		Args: pageArgs(nil),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			films, err := page(p.Args, p.Source.(models.Actor).Films)
+			if err != nil {
+				return nil, err
+			}
+
+			ids := make([]uuid.UUID, 0, len(films))
+			for _, f := range films {
+				ids = append(ids, f.ID)
+			}
+
+			return loadersFrom(p.Context).films.load(p.Context, ids), nil
+		},
+	})
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":   field(graphql.NewNonNull(graphql.ID), func(u models.User) interface{} { return u.ID.String() }),
+			"role": field(graphql.NewNonNull(graphql.String), func(u models.User) interface{} { return u.Role }),
+			"lists": &graphql.Field{
+				Type: nonNullList(listType),
+				Args: pageArgs(nil),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					lists, err := services.Lists.GetUserLists(p.Context, p.Source.(models.User).ID.String())
+					if err != nil {
+						return nil, resolveError(err)
+					}
+
+					return page(p.Args, lists)
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"films": &graphql.Field{
+				Type: nonNullList(filmType),
+				Args: pageArgs(graphql.FieldConfigArgument{
+					"sort":      {Type: filmSortEnum, DefaultValue: "rating"},
+					"order":     {Type: sortOrderEnum, DefaultValue: "DESC"},
+					"name":      {Type: graphql.String},
+					"actorName": {Type: graphql.String},
+				}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var films []models.Film
+					var err error
+
+					switch {
+					case p.Args["name"] != nil:
+						films, err = services.Films.GetAllFilmsByName(p.Context, p.Args["name"].(string))
+					case p.Args["actorName"] != nil:
+						films, err = services.Films.GetAllFilmsByActor(p.Context, p.Args["actorName"].(string))
+					default:
+						ctx := context.WithValue(p.Context, "sort", p.Args["sort"])
+						ctx = context.WithValue(ctx, "order", p.Args["order"])
+						films, err = services.Films.GetAllFilms(ctx)
+					}
+					if err != nil {
+						return nil, resolveError(err)
+					}
+
+					return page(p.Args, films)
+				},
+			},
+			"film": &graphql.Field{
+				Type: filmType,
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					film, err := services.Films.GetFilmById(p.Context, id)
+					return orNil(film, err)
+				},
+			},
+			"actors": &graphql.Field{
+				Type: nonNullList(actorType),
+				Args: pageArgs(graphql.FieldConfigArgument{"name": {Type: graphql.String}}),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					var actors []models.Actor
+					var err error
+
+					if name, ok := p.Args["name"].(string); ok {
+						actors, err = services.Actors.GetActorByName(p.Context, name)
+					} else {
+						actors, err = services.Actors.GetAllActors(p.Context)
+					}
+					if err != nil {
+						return nil, resolveError(err)
+					}
+
+					return page(p.Args, actors)
+				},
+			},
+			"actor": &graphql.Field{
+				Type: actorType,
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					actor, err := services.Actors.GetActorById(p.Context, id)
+					return orNil(actor, err)
+				},
+			},
+			"search": &graphql.Field{
+				Type: graphql.NewNonNull(searchPageType),
+				Args: graphql.FieldConfigArgument{
+					"query": {Type: graphql.NewNonNull(graphql.String)},
+					"page":  {Type: graphql.Int},
+					"limit": {Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, err := services.Search.Search(p.Context, service.SearchInput{
+						Query: p.Args["query"].(string),
+						Page:  intArg(p.Args, "page"),
+						Limit: intArg(p.Args, "limit"),
+					})
+					return page, resolveError(err)
+				},
+			},
+			"suggest": &graphql.Field{
+				Type: nonNullList(suggestionType),
+				Args: graphql.FieldConfigArgument{
+					"query": {Type: graphql.NewNonNull(graphql.String)},
+					"type":  {Type: graphql.NewNonNull(suggestTypeEnum)},
+					"limit": {Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					suggestions, err := services.Search.Suggest(p.Context, service.SuggestInput{
+						Query: p.Args["query"].(string),
+						Type:  p.Args["type"].(string),
+						Limit: intArg(p.Args, "limit"),
+					})
+					return suggestions, resolveError(err)
+				},
+			},
+			"me": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					userId, _ := p.Context.Value("user_id").(string)
+					role, _ := p.Context.Value("role").(string)
+
+					id, err := uuid.Parse(userId)
+					if err != nil {
+						return nil, Error{Status: http.StatusUnauthorized, Message: "Unauthorized"}
+					}
+
+					return models.User{ID: id, Role: role}, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createFilm": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(filmInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					in := p.Args["input"].(map[string]interface{})
+
+					actors, err := idsArg(in, "actors")
+					if err != nil {
+						return nil, err
+					}
+
+					err = services.Films.AddNewFilm(p.Context, service.FilmCreateInput{
+						FilmInfo: filmInfo(in),
+						Actors:   actors,
+					})
+					return err == nil, resolveError(err)
+				},
+			},
+			"updateFilm": &graphql.Field{
+				Type: filmType,
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(filmUpdateInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					in := p.Args["input"].(map[string]interface{})
+
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					toAdd, err := idsArg(in, "actorsToAdd")
+					if err != nil {
+						return nil, err
+					}
+					toDel, err := idsArg(in, "actorsToDel")
+					if err != nil {
+						return nil, err
+					}
+
+					err = services.Films.EditFilm(p.Context, service.FilmUpdateInput{
+						ID:          id,
+						FilmInfo:    filmInfo(in),
+						ActorsToAdd: toAdd,
+						ActorsToDel: toDel,
+					})
+					if err != nil {
+						return nil, resolveError(err)
+					}
+
+					film, err := services.Films.GetFilmById(p.Context, id)
+					return orNil(film, err)
+				},
+			},
+			"deleteFilm": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					err = services.Films.DeleteFilm(p.Context, id)
+					return err == nil, resolveError(err)
+				},
+			},
+			"createActor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(actorInput)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					in := p.Args["input"].(map[string]interface{})
+
+					films, err := idsArg(in, "films")
+					if err != nil {
+						return nil, err
+					}
+
+					err = services.Actors.AddActor(p.Context, service.ActorCreateInput{
+						ActorInfo: actorInfo(in),
+						Films:     films,
+					})
+					return err == nil, resolveError(err)
+				},
+			},
+			"updateActor": &graphql.Field{
+				Type: actorType,
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(actorUpdateInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					in := p.Args["input"].(map[string]interface{})
+
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					toAdd, err := idsArg(in, "filmsToAdd")
+					if err != nil {
+						return nil, err
+					}
+					toDel, err := idsArg(in, "filmsToDel")
+					if err != nil {
+						return nil, err
+					}
+
+					err = services.Actors.UpdateActor(p.Context, service.ActorUpdateInput{
+						ID:         id,
+						ActorInfo:  actorInfo(in),
+						FilmsToAdd: toAdd,
+						FilmsToDel: toDel,
+					})
+					if err != nil {
+						return nil, resolveError(err)
+					}
+
+					actor, err := services.Actors.GetActorById(p.Context, id)
+					return orNil(actor, err)
+				},
+			},
+			"deleteActor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					err = services.Actors.DeleteActor(p.Context, id)
+					return err == nil, resolveError(err)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// Error is a resolver error. Its status is the HTTP status the REST API answers the same failure
+// with and shows up in the extensions of the error in the response.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+func (e Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"status": e.Status}
+}
+
+func resolveError(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case models.CustomError:
+		return Error{Status: e.Code, Message: e.Message}
+	default:
+		return Error{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+}
+
+// orNil resolves a missing film or actor to null instead of an error.
+func orNil[T any](v T, err error) (interface{}, error) {
+	if e, ok := err.(models.CustomError); ok && e.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolveError(err)
+	}
+
+	return v, nil
+}
+
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+func idArg(args map[string]interface{}, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(args[name].(string))
+	if err != nil {
+		return uuid.Nil, Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("%s must be a UUID", name)}
+	}
+
+	return id, nil
+}
+
+func idsArg(args map[string]interface{}, name string) ([]uuid.UUID, error) {
+	values, _ := args[name].([]interface{})
+
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v.(string))
+		if err != nil {
+			return nil, Error{Status: http.StatusBadRequest, Message: fmt.Sprintf("%s must hold UUIDs", name)}
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func intArg(args map[string]interface{}, name string) int {
+	n, _ := args[name].(int)
+	return n
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func filmInfo(in map[string]interface{}) service.FilmInfo {
+	rating, _ := in["rating"].(float64)
+
+	return service.FilmInfo{
+		Name:        stringArg(in, "name"),
+		Description: stringArg(in, "description"),
+		Date:        stringArg(in, "date"),
+		Rating:      rating,
+	}
+}
+
+func actorInfo(in map[string]interface{}) service.ActorInfo {
+	return service.ActorInfo{
+		Name:        stringArg(in, "name"),
+		SecondName:  stringArg(in, "secondName"),
+		Patronymic:  stringArg(in, "patronymic"),
+		Sex:         stringArg(in, "sex"),
+		DateOfBirth: stringArg(in, "dateOfBirth"),
+	}
+}
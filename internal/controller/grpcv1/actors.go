@@ -0,0 +1,142 @@
+package grpcv1
+
+import (
+	"context"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/api/filmsv1"
+)
+
+type actorsServer struct {
+	filmsv1.UnimplementedActorsServiceServer
+	actors service.Actors
+}
+
+func (s *actorsServer) CreateActor(ctx context.Context, req *filmsv1.CreateActorRequest) (*emptypb.Empty, error) {
+	films, err := parseIds("film_ids", req.GetFilmIds())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.actors.AddActor(ctx, service.ActorCreateInput{
+		ActorInfo: service.ActorInfo{
+			Name:        req.GetName(),
+			SecondName:  req.GetSecondName(),
+			Patronymic:  req.GetPatronymic(),
+			Sex:         req.GetSex(),
+			DateOfBirth: req.GetDateOfBirth(),
+		},
+		Films: films,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *actorsServer) UpdateActor(ctx context.Context, req *filmsv1.UpdateActorRequest) (*filmsv1.Actor, error) {
+	id, err := parseId("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	toAdd, err := parseIds("films_to_add", req.GetFilmsToAdd())
+	if err != nil {
+		return nil, err
+	}
+	toDel, err := parseIds("films_to_del", req.GetFilmsToDel())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.actors.UpdateActor(ctx, service.ActorUpdateInput{
+		ID: id,
+		ActorInfo: service.ActorInfo{
+			Name:        req.GetName(),
+			SecondName:  req.GetSecondName(),
+			Patronymic:  req.GetPatronymic(),
+			Sex:         req.GetSex(),
+			DateOfBirth: req.GetDateOfBirth(),
+		},
+		FilmsToAdd: toAdd,
+		FilmsToDel: toDel,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	actor, err := s.actors.GetActorById(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toActor(actor), nil
+}
+
+func (s *actorsServer) DeleteActor(ctx context.Context, req *filmsv1.DeleteActorRequest) (*emptypb.Empty, error) {
+	id, err := parseId("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.actors.DeleteActor(ctx, id); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *actorsServer) GetActor(ctx context.Context, req *filmsv1.GetActorRequest) (*filmsv1.Actor, error) {
+	id, err := parseId("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := s.actors.GetActorById(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toActor(actor), nil
+}
+
+func (s *actorsServer) ListActors(req *filmsv1.ListActorsRequest, stream filmsv1.ActorsService_ListActorsServer) error {
+	var actors []models.Actor
+	var err error
+
+	if req.GetName() != "" {
+		actors, err = s.actors.GetActorByName(stream.Context(), req.GetName())
+	} else {
+		actors, err = s.actors.GetAllActors(stream.Context())
+	}
+	if err != nil {
+		return statusError(err)
+	}
+
+	for _, actor := range actors {
+		if err := stream.Send(toActor(actor)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toActor(actor models.Actor) *filmsv1.Actor {
+	films := make([]*filmsv1.ActorFilm, 0, len(actor.Films))
+	for _, f := range actor.Films {
+		films = append(films, &filmsv1.ActorFilm{Id: f.ID.String(), Name: f.Name})
+	}
+
+	return &filmsv1.Actor{
+		Id:          actor.ID.String(),
+		Name:        actor.Name,
+		SecondName:  actor.SecondName,
+		Patronymic:  actor.Patronymic,
+		Sex:         actor.Sex,
+		DateOfBirth: actor.DateOfBirth,
+		Films:       films,
+		UpdatedAt:   timestamp(actor.UpdatedAt),
+	}
+}
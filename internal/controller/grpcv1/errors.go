@@ -0,0 +1,76 @@
+package grpcv1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"vk-test-spring/internal/models"
+)
+
+// codesByStatus maps the HTTP statuses of models.CustomError to the gRPC codes with the same meaning.
+var codesByStatus = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusPreconditionFailed:  codes.FailedPrecondition,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusNotImplemented:      codes.Unimplemented,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// statusError turns a service error into a gRPC status. Errors that are not models.CustomError are
+// server errors; context errors keep their own codes.
+func statusError(err error) error {
+	var customErr models.CustomError
+	switch {
+	case errors.As(err, &customErr):
+		code, ok := codesByStatus[customErr.Code]
+		if !ok {
+			code = codes.Unknown
+		}
+		return status.Error(code, customErr.Message)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+
+	return false
+}
+
+func parseId(name string, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "%s must be a UUID", name)
+	}
+
+	return id, nil
+}
+
+func parseIds(name string, values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%s must hold UUIDs", name))
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
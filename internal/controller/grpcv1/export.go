@@ -0,0 +1,56 @@
+package grpcv1
+
+import (
+	"context"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/api/filmsv1"
+)
+
+type exportServer struct {
+	filmsv1.UnimplementedExportServiceServer
+	films  service.Films
+	actors service.Actors
+}
+
+// Export sends the actors first, so a consumer importing the stream knows every actor of a film
+// by the time the film arrives. Films come by name.
+func (s *exportServer) Export(_ *filmsv1.ExportRequest, stream filmsv1.ExportService_ExportServer) error {
+	actors, err := s.actors.GetAllActors(stream.Context())
+	if err != nil {
+		return statusError(err)
+	}
+
+	for _, actor := range actors {
+		record := &filmsv1.ExportRecord{Record: &filmsv1.ExportRecord_Actor{Actor: toActor(actor)}}
+		if err := stream.Send(record); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.WithValue(stream.Context(), "sort", "name")
+	ctx = context.WithValue(ctx, "order", "asc")
+
+	films, err := s.films.GetAllFilms(ctx)
+	if err != nil {
+		return statusError(err)
+	}
+
+	for _, film := range films {
+		record := &filmsv1.ExportRecord{Record: &filmsv1.ExportRecord_Film{Film: toFilm(film)}}
+		if err := stream.Send(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,175 @@
+package grpcv1
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/api/filmsv1"
+)
+
+type filmsServer struct {
+	filmsv1.UnimplementedFilmsServiceServer
+	films service.Films
+}
+
+func (s *filmsServer) CreateFilm(ctx context.Context, req *filmsv1.CreateFilmRequest) (*emptypb.Empty, error) {
+	actors, err := parseIds("actor_ids", req.GetActorIds())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.films.AddNewFilm(ctx, service.FilmCreateInput{
+		FilmInfo: service.FilmInfo{
+			Name:        req.GetName(),
+			Description: req.GetDescription(),
+			Date:        req.GetDate(),
+			Rating:      req.GetRating(),
+		},
+		Actors: actors,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *filmsServer) UpdateFilm(ctx context.Context, req *filmsv1.UpdateFilmRequest) (*filmsv1.Film, error) {
+	id, err := parseId("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	toAdd, err := parseIds("actors_to_add", req.GetActorsToAdd())
+	if err != nil {
+		return nil, err
+	}
+	toDel, err := parseIds("actors_to_del", req.GetActorsToDel())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.films.EditFilm(ctx, service.FilmUpdateInput{
+		ID: id,
+		FilmInfo: service.FilmInfo{
+			Name:        req.GetName(),
+			Description: req.GetDescription(),
+			Date:        req.GetDate(),
+			Rating:      req.GetRating(),
+		},
+		ActorsToAdd: toAdd,
+		ActorsToDel: toDel,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	film, err := s.films.GetFilmById(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toFilm(film), nil
+}
+
+func (s *filmsServer) DeleteFilm(ctx context.Context, req *filmsv1.DeleteFilmRequest) (*emptypb.Empty, error) {
+	id, err := parseId("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.films.DeleteFilm(ctx, id); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *filmsServer) GetFilm(ctx context.Context, req *filmsv1.GetFilmRequest) (*filmsv1.Film, error) {
+	id, err := parseId("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	film, err := s.films.GetFilmById(ctx, id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toFilm(film), nil
+}
+
+var filmSorts = map[filmsv1.FilmSort]string{
+	filmsv1.FilmSort_FILM_SORT_NAME:   "name",
+	filmsv1.FilmSort_FILM_SORT_DATE:   "date",
+	filmsv1.FilmSort_FILM_SORT_RATING: "rating",
+}
+
+var sortOrders = map[filmsv1.SortOrder]string{
+	filmsv1.SortOrder_SORT_ORDER_ASC:  "asc",
+	filmsv1.SortOrder_SORT_ORDER_DESC: "desc",
+}
+
+func (s *filmsServer) ListFilms(req *filmsv1.ListFilmsRequest, stream filmsv1.FilmsService_ListFilmsServer) error {
+	ctx := stream.Context()
+
+	var films []models.Film
+	var err error
+
+	switch {
+	case req.GetName() != "":
+		films, err = s.films.GetAllFilmsByName(ctx, req.GetName())
+	case req.GetActorName() != "":
+		films, err = s.films.GetAllFilmsByActor(ctx, req.GetActorName())
+	default:
+		sortField, order := "rating", "desc"
+		if req.GetSort() != filmsv1.FilmSort_FILM_SORT_UNSPECIFIED {
+			sortField, order = filmSorts[req.GetSort()], "asc"
+		}
+		if req.GetOrder() != filmsv1.SortOrder_SORT_ORDER_UNSPECIFIED {
+			order = sortOrders[req.GetOrder()]
+		}
+		if sortField == "" || order == "" {
+			return status.Error(codes.InvalidArgument, "unknown sort or order")
+		}
+
+		ctx = context.WithValue(ctx, "sort", sortField)
+		ctx = context.WithValue(ctx, "order", order)
+		films, err = s.films.GetAllFilms(ctx)
+	}
+	if err != nil {
+		return statusError(err)
+	}
+
+	for _, film := range films {
+		if err := stream.Send(toFilm(film)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toFilm(film models.Film) *filmsv1.Film {
+	actors := make([]*filmsv1.FilmActor, 0, len(film.Actors))
+	for _, a := range film.Actors {
+		actors = append(actors, &filmsv1.FilmActor{
+			Id:         a.ID.String(),
+			Name:       a.Name,
+			SecondName: a.SecondName,
+			Patronymic: a.Patronymic,
+		})
+	}
+
+	return &filmsv1.Film{
+		Id:          film.ID.String(),
+		Name:        film.Name,
+		Description: film.Description,
+		Date:        film.Date,
+		Rating:      film.Rating,
+		Actors:      actors,
+		UpdatedAt:   timestamp(film.UpdatedAt),
+	}
+}
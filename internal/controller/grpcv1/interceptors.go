@@ -0,0 +1,122 @@
+package grpcv1
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+	"vk-test-spring/internal/metrics"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/logger"
+	"vk-test-spring/pkg/requestid"
+)
+
+type interceptors struct {
+	logger zerolog.Logger
+	users  service.Users
+}
+
+// requestID accepts a well-formed x-request-id metadata value or generates one, sends it back in
+// the response header and stores it in the context together with a logger that adds it to every line.
+func (i *interceptors) requestID(ctx context.Context, method string, next func(ctx context.Context) error) error {
+	id := requestid.Resolve(firstValue(ctx, strings.ToLower(requestid.Header)))
+	grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+
+	l := i.logger.With().Str("request_id", id).Logger()
+
+	ctx = requestid.NewContext(ctx, id)
+	ctx = l.WithContext(ctx)
+
+	return next(ctx)
+}
+
+func (i *interceptors) metrics(ctx context.Context, method string, next func(ctx context.Context) error) error {
+	begin := time.Now()
+	err := next(ctx)
+
+	code := status.Code(err).String()
+	metrics.GRPCRequests.WithLabelValues(method, code).Inc()
+	metrics.GRPCRequestDuration.WithLabelValues(method, code).Observe(time.Since(begin).Seconds())
+
+	return err
+}
+
+// logs writes one line per call. Server errors are logged with their message, which is not
+// otherwise kept once it is sent to the client.
+func (i *interceptors) logs(ctx context.Context, method string, next func(ctx context.Context) error) error {
+	logg := logger.FromContext(ctx).Log().Timestamp().Str("method", method)
+
+	begin := time.Now()
+	err := next(context.WithValue(ctx, "logger", logg))
+
+	s := status.Convert(err)
+	if serverError(s.Code()) {
+		logg.Str("error", s.Message())
+	}
+
+	tookMs := time.Since(begin).Milliseconds()
+	logg.Int64("took", tookMs).Str("code", s.Code().String()).Msgf("[%s] grpc call %s took %dms", s.Code(), method, tookMs)
+
+	return err
+}
+
+// auth checks the basic auth credentials of the authorization metadata, as the REST API checks
+// the Authorization header, and stores the id and role of the user in the context.
+func (i *interceptors) auth(ctx context.Context, method string, next func(ctx context.Context) error) error {
+	username, password, ok := basicAuth(firstValue(ctx, "authorization"))
+	if !ok {
+		metrics.AuthFailures.WithLabelValues(metrics.AuthMissingCredentials).Inc()
+		return status.Error(codes.Unauthenticated, "Unauthorized")
+	}
+
+	userId, role, err := i.users.GetUserIdRole(username, password)
+	if err != nil {
+		var customErr models.CustomError
+		if !errors.As(err, &customErr) {
+			return status.Error(codes.Internal, "Internal server error")
+		}
+
+		metrics.AuthFailures.WithLabelValues(metrics.AuthInvalidCredentials).Inc()
+		return statusError(customErr)
+	}
+
+	ctx = context.WithValue(ctx, "user_id", userId)
+	ctx = context.WithValue(ctx, "role", role)
+
+	if l, ok := ctx.Value("logger").(*zerolog.Event); ok {
+		l.Str("user_id", userId).Str("user_name", username).Str("role", role)
+	}
+
+	return next(ctx)
+}
+
+func firstValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// basicAuth parses "Basic <base64 of name:password>" the way http.Request.BasicAuth does.
+func basicAuth(auth string) (username string, password string, ok bool) {
+	const prefix = "Basic "
+	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(auth[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(decoded), ":")
+}
@@ -0,0 +1,75 @@
+// Package grpcv1 serves the films library over gRPC for internal consumers. The services of
+// pkg/api/filmsv1 run on top of service.Services, which validate input and check the administrator
+// role of writes for every API, so both match the REST API.
+package grpcv1
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/api/filmsv1"
+)
+
+// NewServer registers the films, actors, users and export services on a gRPC server. Every call
+// goes through the request id, metrics, logging and auth interceptors, in that order.
+func NewServer(services *service.Services, logs zerolog.Logger) *grpc.Server {
+	i := &interceptors{logger: logs, users: services.Users}
+	chain := []interceptor{i.requestID, i.metrics, i.logs, i.auth}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary(chain)...),
+		grpc.ChainStreamInterceptor(stream(chain)...),
+	)
+
+	filmsv1.RegisterFilmsServiceServer(s, &filmsServer{films: services.Films})
+	filmsv1.RegisterActorsServiceServer(s, &actorsServer{actors: services.Actors})
+	filmsv1.RegisterUsersServiceServer(s, &usersServer{})
+	filmsv1.RegisterExportServiceServer(s, &exportServer{films: services.Films, actors: services.Actors})
+
+	return s
+}
+
+// interceptor wraps one call, unary or streaming. next runs the rest of the chain with ctx.
+type interceptor func(ctx context.Context, method string, next func(ctx context.Context) error) error
+
+func unary(chain []interceptor) []grpc.UnaryServerInterceptor {
+	out := make([]grpc.UnaryServerInterceptor, 0, len(chain))
+	for _, in := range chain {
+		in := in
+		out = append(out, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+			err = in(ctx, info.FullMethod, func(ctx context.Context) error {
+				resp, err = handler(ctx, req)
+				return err
+			})
+
+			return resp, err
+		})
+	}
+
+	return out
+}
+
+func stream(chain []interceptor) []grpc.StreamServerInterceptor {
+	out := make([]grpc.StreamServerInterceptor, 0, len(chain))
+	for _, in := range chain {
+		in := in
+		out = append(out, func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return in(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+				return handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+			})
+		})
+	}
+
+	return out
+}
+
+// contextStream hands the context built by the interceptors to a streaming handler.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,239 @@
+package grpcv1_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+	"net"
+	"testing"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/controller/grpcv1"
+	"vk-test-spring/internal/metrics"
+	"vk-test-spring/internal/repository"
+	"vk-test-spring/internal/repository/repositorytest"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/api/filmsv1"
+)
+
+type clients struct {
+	films  filmsv1.FilmsServiceClient
+	actors filmsv1.ActorsServiceClient
+	users  filmsv1.UsersServiceClient
+	export filmsv1.ExportServiceClient
+}
+
+func newClients(t *testing.T) clients {
+	users := []config.StorageUserConfig{
+		{Name: "admin", PasswordHash: repositorytest.PasswordHash(t, "admin"), Role: "администратор"},
+		{Name: "user", PasswordHash: repositorytest.PasswordHash(t, "user"), Role: "пользователь"},
+	}
+	services := service.NewServices(repository.NewMemoryRepositories(users, config.SearchConfig{SimilarityThreshold: 0.3}), nil)
+
+	listener := bufconn.Listen(1 << 20)
+	srv := grpcv1.NewServer(services, zerolog.Nop())
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return clients{
+		films:  filmsv1.NewFilmsServiceClient(conn),
+		actors: filmsv1.NewActorsServiceClient(conn),
+		users:  filmsv1.NewUsersServiceClient(conn),
+		export: filmsv1.NewExportServiceClient(conn),
+	}
+}
+
+// as authenticates the calls made with the returned context with the user whose password is its name.
+func as(user string) context.Context {
+	credentials := base64.StdEncoding.EncodeToString([]byte(user + ":" + user))
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+credentials)
+}
+
+func receiveAll[T any](t *testing.T, stream interface{ Recv() (T, error) }) []T {
+	t.Helper()
+
+	var out []T
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		require.NoError(t, err)
+		out = append(out, msg)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	c := newClients(t)
+
+	t.Run("missing credentials", func(t *testing.T) {
+		before := testutil.ToFloat64(metrics.AuthFailures.WithLabelValues(metrics.AuthMissingCredentials))
+
+		_, err := c.users.GetMe(context.Background(), &emptypb.Empty{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.AuthFailures.WithLabelValues(metrics.AuthMissingCredentials)))
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization",
+			"Basic "+base64.StdEncoding.EncodeToString([]byte("admin:wrong")))
+
+		_, err := c.users.GetMe(ctx, &emptypb.Empty{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("streams are authenticated", func(t *testing.T) {
+		stream, err := c.films.ListFilms(context.Background(), &filmsv1.ListFilmsRequest{})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("me", func(t *testing.T) {
+		var header metadata.MD
+		me, err := c.users.GetMe(as("user"), &emptypb.Empty{}, grpc.Header(&header))
+		require.NoError(t, err)
+
+		assert.Equal(t, "пользователь", me.GetRole())
+		assert.NotEmpty(t, me.GetId())
+		assert.NotEmpty(t, header.Get("x-request-id"))
+	})
+
+	t.Run("user management is not implemented", func(t *testing.T) {
+		_, err := c.users.ChangeRole(as("admin"), &filmsv1.ChangeRoleRequest{Id: "1", Role: "администратор"})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestFilms(t *testing.T) {
+	c := newClients(t)
+
+	_, err := c.actors.CreateActor(as("admin"), &filmsv1.CreateActorRequest{
+		Name: "Keanu", SecondName: "Reeves", Sex: "Мужчина", DateOfBirth: "1964-09-02",
+	})
+	require.NoError(t, err)
+
+	actors := receiveAll[*filmsv1.Actor](t, must(c.actors.ListActors(as("user"), &filmsv1.ListActorsRequest{})))
+	require.Len(t, actors, 1)
+	actorId := actors[0].GetId()
+
+	for _, film := range []*filmsv1.CreateFilmRequest{
+		{Name: "The Matrix", Description: "A hacker learns the truth", Date: "1999-03-31", Rating: 8.7, ActorIds: []string{actorId}},
+		{Name: "Speed", Description: "A bus must not slow down", Date: "1994-06-10", Rating: 7.2, ActorIds: []string{actorId}},
+		{Name: "Arrival", Description: "Linguists meet aliens", Date: "2016-11-11", Rating: 7.9},
+	} {
+		_, err := c.films.CreateFilm(as("admin"), film)
+		require.NoError(t, err)
+	}
+
+	t.Run("users cannot create", func(t *testing.T) {
+		_, err := c.films.CreateFilm(as("user"), &filmsv1.CreateFilmRequest{Name: "Nope", Description: "Nope", Date: "2000-01-01"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := c.films.CreateFilm(as("admin"), &filmsv1.CreateFilmRequest{Name: "No date", Description: "No date"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = c.films.GetFilm(as("user"), &filmsv1.GetFilmRequest{Id: "not-a-uuid"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("list by rating by default", func(t *testing.T) {
+		films := receiveAll[*filmsv1.Film](t, must(c.films.ListFilms(as("user"), &filmsv1.ListFilmsRequest{})))
+
+		require.Len(t, films, 3)
+		assert.Equal(t, []string{"The Matrix", "Arrival", "Speed"}, names(films))
+		assert.Equal(t, "Keanu", films[0].GetActors()[0].GetName())
+		assert.NotNil(t, films[0].GetUpdatedAt())
+	})
+
+	t.Run("list sorted", func(t *testing.T) {
+		films := receiveAll[*filmsv1.Film](t, must(c.films.ListFilms(as("user"), &filmsv1.ListFilmsRequest{Sort: filmsv1.FilmSort_FILM_SORT_DATE})))
+		assert.Equal(t, []string{"Speed", "The Matrix", "Arrival"}, names(films))
+
+		films = receiveAll[*filmsv1.Film](t, must(c.films.ListFilms(as("user"), &filmsv1.ListFilmsRequest{
+			Sort: filmsv1.FilmSort_FILM_SORT_NAME, Order: filmsv1.SortOrder_SORT_ORDER_DESC,
+		})))
+		assert.Equal(t, []string{"The Matrix", "Speed", "Arrival"}, names(films))
+	})
+
+	t.Run("list by actor", func(t *testing.T) {
+		films := receiveAll[*filmsv1.Film](t, must(c.films.ListFilms(as("user"), &filmsv1.ListFilmsRequest{ActorName: "Keanu"})))
+		assert.ElementsMatch(t, []string{"The Matrix", "Speed"}, names(films))
+	})
+
+	t.Run("update, get and delete", func(t *testing.T) {
+		films := receiveAll[*filmsv1.Film](t, must(c.films.ListFilms(as("user"), &filmsv1.ListFilmsRequest{Name: "Arrival"})))
+		require.Len(t, films, 1)
+		id := films[0].GetId()
+
+		updated, err := c.films.UpdateFilm(as("admin"), &filmsv1.UpdateFilmRequest{Id: id, Rating: 8.0, ActorsToAdd: []string{actorId}})
+		require.NoError(t, err)
+		assert.Equal(t, "Arrival", updated.GetName())
+		assert.Equal(t, 8.0, updated.GetRating())
+		assert.Len(t, updated.GetActors(), 1)
+
+		_, err = c.films.DeleteFilm(as("admin"), &filmsv1.DeleteFilmRequest{Id: id})
+		require.NoError(t, err)
+
+		_, err = c.films.GetFilm(as("user"), &filmsv1.GetFilmRequest{Id: id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("export", func(t *testing.T) {
+		records := receiveAll[*filmsv1.ExportRecord](t, must(c.export.Export(as("user"), &filmsv1.ExportRequest{})))
+
+		require.Len(t, records, 3)
+		assert.Equal(t, "Keanu", records[0].GetActor().GetName())
+		assert.Len(t, records[0].GetActor().GetFilms(), 2)
+		assert.Equal(t, "Speed", records[1].GetFilm().GetName())
+		assert.Equal(t, "The Matrix", records[2].GetFilm().GetName())
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		const method = "/films.v1.FilmsService/GetFilm"
+		before := testutil.ToFloat64(metrics.GRPCRequests.WithLabelValues(method, codes.NotFound.String()))
+
+		_, err := c.films.GetFilm(as("user"), &filmsv1.GetFilmRequest{Id: "00000000-0000-0000-0000-000000000001"})
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.GRPCRequests.WithLabelValues(method, codes.NotFound.String())))
+	})
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+func names(films []*filmsv1.Film) []string {
+	out := make([]string, 0, len(films))
+	for _, f := range films {
+		out = append(out, f.GetName())
+	}
+
+	return out
+}
@@ -0,0 +1,20 @@
+package grpcv1
+
+import (
+	"context"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"vk-test-spring/pkg/api/filmsv1"
+)
+
+// usersServer leaves CreateUser, DeleteUser and ChangeRole to the embedded unimplemented server:
+// service.Users does not manage users yet and the REST API answers them with 501.
+type usersServer struct {
+	filmsv1.UnimplementedUsersServiceServer
+}
+
+func (s *usersServer) GetMe(ctx context.Context, _ *emptypb.Empty) (*filmsv1.User, error) {
+	userId, _ := ctx.Value("user_id").(string)
+	role, _ := ctx.Value("role").(string)
+
+	return &filmsv1.User{Id: userId, Role: role}, nil
+}
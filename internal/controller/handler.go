@@ -2,22 +2,43 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
-	_ "github.com/swaggo/files"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
-	"net/http/httptest"
-	"net/http/httputil"
+	"strconv"
+	"strings"
 	"time"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/controller/graph"
 	"vk-test-spring/internal/controller/httpv1"
+	"vk-test-spring/internal/metrics"
+	"vk-test-spring/internal/models"
 	"vk-test-spring/internal/service"
+	database "vk-test-spring/pkg/database/postgresql"
 	"vk-test-spring/pkg/logger"
+	"vk-test-spring/pkg/requestid"
+	"vk-test-spring/pkg/tracing"
 )
 
 type Handler struct {
 	filmsHandler  FilmsHandler
 	actorsHandler ActorsHandler
 	usersHandler  UsersHandler
+	listsHandler  ListsHandler
+	searchHandler SearchHandler
+	graphHandler  http.Handler
+	graphQL       config.GraphQLConfig
 	logger        zerolog.Logger
+	cacheControl  map[string]string
+	redactor      *logger.Redactor
+	patterns      []string
 }
 
 type ActorsHandler interface {
@@ -38,6 +59,7 @@ type FilmsHandler interface {
 	GetAllFilms(w http.ResponseWriter, r *http.Request)
 	GetFilmsByName(w http.ResponseWriter, r *http.Request)
 	GetFilmsByActor(w http.ResponseWriter, r *http.Request)
+	GetFilmById(w http.ResponseWriter, r *http.Request)
 }
 
 type UsersHandler interface {
@@ -48,8 +70,37 @@ type UsersHandler interface {
 	GetRole(username string, password string) (string, string, error)
 }
 
-func NewHandler() *Handler {
-	return &Handler{}
+type ListsHandler interface {
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+	GetUserLists(w http.ResponseWriter, r *http.Request)
+	CreateList(w http.ResponseWriter, r *http.Request)
+	GetList(w http.ResponseWriter, r *http.Request)
+	UpdateList(w http.ResponseWriter, r *http.Request)
+	DeleteList(w http.ResponseWriter, r *http.Request)
+	AddFilm(w http.ResponseWriter, r *http.Request)
+	UpdateFilm(w http.ResponseWriter, r *http.Request)
+	RemoveFilm(w http.ResponseWriter, r *http.Request)
+	GetSharedList(w http.ResponseWriter, r *http.Request)
+}
+
+type SearchHandler interface {
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+	Search(w http.ResponseWriter, r *http.Request)
+	Suggest(w http.ResponseWriter, r *http.Request)
+}
+
+// NewHandler builds the handler from cfg. redactor masks the request log; with nil requests are
+// logged with the built-in rules only and without bodies.
+func NewHandler(cfg *config.Config, redactor *logger.Redactor) *Handler {
+	if redactor == nil {
+		redactor = logger.NewRedactor(logger.RequestOptions{})
+	}
+
+	return &Handler{
+		cacheControl: cfg.HTTP.CacheControl,
+		redactor:     redactor,
+		graphQL:      cfg.GraphQL,
+	}
 }
 
 func (h *Handler) Init(services *service.Services, logs zerolog.Logger) *http.ServeMux {
@@ -58,6 +109,9 @@ func (h *Handler) Init(services *service.Services, logs zerolog.Logger) *http.Se
 	h.filmsHandler = httpv1.NewFilmsHandler(services.Films)
 	h.actorsHandler = httpv1.NewActorsHandler(services.Actors)
 	h.usersHandler = httpv1.NewUsersHandler(services.Users)
+	h.listsHandler = httpv1.NewListsHandler(services.Lists)
+	h.searchHandler = httpv1.NewSearchHandler(services.Search)
+	h.graphHandler = graph.NewHandler(services, h.graphQL)
 
 	h.logger = logs
 
@@ -67,12 +121,29 @@ func (h *Handler) Init(services *service.Services, logs zerolog.Logger) *http.Se
 }
 
 func (h *Handler) initAPI(router *http.ServeMux) {
-	router.Handle("/films", h.logs(h.usersAuth(h.filmsHandler))) // добавить это на handlers ниже
-	router.Handle("/films/", h.logs(h.usersAuth(h.filmsHandler)))
-	router.Handle("/actors", h.logs(h.usersAuth(h.actorsHandler)))
-	router.Handle("/actors/", h.logs(h.usersAuth(h.actorsHandler)))
-	router.Handle("/users", h.logs(h.usersHandler))
-	router.Handle("/users/", h.logs(h.usersHandler))
+	h.handle(router, "/films", h.usersAuth(h.conditionalGet("films", h.filmsHandler))) // добавить это на handlers ниже
+	h.handle(router, "/films/", h.usersAuth(h.conditionalGet("films", h.filmsHandler)))
+	h.handle(router, "/actors", h.usersAuth(h.conditionalGet("actors", h.actorsHandler)))
+	h.handle(router, "/actors/", h.usersAuth(h.conditionalGet("actors", h.actorsHandler)))
+	h.handle(router, "/users", h.usersHandler)
+	h.handle(router, "/users/", h.usersHandler)
+	h.handle(router, "/users/me/lists", h.usersAuth(h.conditionalGet("lists", h.listsHandler)))
+	h.handle(router, "/users/me/lists/", h.usersAuth(h.conditionalGet("lists", h.listsHandler)))
+	h.handle(router, "/lists/shared/", h.conditionalGet("shared_lists", h.listsHandler))
+	h.handle(router, "/search", h.usersAuth(h.conditionalGet("search", h.searchHandler)))
+	h.handle(router, "/suggest", h.usersAuth(h.conditionalGet("suggest", h.searchHandler)))
+	h.handle(router, "/graphql", h.usersAuth(h.graphHandler))
+	h.handle(router, "/debug/vars", h.usersAuth(h.adminOnly(getOnly(expvar.Handler()))))
+	h.handle(router, "/openapi.json", getOnly(h.openAPI()))
+	h.handle(router, "/docs", getOnly(h.swaggerUI()))
+	h.handle(router, "/docs/", getOnly(h.swaggerUI()))
+}
+
+// handle registers the handler with the middleware shared by every route: request id, metrics, tracing,
+// request logging and read consistency. The patterns are kept to check the OpenAPI spec against.
+func (h *Handler) handle(router *http.ServeMux, pattern string, handler http.Handler) {
+	h.patterns = append(h.patterns, pattern)
+	router.Handle(pattern, h.requestID(h.instrument(pattern, h.trace(pattern, h.logs(h.readConsistency(handler))))))
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -83,15 +154,23 @@ func (h *Handler) usersAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		username, password, ok := r.BasicAuth()
 		if !ok {
+			metrics.AuthFailures.WithLabelValues(metrics.AuthMissingCredentials).Inc()
 			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			httpv1.WriteError(w, r, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
 		userId, role, err := h.usersHandler.GetRole(username, password)
 		if err != nil {
+			var customErr models.CustomError
+			if !errors.As(err, &customErr) {
+				httpv1.WriteError(w, r, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			metrics.AuthFailures.WithLabelValues(metrics.AuthInvalidCredentials).Inc()
 			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			httpv1.WriteError(w, r, customErr.Message, customErr.Code)
 			return
 		}
 
@@ -107,21 +186,126 @@ func (h *Handler) usersAuth(next http.Handler) http.Handler {
 	})
 }
 
+func (h *Handler) adminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !service.IsAdmin(r.Context()) {
+			httpv1.WriteError(w, r, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getOnly answers 405 to requests other than GET and HEAD, for handlers that would serve any method.
+func getOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			httpv1.WriteError(w, r, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestID accepts a well-formed X-Request-ID or generates one, echoes it in the response and
+// stores it in the context together with a logger that adds it to every line.
+func (h *Handler) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestid.Resolve(r.Header.Get(requestid.Header))
+		w.Header().Set(requestid.Header, id)
+
+		l := h.logger.With().Str("request_id", id).Logger()
+
+		ctx := requestid.NewContext(r.Context(), id)
+		ctx = l.WithContext(ctx)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ReadConsistencyHeader set to "primary" makes every read of the request go to the primary database,
+// so a client sees the writes of its previous requests even while the replica lags behind.
+const ReadConsistencyHeader = "X-Read-Consistency"
+
+// readConsistency sends the reads of a request to the primary once the request has written,
+// or from the start when the client asks for it with ReadConsistencyHeader.
+func (h *Handler) readConsistency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primary := strings.EqualFold(r.Header.Get(ReadConsistencyHeader), "primary")
+		ctx := database.WithReadYourWrites(r.Context(), primary)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// instrument records the request count and latency. The route label is the registered pattern,
+// not the request path, to keep the number of series bounded.
+func (h *Handler) instrument(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := logger.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func(begin time.Time) {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			labels := prometheus.Labels{"route": route, "method": r.Method, "status": strconv.Itoa(status)}
+			metrics.HTTPRequests.With(labels).Inc()
+			metrics.HTTPRequestDuration.With(labels).Observe(time.Since(begin).Seconds())
+		}(time.Now())
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// trace starts the server span of the request. The incoming trace context, if any, becomes its parent,
+// and the span travels down to services and repositories through the request context.
+func (h *Handler) trace(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Start(ctx, r.Method+" "+route, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", route),
+			attribute.String("http.target", r.URL.RequestURI()),
+			attribute.String("http.request_id", requestid.FromContext(r.Context())),
+		))
+		defer span.End()
+
+		ww := logger.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
 func (h *Handler) logs(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ww := logger.NewWrapResponseWriter(w, r.ProtoMajor)
-		rec := httptest.NewRecorder()
 
 		ctx := r.Context()
 
 		path := r.URL.EscapedPath()
 
-		reqData, _ := httputil.DumpRequest(r, true)
-
-		logg := h.logger.Log().Timestamp().Str("path", path).Bytes("request_data", reqData)
+		logg := logger.FromContext(ctx).Log().Timestamp().Str("path", path).Dict("request", h.redactor.Request(r))
 
 		defer func(begin time.Time) {
 			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
 
 			tookMs := time.Since(begin).Milliseconds()
 			logg.Int64("took", tookMs).Int("status_code", status).Msgf("[%d] %s http request for %s took %dms",
@@ -129,13 +313,6 @@ func (h *Handler) logs(next http.Handler) http.Handler {
 		}(time.Now())
 
 		ctx = context.WithValue(ctx, "logger", logg)
-		next.ServeHTTP(rec, r.WithContext(ctx))
-
-		for k, v := range rec.Header() {
-			ww.Header()[k] = v
-		}
-
-		ww.WriteHeader(rec.Code)
-		rec.Body.WriteTo(ww)
+		next.ServeHTTP(ww, r.WithContext(ctx))
 	})
 }
@@ -29,28 +29,27 @@ func NewActorsHandler(actorsService service.Actors) *ActorsHandler {
 }
 
 func (h *ActorsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	switch {
-	case r.Method == http.MethodGet && actorsRe.MatchString(r.URL.Path):
-		h.GetAllActors(w, r)
-		return
-	case r.Method == http.MethodGet && actorIdRe.MatchString(r.URL.Path):
-		h.GetActorById(w, r)
-		return
-	case r.Method == http.MethodPost && actorsRe.MatchString(r.URL.Path) &&
-		r.Context().Value("role").(string) == "администратор":
-		h.AddActor(w, r)
-		return
-	case r.Method == http.MethodPatch && actorIdRe.MatchString(r.URL.Path) &&
-		r.Context().Value("role").(string) == "администратор":
-		h.UpdateActor(w, r)
-		return
-	case r.Method == http.MethodDelete && actorIdRe.MatchString(r.URL.Path) &&
-		r.Context().Value("role").(string) == "администратор":
-		h.DeleteActor(w, r)
-		return
-	default:
+	serve(w, r, h.routes())
+}
+
+func (h *ActorsHandler) routes() []route {
+	return []route{
+		{http.MethodGet, actorsRe, "/actors", false, h.GetActors},
+		{http.MethodGet, actorIdRe, "/actors/{id}", false, h.GetActorById},
+		{http.MethodPost, actorsRe, "/actors", true, h.AddActor},
+		{http.MethodPatch, actorIdRe, "/actors/{id}", true, h.UpdateActor},
+		{http.MethodDelete, actorIdRe, "/actors/{id}", true, h.DeleteActor},
+	}
+}
+
+// GetActors lists the actors, filtered by name when the query asks for it.
+func (h *ActorsHandler) GetActors(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("name") != "" {
+		h.GetActorByName(w, r)
 		return
 	}
+
+	h.GetAllActors(w, r)
 }
 
 type ActorCreateInput struct {
@@ -62,23 +61,10 @@ type ActorCreateInput struct {
 	Films       []uuid.UUID `json:"films,omitempty"`
 }
 
-// @Summary Add New Actor
-// @Tags actors
-// @Description create new actor record
-// @ID AddActor
-// @Accept json
-// @Produce json
-// @Param input body ActorCreateInput true
-// @Success 201 {string} string "ok"
-// @Failure 400,404 {object} http.Error
-// @Failure 500 {object} http.Error
-// @Failure default {object} http.Error
-// @Router /films [post]
-
 func (h *ActorsHandler) AddActor(w http.ResponseWriter, r *http.Request) {
 	var actor ActorCreateInput
 	if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
-		http.Error(w, "error while decoding request body", http.StatusBadRequest)
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
 		return
 	}
 
@@ -95,10 +81,10 @@ func (h *ActorsHandler) AddActor(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
@@ -119,13 +105,13 @@ type ActorUpdateInput struct {
 func (h *ActorsHandler) UpdateActor(w http.ResponseWriter, r *http.Request) {
 	var actor ActorUpdateInput
 	if err := json.NewDecoder(r.Body).Decode(&actor); err != nil {
-		http.Error(w, "error while decoding request body", http.StatusBadRequest)
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
 		return
 	}
 
 	actorId, err := h.getActorIdFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -144,10 +130,10 @@ func (h *ActorsHandler) UpdateActor(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
@@ -158,7 +144,7 @@ func (h *ActorsHandler) UpdateActor(w http.ResponseWriter, r *http.Request) {
 func (h *ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 	actorId, err := h.getActorIdFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -166,10 +152,10 @@ func (h *ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
@@ -178,32 +164,32 @@ func (h *ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ActorsHandler) GetAllActors(w http.ResponseWriter, r *http.Request) {
+	changed, err := h.actorsService.LastModified(r.Context())
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	actorsList, err := h.actorsService.GetAllActors(r.Context())
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	jsonResponse, err := json.Marshal(actorsList)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	setVersion(w, changed)
+	RenderList(w, r, http.StatusOK, actorsList)
 }
 
 func (h *ActorsHandler) GetActorById(w http.ResponseWriter, r *http.Request) {
 	actorId, err := h.getActorIdFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -211,29 +197,26 @@ func (h *ActorsHandler) GetActorById(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	jsonResponse, err := json.Marshal(actor)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	setLastModified(w, actor.UpdatedAt)
+	Render(w, r, http.StatusOK, actor)
 }
 
 func (h *ActorsHandler) GetActorByName(w http.ResponseWriter, r *http.Request) {
 	var name string
+	params := r.URL.Query()
+	name = params.Get("name")
 
-	err := json.NewDecoder(r.Body).Decode(&name)
+	changed, err := h.actorsService.LastModified(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -241,22 +224,16 @@ func (h *ActorsHandler) GetActorByName(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	jsonResponse, err := json.Marshal(actors)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	setVersion(w, changed)
+	RenderList(w, r, http.StatusOK, actors)
 }
 
 func (h *ActorsHandler) getActorIdFromRequest(r *http.Request) (uuid.UUID, error) {
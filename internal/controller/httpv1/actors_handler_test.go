@@ -1 +1,38 @@
 package httpv1
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestActorsHandlerUnmatched(t *testing.T) {
+	h := NewActorsHandler(nil)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		allow  string
+	}{
+		{"write as user", http.MethodPost, "/actors", http.StatusForbidden, ""},
+		{"delete as user", http.MethodDelete, "/actors/3f1c2a9e-8d4b-4c2e-9a7f-1b2c3d4e5f60", http.StatusForbidden, ""},
+		{"unknown method", http.MethodPut, "/actors/3f1c2a9e-8d4b-4c2e-9a7f-1b2c3d4e5f60", http.StatusMethodNotAllowed, "DELETE, GET, PATCH"},
+		{"unknown path", http.MethodGet, "/actors/42", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r = r.WithContext(context.WithValue(r.Context(), "role", "пользователь"))
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.allow, w.Header().Get("Allow"))
+		})
+	}
+}
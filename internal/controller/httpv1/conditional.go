@@ -0,0 +1,40 @@
+package httpv1
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setLastModified announces when the returned record was changed.
+func setLastModified(w http.ResponseWriter, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	w.Header().Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// setVersion tags a list with when the tables it is read from last changed, deletes included. The
+// ETag is made of that time, so the list does not have to be hashed; negotiate adds the format.
+// changed has to be read before the list, so that a write in between only makes it look older.
+func setVersion(w http.ResponseWriter, changed time.Time) {
+	if changed.IsZero() {
+		return
+	}
+
+	setLastModified(w, changed)
+	w.Header().Set("ETag", `"v`+strconv.FormatInt(changed.UnixMicro(), 36)+`"`)
+}
+
+// versionFor scopes the ETag set by setVersion to the representation in f.
+func versionFor(w http.ResponseWriter, f format) {
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		return
+	}
+
+	name := f.mediaType[strings.LastIndex(f.mediaType, "/")+1:]
+	w.Header().Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+name+`"`)
+}
@@ -0,0 +1,33 @@
+package httpv1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"vk-test-spring/pkg/logger"
+	"vk-test-spring/pkg/requestid"
+)
+
+// ErrorResponse is the body of every error the API answers with.
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// WriteError replies with a JSON error body carrying the request identifier,
+// so a client can quote it when reporting a problem. Server errors are also logged.
+func WriteError(w http.ResponseWriter, r *http.Request, message string, code int) {
+	message = strings.TrimSpace(message)
+	id := requestid.FromContext(r.Context())
+
+	if code >= http.StatusInternalServerError {
+		logger.FromContext(r.Context()).Error().Int("status_code", code).Msg(message)
+	}
+
+	body, _ := json.Marshal(ErrorResponse{Error: message, RequestID: id})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	w.Write(body)
+}
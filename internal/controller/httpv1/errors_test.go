@@ -0,0 +1,21 @@
+package httpv1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"vk-test-spring/pkg/requestid"
+)
+
+func TestWriteError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/films", nil)
+	r = r.WithContext(requestid.NewContext(r.Context(), "req-1"))
+	w := httptest.NewRecorder()
+
+	WriteError(w, r, "films not found\n", http.StatusNotFound)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error": "films not found", "request_id": "req-1"}`, w.Body.String())
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"net/http"
 	"regexp"
+	"slices"
 	"strings"
 	"vk-test-spring/internal/models"
 	"vk-test-spring/internal/service"
@@ -31,34 +33,29 @@ func NewFilmsHandler(filmsService service.Films) *FilmsHandler {
 }
 
 func (h *FilmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, h.routes())
+}
+
+func (h *FilmsHandler) routes() []route {
+	return []route{
+		{http.MethodGet, filmsWithFilterRe, "/films", false, h.GetFilms},
+		{http.MethodGet, filmsIdRe, "/films/{id}", false, h.GetFilmById},
+		{http.MethodPost, filmsRe, "/films", true, h.AddFilm},
+		{http.MethodPatch, filmsIdRe, "/films/{id}", true, h.UpdateFilm},
+		{http.MethodDelete, filmsIdRe, "/films/{id}", true, h.DeleteFilm},
+	}
+}
+
+// GetFilms lists the films, filtered by name or actor name when the query asks for it.
+func (h *FilmsHandler) GetFilms(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
 	switch {
-	case r.Method == http.MethodGet && filmsWithFilterRe.MatchString(r.URL.Path):
-		params := r.URL.Query()
-		switch {
-		case params.Get("name") != "":
-			h.GetFilmsByName(w, r)
-			return
-		case params.Get("actor-name") != "":
-			h.GetFilmsByActor(w, r)
-			return
-		default:
-			h.GetAllFilms(w, r)
-			return
-		}
-	case r.Method == http.MethodPost && filmsRe.MatchString(r.URL.Path) &&
-		r.Context().Value("role").(string) == "администратор":
-		h.AddFilm(w, r)
-		return
-	case r.Method == http.MethodPatch && filmsIdRe.MatchString(r.URL.Path) &&
-		r.Context().Value("role").(string) == "администратор":
-		h.UpdateFilm(w, r)
-		return
-	case r.Method == http.MethodDelete && filmsIdRe.MatchString(r.URL.Path) &&
-		r.Context().Value("role").(string) == "администратор":
-		h.DeleteFilm(w, r)
-		return
+	case params.Get("name") != "":
+		h.GetFilmsByName(w, r)
+	case params.Get("actor-name") != "":
+		h.GetFilmsByActor(w, r)
 	default:
-		return
+		h.GetAllFilms(w, r)
 	}
 }
 
@@ -68,12 +65,14 @@ type FilmCreateInput struct {
 	Date        string      `json:"date" binding:"required"`
 	Rating      float64     `json:"rating" binding:"required"`
 	Actors      []uuid.UUID `json:"actors,omitempty"`
+	// NewActors are created together with the film, in the same transaction.
+	NewActors []ActorCreateInput `json:"new_actors,omitempty"`
 }
 
 func (h *FilmsHandler) AddFilm(w http.ResponseWriter, r *http.Request) {
 	var film FilmCreateInput
 	if err := json.NewDecoder(r.Body).Decode(&film); err != nil {
-		http.Error(w, "error while decoding request body", http.StatusInternalServerError)
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
 		return
 	}
 
@@ -84,15 +83,16 @@ func (h *FilmsHandler) AddFilm(w http.ResponseWriter, r *http.Request) {
 			Date:        film.Date,
 			Rating:      film.Rating,
 		},
-		Actors: film.Actors,
+		Actors:    film.Actors,
+		NewActors: newActorsInput(film.NewActors),
 	})
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
@@ -112,13 +112,13 @@ type FilmUpdateInput struct {
 func (h *FilmsHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 	var film FilmUpdateInput
 	if err := json.NewDecoder(r.Body).Decode(&film); err != nil {
-		http.Error(w, "error while decoding request body", http.StatusBadRequest)
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
 		return
 	}
 
 	filmId, err := h.getFilmIdFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -136,10 +136,10 @@ func (h *FilmsHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
@@ -150,7 +150,7 @@ func (h *FilmsHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
 func (h *FilmsHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 	filmId, err := h.getFilmIdFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -158,10 +158,10 @@ func (h *FilmsHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
@@ -170,28 +170,32 @@ func (h *FilmsHandler) DeleteFilm(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *FilmsHandler) GetAllFilms(w http.ResponseWriter, r *http.Request) {
-	r = h.getSortParams(r)
+	r, err := h.getSortParams(r)
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	changed, err := h.filmsService.LastModified(r.Context())
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	filmsList, err := h.filmsService.GetAllFilms(r.Context())
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	jsonResponse, err := json.Marshal(filmsList)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	setVersion(w, changed)
+	RenderList(w, r, http.StatusOK, filmsList)
 }
 
 func (h *FilmsHandler) GetFilmsByName(w http.ResponseWriter, r *http.Request) {
@@ -199,53 +203,76 @@ func (h *FilmsHandler) GetFilmsByName(w http.ResponseWriter, r *http.Request) {
 	params := r.URL.Query()
 	name = params.Get("name")
 
+	changed, err := h.filmsService.LastModified(r.Context())
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	films, err := h.filmsService.GetAllFilmsByName(r.Context(), name)
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	jsonResponse, err := json.Marshal(films)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	setVersion(w, changed)
+	RenderList(w, r, http.StatusOK, films)
 }
 
 func (h *FilmsHandler) GetFilmsByActor(w http.ResponseWriter, r *http.Request) {
 	var actorName string
 	params := r.URL.Query()
-	actorName = params.Get("name")
+	actorName = params.Get("actor-name")
+
+	changed, err := h.filmsService.LastModified(r.Context())
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	films, err := h.filmsService.GetAllFilmsByActor(r.Context(), actorName)
 	if err != nil {
 		switch e := err.(type) {
 		case models.CustomError:
-			http.Error(w, e.Message, e.Code)
+			WriteError(w, r, e.Message, e.Code)
 			return
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
 
-	jsonResponse, err := json.Marshal(films)
+	setVersion(w, changed)
+	RenderList(w, r, http.StatusOK, films)
+}
+
+func (h *FilmsHandler) GetFilmById(w http.ResponseWriter, r *http.Request) {
+	filmId, err := h.getFilmIdFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	film, err := h.filmsService.GetFilmById(r.Context(), filmId)
+	if err != nil {
+		switch e := err.(type) {
+		case models.CustomError:
+			WriteError(w, r, e.Message, e.Code)
+			return
+		default:
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	setLastModified(w, film.UpdatedAt)
+	Render(w, r, http.StatusOK, film)
 }
 
 func (h *FilmsHandler) getFilmIdFromRequest(r *http.Request) (uuid.UUID, error) {
@@ -257,21 +284,49 @@ func (h *FilmsHandler) getFilmIdFromRequest(r *http.Request) (uuid.UUID, error)
 	return uuid.Parse(parts[2])
 }
 
-func (h *FilmsHandler) getSortParams(r *http.Request) *http.Request {
+// getSortParams puts the sort field and order of the query into the context of r: rating, descending
+// when neither is given, ascending when only the field is. Only the known fields and orders are let
+// through, since the repository orders by them.
+func (h *FilmsHandler) getSortParams(r *http.Request) (*http.Request, error) {
 	params := r.URL.Query()
 	sortField := params.Get("sort")
-	sortOrder := params.Get("order")
-	sortOrder = strings.ToUpper(sortOrder)
+	sortOrder := strings.ToLower(params.Get("order"))
 
-	if sortField == "" && sortOrder == "" {
-		ctx := context.WithValue(r.Context(), "sort", "rating")
-		ctx = context.WithValue(ctx, "order", "desc")
+	switch {
+	case sortField == "" && sortOrder == "":
+		sortField, sortOrder = "rating", "desc"
+	case sortField == "":
+		sortField = "rating"
+	case sortOrder == "":
+		sortOrder = "asc"
+	}
 
-		return r.WithContext(ctx)
+	if !slices.Contains([]string{"name", "date", "rating"}, sortField) {
+		return r, fmt.Errorf("sort must be name, date or rating, got %q", sortField)
+	}
+	if !slices.Contains([]string{"asc", "desc"}, sortOrder) {
+		return r, fmt.Errorf("order must be asc or desc, got %q", params.Get("order"))
 	}
 
 	ctx := context.WithValue(r.Context(), "sort", sortField)
 	ctx = context.WithValue(ctx, "order", sortOrder)
 
-	return r.WithContext(ctx)
+	return r.WithContext(ctx), nil
+}
+
+func newActorsInput(actors []ActorCreateInput) []service.ActorCreateInput {
+	inputs := make([]service.ActorCreateInput, 0, len(actors))
+	for _, a := range actors {
+		inputs = append(inputs, service.ActorCreateInput{
+			ActorInfo: service.ActorInfo{
+				Name:        a.Name,
+				SecondName:  a.SecondName,
+				Patronymic:  a.Patronymic,
+				Sex:         a.Sex,
+				DateOfBirth: a.DateOfBirth,
+			},
+		})
+	}
+
+	return inputs
 }
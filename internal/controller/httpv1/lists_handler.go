@@ -0,0 +1,232 @@
+package httpv1
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"net/http"
+	"regexp"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+)
+
+var (
+	listsRe       = regexp.MustCompile(`^/users/me/lists/*$`)
+	listRe        = regexp.MustCompile(`^/users/me/lists/([^/]+)$`)
+	listFilmsRe   = regexp.MustCompile(`^/users/me/lists/([^/]+)/films/*$`)
+	listFilmIdRe  = regexp.MustCompile(`^/users/me/lists/([^/]+)/films/([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+	sharedListsRe = regexp.MustCompile(`^/lists/shared/([0-9a-f]{64})$`)
+)
+
+type ListsHandler struct {
+	listsService service.Lists
+}
+
+func NewListsHandler(listsService service.Lists) *ListsHandler {
+	return &ListsHandler{
+		listsService: listsService,
+	}
+}
+
+func (h *ListsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, h.routes())
+}
+
+func (h *ListsHandler) routes() []route {
+	return []route{
+		{http.MethodGet, listsRe, "/users/me/lists", false, h.GetUserLists},
+		{http.MethodPost, listsRe, "/users/me/lists", false, h.CreateList},
+		{http.MethodGet, listRe, "/users/me/lists/{list}", false, h.GetList},
+		{http.MethodPatch, listRe, "/users/me/lists/{list}", false, h.UpdateList},
+		{http.MethodDelete, listRe, "/users/me/lists/{list}", false, h.DeleteList},
+		{http.MethodPost, listFilmsRe, "/users/me/lists/{list}/films", false, h.AddFilm},
+		{http.MethodPatch, listFilmIdRe, "/users/me/lists/{list}/films/{film_id}", false, h.UpdateFilm},
+		{http.MethodDelete, listFilmIdRe, "/users/me/lists/{list}/films/{film_id}", false, h.RemoveFilm},
+		{http.MethodGet, sharedListsRe, "/lists/shared/{token}", false, h.GetSharedList},
+	}
+}
+
+func (h *ListsHandler) GetUserLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.listsService.GetUserLists(r.Context(), h.getUserId(r))
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	RenderList(w, r, http.StatusOK, lists)
+}
+
+type ListCreateInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (h *ListsHandler) CreateList(w http.ResponseWriter, r *http.Request) {
+	var input ListCreateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.listsService.CreateList(r.Context(), h.getUserId(r), service.ListInput{Name: input.Name})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	Render(w, r, http.StatusCreated, list)
+}
+
+func (h *ListsHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	listRef := listRe.FindStringSubmatch(r.URL.Path)[1]
+
+	list, err := h.listsService.GetList(r.Context(), h.getUserId(r), listRef)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	Render(w, r, http.StatusOK, list)
+}
+
+type ListUpdateInput struct {
+	Name   string `json:"name,omitempty"`
+	Public *bool  `json:"public,omitempty"`
+}
+
+func (h *ListsHandler) UpdateList(w http.ResponseWriter, r *http.Request) {
+	var input ListUpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	listRef := listRe.FindStringSubmatch(r.URL.Path)[1]
+
+	list, err := h.listsService.UpdateList(r.Context(), h.getUserId(r), listRef, service.ListUpdateInput{
+		Name:   input.Name,
+		Public: input.Public,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	Render(w, r, http.StatusOK, list)
+}
+
+func (h *ListsHandler) DeleteList(w http.ResponseWriter, r *http.Request) {
+	listRef := listRe.FindStringSubmatch(r.URL.Path)[1]
+
+	err := h.listsService.DeleteList(r.Context(), h.getUserId(r), listRef)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type ListFilmInput struct {
+	FilmID   uuid.UUID `json:"film_id"`
+	Position int       `json:"position,omitempty"`
+	Note     string    `json:"note,omitempty"`
+}
+
+func (h *ListsHandler) AddFilm(w http.ResponseWriter, r *http.Request) {
+	var input ListFilmInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	listRef := listFilmsRe.FindStringSubmatch(r.URL.Path)[1]
+
+	err := h.listsService.AddFilm(r.Context(), h.getUserId(r), listRef, service.ListFilmInput{
+		FilmID:   input.FilmID,
+		Position: input.Position,
+		Note:     input.Note,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// ListFilmUpdateInput changes the fields that are set; an empty note clears it.
+type ListFilmUpdateInput struct {
+	Position int     `json:"position,omitempty"`
+	Note     *string `json:"note,omitempty"`
+}
+
+func (h *ListsHandler) UpdateFilm(w http.ResponseWriter, r *http.Request) {
+	var input ListFilmUpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		WriteError(w, r, "error while decoding request body", http.StatusBadRequest)
+		return
+	}
+
+	parts := listFilmIdRe.FindStringSubmatch(r.URL.Path)
+	filmId, err := uuid.Parse(parts[2])
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.listsService.UpdateFilm(r.Context(), h.getUserId(r), parts[1], service.ListFilmUpdateInput{
+		FilmID:   filmId,
+		Position: input.Position,
+		Note:     input.Note,
+	})
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ListsHandler) RemoveFilm(w http.ResponseWriter, r *http.Request) {
+	parts := listFilmIdRe.FindStringSubmatch(r.URL.Path)
+	filmId, err := uuid.Parse(parts[2])
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.listsService.RemoveFilm(r.Context(), h.getUserId(r), parts[1], filmId)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ListsHandler) GetSharedList(w http.ResponseWriter, r *http.Request) {
+	token := sharedListsRe.FindStringSubmatch(r.URL.Path)[1]
+
+	list, err := h.listsService.GetSharedList(r.Context(), token)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	list.ShareToken = ""
+
+	Render(w, r, http.StatusOK, list)
+}
+
+func (h *ListsHandler) getUserId(r *http.Request) string {
+	userId, _ := r.Context().Value("user_id").(string)
+	return userId
+}
+
+func (h *ListsHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch e := err.(type) {
+	case models.CustomError:
+		WriteError(w, r, e.Message, e.Code)
+	default:
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+	}
+}
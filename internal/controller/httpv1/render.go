@@ -0,0 +1,600 @@
+package httpv1
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"vk-test-spring/pkg/logger"
+)
+
+// flushEvery is the number of list elements written between two flushes of a list.
+const flushEvery = 100
+
+const (
+	mediaJSON    = "application/json"
+	mediaCSV     = "text/csv"
+	mediaXML     = "application/xml"
+	mediaMsgPack = "application/msgpack"
+)
+
+// format is a representation a client can ask for in the Accept header. Every format is produced
+// from the JSON encoding of the value, so field names and values are the same in all of them.
+type format struct {
+	mediaType   string
+	aliases     []string
+	contentType string
+	// listsOnly formats cannot represent a single value.
+	listsOnly  bool
+	newEncoder func(w io.Writer) encoder
+}
+
+// formats are listed by preference: when the client accepts several equally, the first one wins.
+var formats = []format{
+	{mediaType: mediaJSON, contentType: "application/json; charset=utf-8", newEncoder: newJSONEncoder},
+	{mediaType: mediaCSV, contentType: "text/csv; charset=utf-8", listsOnly: true, newEncoder: newCSVEncoder},
+	{mediaType: mediaXML, aliases: []string{"text/xml"}, contentType: "application/xml; charset=utf-8", newEncoder: newXMLEncoder},
+	{mediaType: mediaMsgPack, aliases: []string{"application/x-msgpack", "application/vnd.msgpack"}, contentType: mediaMsgPack, newEncoder: newMsgPackEncoder},
+}
+
+// encoder writes values given as JSON. A single value is written with one; a list of n elements
+// with open, an item per element and close. columns are the JSON field names of the elements.
+type encoder interface {
+	one(raw []byte) error
+	open(n int, columns []string) error
+	item(raw []byte) error
+	close() error
+}
+
+// Render writes v with code in the format negotiated from the Accept header: JSON, which is also
+// the default, XML or MessagePack. A client accepting none of them gets 406.
+func Render(w http.ResponseWriter, r *http.Request, code int, v any) {
+	f, ok := negotiate(w, r, false)
+	if !ok {
+		return
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := f.newEncoder(&body).one(raw); err != nil {
+		WriteError(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", f.contentType)
+	w.WriteHeader(code)
+	w.Write(body.Bytes())
+}
+
+// RenderList writes items with code like Render, and as CSV too, with a header row of the field
+// names and nested values as JSON. Elements are encoded and written one by one, with a flush
+// every flushEvery elements.
+func RenderList[T any](w http.ResponseWriter, r *http.Request, code int, items []T) {
+	f, ok := negotiate(w, r, true)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", f.contentType)
+	w.WriteHeader(code)
+
+	flusher, _ := w.(http.Flusher)
+	enc := f.newEncoder(w)
+
+	err := enc.open(len(items), jsonColumns(reflect.TypeOf((*T)(nil)).Elem()))
+	for i := 0; err == nil && i < len(items); i++ {
+		var raw []byte
+		if raw, err = json.Marshal(items[i]); err == nil {
+			err = enc.item(raw)
+		}
+
+		if flusher != nil && (i+1)%flushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	if err == nil {
+		err = enc.close()
+	}
+
+	// The status is sent already, the client sees a truncated body.
+	if err != nil {
+		logger.FromContext(r.Context()).Error().Err(err).Msg("error while writing list")
+	}
+}
+
+// negotiate picks the format for the response and adds Accept to Vary. Without an Accept header
+// the response is JSON. An ETag set by setVersion is scoped to the picked format. When no format
+// is acceptable, it answers 406 and returns false.
+func negotiate(w http.ResponseWriter, r *http.Request, list bool) (format, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		versionFor(w, formats[0])
+		return formats[0], true
+	}
+
+	best, bestQ := -1, 0.0
+	for i, f := range formats {
+		if f.listsOnly && !list {
+			continue
+		}
+		if q := quality(accept, f); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+
+	if best < 0 {
+		supported := make([]string, 0, len(formats))
+		for _, f := range formats {
+			if !f.listsOnly || list {
+				supported = append(supported, f.mediaType)
+			}
+		}
+
+		w.Header().Del("Last-Modified")
+		w.Header().Del("ETag")
+		WriteError(w, r, fmt.Sprintf("none of the accepted media types is supported, use one of %s",
+			strings.Join(supported, ", ")), http.StatusNotAcceptable)
+		return format{}, false
+	}
+
+	versionFor(w, formats[best])
+	return formats[best], true
+}
+
+// quality returns the weight the Accept header gives to f, taken from its most specific matching
+// media range (RFC 9110, section 12.5.1), or 0 when no range matches.
+func quality(accept string, f format) float64 {
+	q, specificity := 0.0, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		s := matches(mediaType, f)
+		if s <= specificity {
+			continue
+		}
+
+		specificity, q = s, 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+
+	return q
+}
+
+// matches returns how specifically mediaType names f: 2 for the type itself, 1 for a type/*
+// range, 0 for */*, and -1 when it does not match.
+func matches(mediaType string, f format) int {
+	for _, name := range append([]string{f.mediaType}, f.aliases...) {
+		if mediaType == name {
+			return 2
+		}
+	}
+
+	typ, _, _ := strings.Cut(f.mediaType, "/")
+	switch mediaType {
+	case typ + "/*":
+		return 1
+	case "*/*":
+		return 0
+	}
+
+	return -1
+}
+
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONEncoder(w io.Writer) encoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) one(raw []byte) error {
+	_, err := e.w.Write(raw)
+	return err
+}
+
+func (e *jsonEncoder) open(int, []string) error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder) item(raw []byte) error {
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	_, err := e.w.Write(raw)
+	return err
+}
+
+func (e *jsonEncoder) close() error {
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
+
+// csvEncoder writes a row per element. The columns are the JSON fields of the element type, so
+// every row has them all even when some are omitted from the JSON of an element. Elements that
+// are not structs have a single value column.
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func newCSVEncoder(w io.Writer) encoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) one([]byte) error {
+	return errors.New("csv only represents lists")
+}
+
+func (e *csvEncoder) open(_ int, columns []string) error {
+	e.columns = columns
+	if e.columns == nil {
+		return e.w.Write([]string{"value"})
+	}
+
+	return e.w.Write(e.columns)
+}
+
+func (e *csvEncoder) item(raw []byte) error {
+	v, err := parseJSON(raw)
+	if err != nil {
+		return err
+	}
+
+	if e.columns == nil {
+		cell, err := csvCell(v)
+		if err != nil {
+			return err
+		}
+
+		return e.w.Write([]string{cell})
+	}
+
+	fields, _ := v.(object)
+	row := make([]string, len(e.columns))
+	for i, key := range e.columns {
+		if row[i], err = csvCell(fields.get(key)); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Write(row)
+}
+
+func (e *csvEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonColumns returns the names the fields of t have in JSON, in declaration order, or nil when t
+// is not a struct.
+func jsonColumns(t reflect.Type) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+
+		switch {
+		case tag == "-":
+			continue
+		case f.Anonymous && name == "" && jsonColumns(f.Type) != nil:
+			columns = append(columns, jsonColumns(f.Type)...)
+			continue
+		case !f.IsExported():
+			continue
+		case name == "":
+			name = f.Name
+		}
+
+		columns = append(columns, name)
+	}
+
+	return columns
+}
+
+func csvCell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+}
+
+// xmlEncoder writes objects as elements named after their fields. A single value is the
+// <response> element; a list is a <response> holding an <item> per element, as are nested lists.
+type xmlEncoder struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newXMLEncoder(w io.Writer) encoder {
+	return &xmlEncoder{w: w, enc: xml.NewEncoder(w)}
+}
+
+func (e *xmlEncoder) one(raw []byte) error {
+	v, err := parseJSON(raw)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return err
+	}
+	if err := e.element("response", v); err != nil {
+		return err
+	}
+
+	return e.enc.Flush()
+}
+
+func (e *xmlEncoder) open(int, []string) error {
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return err
+	}
+
+	return e.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "response"}})
+}
+
+func (e *xmlEncoder) item(raw []byte) error {
+	v, err := parseJSON(raw)
+	if err != nil {
+		return err
+	}
+
+	if err := e.element("item", v); err != nil {
+		return err
+	}
+
+	return e.enc.Flush()
+}
+
+func (e *xmlEncoder) close() error {
+	if err := e.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "response"}}); err != nil {
+		return err
+	}
+
+	return e.enc.Flush()
+}
+
+func (e *xmlEncoder) element(name string, v any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := e.enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	var err error
+	switch v := v.(type) {
+	case nil:
+	case object:
+		for _, f := range v {
+			if err = e.element(f.key, f.value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err = e.element("item", item); err != nil {
+				return err
+			}
+		}
+	case json.Number:
+		err = e.enc.EncodeToken(xml.CharData(v.String()))
+	case string:
+		err = e.enc.EncodeToken(xml.CharData(v))
+	case bool:
+		err = e.enc.EncodeToken(xml.CharData(strconv.FormatBool(v)))
+	}
+	if err != nil {
+		return err
+	}
+
+	return e.enc.EncodeToken(start.End())
+}
+
+type msgPackEncoder struct {
+	enc *msgpack.Encoder
+}
+
+func newMsgPackEncoder(w io.Writer) encoder {
+	return &msgPackEncoder{enc: msgpack.NewEncoder(w)}
+}
+
+func (e *msgPackEncoder) one(raw []byte) error {
+	v, err := parseJSON(raw)
+	if err != nil {
+		return err
+	}
+
+	return e.value(v)
+}
+
+func (e *msgPackEncoder) open(n int, _ []string) error {
+	return e.enc.EncodeArrayLen(n)
+}
+
+func (e *msgPackEncoder) item(raw []byte) error {
+	return e.one(raw)
+}
+
+func (e *msgPackEncoder) close() error {
+	return nil
+}
+
+func (e *msgPackEncoder) value(v any) error {
+	switch v := v.(type) {
+	case nil:
+		return e.enc.EncodeNil()
+	case object:
+		if err := e.enc.EncodeMapLen(len(v)); err != nil {
+			return err
+		}
+		for _, f := range v {
+			if err := e.enc.EncodeString(f.key); err != nil {
+				return err
+			}
+			if err := e.value(f.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if err := e.enc.EncodeArrayLen(len(v)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := e.value(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return e.enc.EncodeInt(n)
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		return e.enc.EncodeFloat64(f)
+	case string:
+		return e.enc.EncodeString(v)
+	case bool:
+		return e.enc.EncodeBool(v)
+	default:
+		return fmt.Errorf("unexpected value %T", v)
+	}
+}
+
+// member is a field of a JSON object. Objects are kept as ordered fields rather than maps, so the
+// other formats list the fields in the order of the JSON encoding.
+type member struct {
+	key   string
+	value any
+}
+
+type object []member
+
+func (o object) get(key string) any {
+	for _, f := range o {
+		if f.key == key {
+			return f.value
+		}
+	}
+
+	return nil
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(f.key)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// parseJSON decodes raw into object, []any, string, json.Number, bool or nil values.
+func parseJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	return parseValue(dec)
+}
+
+func parseValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		var o object
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, member{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return o, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	default:
+		return tok, nil
+	}
+}
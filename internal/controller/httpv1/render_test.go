@@ -0,0 +1,219 @@
+package httpv1
+
+import (
+	"encoding/csv"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"vk-test-spring/internal/models"
+)
+
+var renderedFilms = []models.Film{
+	{
+		ID:     uuid.MustParse("6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e01"),
+		Name:   "Брат",
+		Date:   "1997-12-12",
+		Rating: 8.3,
+		Actors: []models.FilmActors{{ID: uuid.MustParse("6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e02"), Name: "Сергей"}},
+	},
+	{
+		ID:     uuid.MustParse("6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e03"),
+		Name:   "Асса, \"Бананан\"",
+		Date:   "1987-01-01",
+		Rating: 7,
+	},
+}
+
+func renderList(t *testing.T, accept string, items any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, "/films", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+
+	switch items := items.(type) {
+	case []models.Film:
+		RenderList(w, r, http.StatusOK, items)
+	default:
+		Render(w, r, http.StatusOK, items)
+	}
+
+	return w
+}
+
+func TestRender(t *testing.T) {
+	t.Run("json by default", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", "application/json", "application/*"} {
+			w := renderList(t, accept, renderedFilms)
+
+			assert.Equal(t, http.StatusOK, w.Code, accept)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), accept)
+			assert.Equal(t, "Accept", w.Header().Get("Vary"), accept)
+			assert.JSONEq(t, `[
+				{"id": "6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e01", "name": "Брат", "description": "", "date": "1997-12-12", "rating": 8.3,
+				 "actors": [{"id": "6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e02", "name": "Сергей", "second_name": "", "patronymic": ""}],
+				 "updated_at": "0001-01-01T00:00:00Z"},
+				{"id": "6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e03", "name": "Асса, \"Бананан\"", "description": "", "date": "1987-01-01", "rating": 7,
+				 "actors": null, "updated_at": "0001-01-01T00:00:00Z"}
+			]`, w.Body.String(), accept)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		w := renderList(t, "", []models.Film(nil))
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		w := renderList(t, "text/csv", renderedFilms)
+
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, []string{"id", "name", "description", "date", "rating", "actors", "score", "updated_at"}, rows[0])
+		assert.Equal(t, "Брат", rows[1][1])
+		assert.Equal(t, "8.3", rows[1][4])
+		assert.JSONEq(t, `[{"id": "6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e02", "name": "Сергей", "second_name": "", "patronymic": ""}]`, rows[1][5])
+		assert.Equal(t, "Асса, \"Бананан\"", rows[2][1])
+		assert.Equal(t, "", rows[2][5])
+	})
+
+	t.Run("csv columns come from the element type", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users/me/lists", nil)
+		r.Header.Set("Accept", "text/csv")
+		w := httptest.NewRecorder()
+
+		RenderList(w, r, http.StatusOK, []models.UserList{
+			{Name: "Watchlist", Kind: models.ListKindWatchlist},
+			{Name: "Кино", Public: true, ShareToken: "abc"},
+		})
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Contains(t, rows[0], "share_token")
+		column := indexOf(rows[0], "share_token")
+		assert.Equal(t, "", rows[1][column])
+		assert.Equal(t, "abc", rows[2][column])
+
+		w = renderList(t, "text/csv", []models.Film{})
+		assert.Equal(t, "id,name,description,date,rating,actors,score,updated_at\n", w.Body.String())
+	})
+
+	t.Run("csv is for lists only", func(t *testing.T) {
+		w := renderList(t, "text/csv", renderedFilms[0])
+
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		assert.Contains(t, w.Body.String(), "application/json, application/xml, application/msgpack")
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		w := renderList(t, "text/xml", renderedFilms[:1])
+
+		assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<response><item><id>6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e01</id><name>Брат</name><description></description>`+
+			`<date>1997-12-12</date><rating>8.3</rating><actors><item><id>6b1a2c54-3f3e-4d5b-9a43-1d9c5b7f1e02</id>`+
+			`<name>Сергей</name><second_name></second_name><patronymic></patronymic></item></actors>`+
+			`<updated_at>0001-01-01T00:00:00Z</updated_at></item></response>`, w.Body.String())
+
+		w = renderList(t, "application/xml", models.ListFilm{Name: "Брат", Note: "<смотреть>"})
+		assert.Contains(t, w.Body.String(), `<response><id>00000000-0000-0000-0000-000000000000</id><name>Брат</name>`)
+		assert.Contains(t, w.Body.String(), `<note>&lt;смотреть&gt;</note>`)
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		w := renderList(t, "application/x-msgpack", renderedFilms)
+
+		assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+		var films []map[string]any
+		require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &films))
+		require.Len(t, films, 2)
+		assert.Equal(t, "Брат", films[0]["name"])
+		assert.Equal(t, 8.3, films[0]["rating"])
+		assert.EqualValues(t, 7, films[1]["rating"])
+		assert.Equal(t, "Сергей", films[0]["actors"].([]any)[0].(map[string]any)["name"])
+		assert.Nil(t, films[1]["actors"])
+	})
+
+	t.Run("quality values", func(t *testing.T) {
+		cases := map[string]string{
+			"application/xml;q=0.5, application/msgpack":      "application/msgpack",
+			"text/*;q=0.9, application/json;q=0.1":            "text/csv; charset=utf-8",
+			"*/*;q=0.5, application/json;q=0":                 "text/csv; charset=utf-8",
+			"application/xml, application/json":               "application/json; charset=utf-8",
+			"text/html, application/xml;q=0.2, image/png;q=1": "application/xml; charset=utf-8",
+		}
+		for accept, contentType := range cases {
+			w := renderList(t, accept, renderedFilms)
+			assert.Equal(t, contentType, w.Header().Get("Content-Type"), accept)
+		}
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		for _, accept := range []string{"text/html", "application/json;q=0", "image/*"} {
+			w := renderList(t, accept, renderedFilms)
+
+			assert.Equal(t, http.StatusNotAcceptable, w.Code, accept)
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), accept)
+			assert.Contains(t, w.Body.String(), "text/csv", accept)
+		}
+	})
+
+	t.Run("versions are scoped to the format", func(t *testing.T) {
+		etags := make(map[string]bool)
+		for _, accept := range []string{"", "text/csv", "application/xml", "application/msgpack", "text/html"} {
+			r := httptest.NewRequest(http.MethodGet, "/films", nil)
+			r.Header.Set("Accept", accept)
+			w := httptest.NewRecorder()
+			setVersion(w, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+			RenderList(w, r, http.StatusOK, renderedFilms)
+
+			if accept == "text/html" {
+				assert.Empty(t, w.Header().Get("ETag"))
+				assert.Empty(t, w.Header().Get("Last-Modified"))
+				continue
+			}
+
+			assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"), accept)
+			etags[w.Header().Get("ETag")] = true
+		}
+
+		assert.Len(t, etags, 4)
+	})
+
+	t.Run("large lists are flushed as they are written", func(t *testing.T) {
+		films := make([]models.Film, 3*flushEvery)
+		for i := range films {
+			films[i].Name = strings.Repeat("x", i%10+1)
+		}
+
+		w := renderList(t, "text/csv", films)
+
+		assert.True(t, w.Flushed)
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, rows, len(films)+1)
+	})
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
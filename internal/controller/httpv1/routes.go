@@ -0,0 +1,91 @@
+package httpv1
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"vk-test-spring/internal/service"
+)
+
+// route is an operation a handler serves: a method on a path, answered by handle. template is the
+// path as the OpenAPI spec writes it. adminOnly operations are open to administrators only.
+type route struct {
+	method    string
+	path      *regexp.Regexp
+	template  string
+	adminOnly bool
+	handle    http.HandlerFunc
+}
+
+// Route is a path of the API in OpenAPI notation with the methods it accepts.
+type Route struct {
+	Path    string
+	Methods []string
+}
+
+// Routes lists what the films, actors, users, lists and search handlers serve, for checking the spec.
+// The handlers route requests with the same tables.
+func Routes() []Route {
+	var result []Route
+	for _, routes := range [][]route{
+		(&FilmsHandler{}).routes(),
+		(&ActorsHandler{}).routes(),
+		(&UsersHandler{}).routes(),
+		(&ListsHandler{}).routes(),
+		(&SearchHandler{}).routes(),
+	} {
+		for _, rt := range routes {
+			i := slices.IndexFunc(result, func(r Route) bool { return r.Path == rt.template })
+			if i < 0 {
+				result = append(result, Route{Path: rt.template})
+				i = len(result) - 1
+			}
+
+			result[i].Methods = append(result[i].Methods, rt.method)
+		}
+	}
+
+	for i := range result {
+		slices.Sort(result[i].Methods)
+		result[i].Methods = slices.Compact(result[i].Methods)
+	}
+
+	return result
+}
+
+// serve hands r to the first of routes that matches it, or answers with writeUnmatched.
+func serve(w http.ResponseWriter, r *http.Request, routes []route) {
+	for _, rt := range routes {
+		if rt.method == r.Method && rt.path.MatchString(r.URL.Path) &&
+			(!rt.adminOnly || service.IsAdmin(r.Context())) {
+			rt.handle(w, r)
+			return
+		}
+	}
+
+	writeUnmatched(w, r, routes...)
+}
+
+// writeUnmatched answers a request none of the routes of a handler took. A method the handler
+// accepts on the path was turned away by the role check, so it is 403; another method on a known
+// path is 405 with the Allow header; an unknown path is 404.
+func writeUnmatched(w http.ResponseWriter, r *http.Request, routes ...route) {
+	var allowed []string
+	for _, rt := range routes {
+		if rt.path.MatchString(r.URL.Path) {
+			allowed = append(allowed, rt.method)
+		}
+	}
+
+	switch {
+	case len(allowed) == 0:
+		WriteError(w, r, "Not found", http.StatusNotFound)
+	case slices.Contains(allowed, r.Method):
+		WriteError(w, r, "Forbidden", http.StatusForbidden)
+	default:
+		slices.Sort(allowed)
+		w.Header().Set("Allow", strings.Join(slices.Compact(allowed), ", "))
+		WriteError(w, r, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,111 @@
+package httpv1
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+)
+
+var (
+	searchRe  = regexp.MustCompile(`^/search/*$`)
+	suggestRe = regexp.MustCompile(`^/suggest/*$`)
+)
+
+type SearchHandler struct {
+	searchService service.Search
+}
+
+func NewSearchHandler(searchService service.Search) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, h.routes())
+}
+
+func (h *SearchHandler) routes() []route {
+	return []route{
+		{http.MethodGet, searchRe, "/search", false, h.Search},
+		{http.MethodGet, suggestRe, "/suggest", false, h.Suggest},
+	}
+}
+
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	page, err := h.getIntParam(params.Get("page"), "page")
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := h.getIntParam(params.Get("limit"), "limit")
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.searchService.Search(r.Context(), service.SearchInput{
+		Query: params.Get("q"),
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		switch e := err.(type) {
+		case models.CustomError:
+			WriteError(w, r, e.Message, e.Code)
+			return
+		default:
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	Render(w, r, http.StatusOK, result)
+}
+
+func (h *SearchHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	limit, err := h.getIntParam(params.Get("limit"), "limit")
+	if err != nil {
+		WriteError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	suggestions, err := h.searchService.Suggest(r.Context(), service.SuggestInput{
+		Query: params.Get("q"),
+		Type:  params.Get("type"),
+		Limit: limit,
+	})
+	if err != nil {
+		switch e := err.(type) {
+		case models.CustomError:
+			WriteError(w, r, e.Message, e.Code)
+			return
+		default:
+			WriteError(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	RenderList(w, r, http.StatusOK, suggestions)
+}
+
+func (h *SearchHandler) getIntParam(value string, name string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v parameter. must be integer, but has: %v", name, value)
+	}
+
+	return n, nil
+}
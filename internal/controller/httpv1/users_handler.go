@@ -22,19 +22,29 @@ func NewUsersHandler(usersService service.Users) *UsersHandler {
 }
 
 func (h *UsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, h.routes())
+}
 
+func (h *UsersHandler) routes() []route {
+	return []route{
+		{http.MethodPost, users, "/users", false, h.CreateUser},
+		{http.MethodDelete, usersId, "/users/{id}", false, h.DeleteUser},
+		{http.MethodPatch, usersId, "/users/{id}", false, h.ChangeRole},
+	}
 }
 
+// CreateUser, DeleteUser and ChangeRole answer 501: user management is not implemented yet, and an
+// empty 200 would read as success.
 func (h *UsersHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-
+	WriteError(w, r, "user management is not implemented", http.StatusNotImplemented)
 }
 
 func (h *UsersHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-
+	WriteError(w, r, "user management is not implemented", http.StatusNotImplemented)
 }
 
 func (h *UsersHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
-
+	WriteError(w, r, "user management is not implemented", http.StatusNotImplemented)
 }
 
 func (h *UsersHandler) GetRole(username string, password string) (string, string, error) {
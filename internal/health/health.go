@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports a component as healthy by returning nil.
+type Check func(ctx context.Context) error
+
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Health serves liveness and readiness probes. Readiness is reported only between MarkStarted
+// and MarkShuttingDown, and only while every registered check passes.
+type Health struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	checks       []namedCheck
+	started      atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+func New(timeout time.Duration) *Health {
+	return &Health{
+		timeout: timeout,
+	}
+}
+
+// AddCheck registers a readiness check. Checks may be added while the probes are already served,
+// e.g. once the component they watch has been initialized.
+func (h *Health) AddCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+func (h *Health) MarkStarted() {
+	h.started.Store(true)
+}
+
+func (h *Health) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Ready runs every check concurrently, each bounded by the configured timeout.
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := h.checks
+	h.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Components: make(map[string]ComponentStatus, len(checks)+1)}
+
+	switch {
+	case h.shuttingDown.Load():
+		report.Components["lifecycle"] = ComponentStatus{Status: StatusDown, Error: "shutting down"}
+	case !h.started.Load():
+		report.Components["lifecycle"] = ComponentStatus{Status: StatusDown, Error: "starting"}
+	default:
+		report.Components["lifecycle"] = ComponentStatus{Status: StatusUp}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+
+			status := ComponentStatus{Status: StatusUp}
+			if err := c.check(ctx); err != nil {
+				status = ComponentStatus{Status: StatusDown, Error: err.Error()}
+			}
+
+			mu.Lock()
+			report.Components[c.name] = status
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	for _, status := range report.Components {
+		if status.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// LivenessHandler answers 200 as long as the process can serve HTTP. It does not look at
+// dependencies, so a database outage makes the app unready but never gets it restarted.
+func (h *Health) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: StatusUp})
+	})
+}
+
+// ReadinessHandler answers 200 when Ready reports up and 503 otherwise, with the report as the body.
+func (h *Health) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.Ready(r.Context()))
+	})
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	code := http.StatusOK
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func probe(t *testing.T, handler http.Handler) (int, Report) {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+
+	return w.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	t.Run("not ready until started", func(t *testing.T) {
+		h := New(time.Second)
+		h.AddCheck("database", func(ctx context.Context) error { return nil })
+
+		code, report := probe(t, h.ReadinessHandler())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, ComponentStatus{Status: StatusDown, Error: "starting"}, report.Components["lifecycle"])
+
+		h.MarkStarted()
+
+		code, report = probe(t, h.ReadinessHandler())
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Equal(t, ComponentStatus{Status: StatusUp}, report.Components["database"])
+	})
+
+	t.Run("failing check", func(t *testing.T) {
+		h := New(time.Second)
+		h.AddCheck("database", func(ctx context.Context) error { return nil })
+		h.AddCheck("schema", func(ctx context.Context) error { return errors.New("schema version 0, expected 1") })
+		h.MarkStarted()
+
+		code, report := probe(t, h.ReadinessHandler())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, ComponentStatus{Status: StatusUp}, report.Components["database"])
+		assert.Equal(t, ComponentStatus{Status: StatusDown, Error: "schema version 0, expected 1"}, report.Components["schema"])
+	})
+
+	t.Run("slow check times out", func(t *testing.T) {
+		h := New(10 * time.Millisecond)
+		h.AddCheck("database", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		h.MarkStarted()
+
+		code, report := probe(t, h.ReadinessHandler())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, StatusDown, report.Components["database"].Status)
+	})
+
+	t.Run("not ready while shutting down", func(t *testing.T) {
+		h := New(time.Second)
+		h.MarkStarted()
+		h.MarkShuttingDown()
+
+		code, report := probe(t, h.ReadinessHandler())
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, ComponentStatus{Status: StatusDown, Error: "shutting down"}, report.Components["lifecycle"])
+
+		code, report = probe(t, h.LivenessHandler())
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, StatusUp, report.Status)
+	})
+}
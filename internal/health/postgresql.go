@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DatabasePing checks that a connection can be acquired and answers.
+func DatabasePing(pool *pgxpool.Pool) Check {
+	return func(ctx context.Context) error {
+		return pool.Ping(ctx)
+	}
+}
+
+// SchemaVersion checks that the database was built from the expected revision of the schema script
+// and that no migration was left half applied.
+func SchemaVersion(pool *pgxpool.Pool, expected int64) Check {
+	return func(ctx context.Context) error {
+		var version int64
+		var dirty bool
+
+		err := pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1`).Scan(&version, &dirty)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("no schema version recorded, expected %d", expected)
+			}
+
+			return err
+		}
+
+		if dirty {
+			return fmt.Errorf("schema version %d is dirty", version)
+		}
+
+		if version != expected {
+			return fmt.Errorf("schema version %d, expected %d", version, expected)
+		}
+
+		return nil
+	}
+}
+
+// PoolSaturation fails when the share of acquired connections reaches threshold,
+// so traffic is moved away before requests start queueing for connections.
+func PoolSaturation(pool *pgxpool.Pool, threshold float64) Check {
+	return func(ctx context.Context) error {
+		stat := pool.Stat()
+		if stat.MaxConns() == 0 {
+			return nil
+		}
+
+		if float64(stat.AcquiredConns()) >= threshold*float64(stat.MaxConns()) {
+			return fmt.Errorf("pool saturated: %d of %d connections in use", stat.AcquiredConns(), stat.MaxConns())
+		}
+
+		return nil
+	}
+}
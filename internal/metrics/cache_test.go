@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+	"vk-test-spring/pkg/cache"
+)
+
+func TestCacheCollector(t *testing.T) {
+	c := cache.NewLRU(10, time.Minute)
+	c.Set("films:all", []byte("[]"), "films")
+	c.Get("films:all")
+	c.Get("films:all")
+	c.Get("actors:all")
+
+	expected := `
+# HELP films_library_cache_entries Number of entries currently stored.
+# TYPE films_library_cache_entries gauge
+films_library_cache_entries 1
+# HELP films_library_cache_hits_total Number of cache hits.
+# TYPE films_library_cache_hits_total counter
+films_library_cache_hits_total 2
+# HELP films_library_cache_misses_total Number of cache misses.
+# TYPE films_library_cache_misses_total counter
+films_library_cache_misses_total 1
+`
+
+	err := testutil.CollectAndCompare(NewCacheCollector(c), strings.NewReader(expected),
+		"films_library_cache_entries", "films_library_cache_hits_total", "films_library_cache_misses_total")
+	assert.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/google/uuid"
+	"time"
 )
 
 type Actor struct {
@@ -12,6 +13,8 @@ type Actor struct {
 	Sex         string      `json:"sex"`
 	DateOfBirth string      `json:"date_of_birth"`
 	Films       []ActorFilm `json:"films"`
+	Score       float64     `json:"score,omitempty"`
+	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
 type ActorFilm struct {
@@ -1,6 +1,9 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type Film struct {
 	ID          uuid.UUID    `json:"id,omitempty"`
@@ -9,6 +12,8 @@ type Film struct {
 	Date        string       `json:"date"`
 	Rating      float64      `json:"rating"`
 	Actors      []FilmActors `json:"actors"`
+	Score       float64      `json:"score,omitempty"`
+	UpdatedAt   time.Time    `json:"updated_at"`
 }
 
 type FilmActors struct {
@@ -0,0 +1,32 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	ListKindCustom     = "custom"
+	ListKindWatchlist  = "watchlist"
+	ListKindFavourites = "favourites"
+	ListKindSeen       = "seen"
+)
+
+type UserList struct {
+	ID         uuid.UUID  `json:"id,omitempty"`
+	UserID     uuid.UUID  `json:"-"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Public     bool       `json:"public"`
+	ShareToken string     `json:"share_token,omitempty"`
+	CreatedAt  string     `json:"created_at"`
+	FilmsCount int        `json:"films_count"`
+	Films      []ListFilm `json:"films,omitempty"`
+}
+
+type ListFilm struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Date     string    `json:"date"`
+	Rating   float64   `json:"rating"`
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+	AddedAt  string    `json:"added_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "github.com/google/uuid"
+
+const (
+	SearchTypeFilm  = "film"
+	SearchTypeActor = "actor"
+)
+
+type SearchResult struct {
+	Type     string    `json:"type"`
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Headline string    `json:"headline"`
+	Rank     float64   `json:"rank"`
+}
+
+type SearchPage struct {
+	Items []SearchResult `json:"items"`
+	Total int            `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+type Suggestion struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Year int       `json:"year"`
+}
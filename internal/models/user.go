@@ -2,6 +2,12 @@ package models
 
 import "github.com/google/uuid"
 
+// Roles of users. Administrators may also change films and actors.
+const (
+	RoleUser  = "пользователь"
+	RoleAdmin = "администратор"
+)
+
 type User struct {
 	ID   uuid.UUID `json:"id,omitempty"`
 	Name string    `json:"name"`
@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/models"
+)
+
+type ActorsRepo struct {
+	store  *Store
+	search config.SearchConfig
+}
+
+func NewActorsRepo(store *Store, search config.SearchConfig) *ActorsRepo {
+	return &ActorsRepo{
+		store:  store,
+		search: search,
+	}
+}
+
+func (r *ActorsRepo) Create(ctx context.Context, a models.Actor, actorFilms []uuid.UUID) (uuid.UUID, error) {
+	id := uuid.New()
+
+	err := r.store.WithinTx(ctx, func(ctx context.Context) error {
+		birthday, err := normalizeDate(a.DateOfBirth)
+		if err != nil {
+			return err
+		}
+
+		at := r.store.change()
+		r.store.actors[id] = actor{
+			Actor: models.Actor{
+				ID:          id,
+				Name:        a.Name,
+				SecondName:  a.SecondName,
+				Patronymic:  a.Patronymic,
+				Sex:         a.Sex,
+				DateOfBirth: birthday,
+				UpdatedAt:   at,
+			},
+			seq: r.store.nextSeq(),
+		}
+
+		for _, f := range actorFilms {
+			err := r.store.insertLink(link{actor: id, film: f}, at)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+func (r *ActorsRepo) Edit(ctx context.Context, a models.Actor, filmsToAdd []uuid.UUID, filmsToDel []uuid.UUID) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		birthday, err := normalizeDate(a.DateOfBirth)
+		if err != nil {
+			return err
+		}
+
+		at := r.store.change()
+		if stored, ok := r.store.actors[a.ID]; ok {
+			stored.Name = a.Name
+			stored.SecondName = a.SecondName
+			stored.Patronymic = a.Patronymic
+			stored.Sex = a.Sex
+			stored.DateOfBirth = birthday
+			stored.UpdatedAt = at
+			r.store.actors[a.ID] = stored
+
+			for l := range r.store.links {
+				if l.actor == a.ID {
+					r.store.touch(l, at)
+				}
+			}
+		}
+
+		for _, f := range filmsToAdd {
+			err := r.store.insertLink(link{actor: a.ID, film: f}, at)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, f := range filmsToDel {
+			r.store.deleteLink(link{actor: a.ID, film: f}, at)
+		}
+
+		return nil
+	})
+}
+
+func (r *ActorsRepo) Delete(ctx context.Context, actorId uuid.UUID) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		if _, ok := r.store.actors[actorId]; !ok {
+			return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found actor with this id: %v", actorId)}
+		}
+
+		at := r.store.change()
+		for l := range r.store.links {
+			if l.actor == actorId {
+				r.store.deleteLink(l, at)
+			}
+		}
+		delete(r.store.actors, actorId)
+
+		return nil
+	})
+}
+
+// LastModified returns when the actors, their films or the links between them last changed.
+func (r *ActorsRepo) LastModified(ctx context.Context) (time.Time, error) {
+	defer r.store.rlock(ctx)()
+
+	return r.store.changed, nil
+}
+
+// GetAllActors returns the actors in the order they were created.
+func (r *ActorsRepo) GetAllActors(ctx context.Context) ([]models.Actor, error) {
+	defer r.store.rlock(ctx)()
+
+	return r.withFilms(r.actors()), nil
+}
+
+func (r *ActorsRepo) GetActorsByName(ctx context.Context, name string) ([]models.Actor, error) {
+	defer r.store.rlock(ctx)()
+
+	query := toLatin(name)
+	actors := make([]actor, 0)
+	for _, a := range r.actors() {
+		a.Score = wordSimilarity(query, actorNameLatin(a.Actor))
+		if a.Score >= r.search.SimilarityThreshold {
+			actors = append(actors, a)
+		}
+	}
+
+	slices.SortStableFunc(actors, func(a, b actor) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.SecondName, b.SecondName); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return r.withFilms(actors), nil
+}
+
+func (r *ActorsRepo) GetActorById(ctx context.Context, actorId uuid.UUID) (models.Actor, error) {
+	defer r.store.rlock(ctx)()
+
+	a, ok := r.store.actors[actorId]
+	if !ok {
+		return models.Actor{}, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found actor with this id: %v", actorId)}
+	}
+
+	return r.withFilms([]actor{a})[0], nil
+}
+
+// GetActorsByIds returns the actors with the given ids in the order of ids, skipping the ones that
+// do not exist.
+func (r *ActorsRepo) GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error) {
+	defer r.store.rlock(ctx)()
+
+	actors := make([]actor, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := r.store.actors[id]; ok {
+			actors = append(actors, a)
+		}
+	}
+
+	return r.withFilms(actors), nil
+}
+
+func (r *ActorsRepo) actors() []actor {
+	actors := make([]actor, 0, len(r.store.actors))
+	for _, a := range r.store.actors {
+		actors = append(actors, a)
+	}
+	slices.SortFunc(actors, func(a, b actor) int { return cmp.Compare(a.seq, b.seq) })
+
+	return actors
+}
+
+func (r *ActorsRepo) withFilms(actors []actor) []models.Actor {
+	result := make([]models.Actor, 0, len(actors))
+	for _, a := range actors {
+		a.Films = r.store.actorFilms(a.ID)
+		result = append(result, a.Actor)
+	}
+
+	return result
+}
+
+// actorNameLatin is the name_latin column of actors.
+func actorNameLatin(a models.Actor) string {
+	return toLatin(strings.Join([]string{a.Name, a.SecondName, a.Patronymic}, " "))
+}
@@ -0,0 +1,25 @@
+package memory_test
+
+import (
+	"testing"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/repository/memory"
+	"vk-test-spring/internal/repository/repositorytest"
+)
+
+func TestContract(t *testing.T) {
+	search := config.SearchConfig{SimilarityThreshold: repositorytest.SimilarityThreshold}
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Fixture {
+		store := memory.NewStore()
+
+		return repositorytest.Fixture{
+			Films:  memory.NewFilmsRepo(store, search),
+			Actors: memory.NewActorsRepo(store, search),
+			Users:  memory.NewUsersRepo(store),
+			AddUser: func(t *testing.T, name string, password string, role string) string {
+				return store.AddUser(name, repositorytest.PasswordHash(t, password), role).String()
+			},
+		}
+	})
+}
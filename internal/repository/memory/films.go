@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/models"
+)
+
+type FilmsRepo struct {
+	store  *Store
+	search config.SearchConfig
+}
+
+func NewFilmsRepo(store *Store, search config.SearchConfig) *FilmsRepo {
+	return &FilmsRepo{
+		store:  store,
+		search: search,
+	}
+}
+
+func (r *FilmsRepo) Create(ctx context.Context, f models.Film, actors []uuid.UUID) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		date, err := normalizeDate(f.Date)
+		if err != nil {
+			return err
+		}
+
+		at := r.store.change()
+		id := uuid.New()
+		r.store.films[id] = film{
+			Film: models.Film{
+				ID:          id,
+				Name:        f.Name,
+				Description: f.Description,
+				Date:        date,
+				Rating:      f.Rating,
+				UpdatedAt:   at,
+			},
+			seq: r.store.nextSeq(),
+		}
+
+		for _, a := range actors {
+			err := r.store.insertLink(link{actor: a, film: id}, at)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *FilmsRepo) Update(ctx context.Context, f models.Film, actorsToAdd []uuid.UUID, actorsToDel []uuid.UUID) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		date, err := normalizeDate(f.Date)
+		if err != nil {
+			return err
+		}
+
+		at := r.store.change()
+		if stored, ok := r.store.films[f.ID]; ok {
+			stored.Name = f.Name
+			stored.Description = f.Description
+			stored.Date = date
+			stored.Rating = f.Rating
+			stored.UpdatedAt = at
+			r.store.films[f.ID] = stored
+
+			for l := range r.store.links {
+				if l.film == f.ID {
+					r.store.touch(l, at)
+				}
+			}
+		}
+
+		for _, a := range actorsToAdd {
+			err := r.store.insertLink(link{actor: a, film: f.ID}, at)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, a := range actorsToDel {
+			r.store.deleteLink(link{actor: a, film: f.ID}, at)
+		}
+
+		return nil
+	})
+}
+
+func (r *FilmsRepo) Delete(ctx context.Context, filmId uuid.UUID) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		if _, ok := r.store.films[filmId]; !ok {
+			return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film with this id: %v", filmId)}
+		}
+
+		at := r.store.change()
+		for l := range r.store.links {
+			if l.film == filmId {
+				r.store.deleteLink(l, at)
+			}
+		}
+		delete(r.store.films, filmId)
+
+		return nil
+	})
+}
+
+// LastModified returns when the films, their actors or the links between them last changed.
+func (r *FilmsRepo) LastModified(ctx context.Context) (time.Time, error) {
+	defer r.store.rlock(ctx)()
+
+	return r.store.changed, nil
+}
+
+// GetAllFilms orders films by the "sort" (name, date or rating) and "order" (ASC or DESC) values
+// of ctx, as the handler sets them. Without them films come in the order they were created.
+func (r *FilmsRepo) GetAllFilms(ctx context.Context) ([]models.Film, error) {
+	defer r.store.rlock(ctx)()
+
+	films := r.films()
+
+	sortField, _ := ctx.Value("sort").(string)
+	order, _ := ctx.Value("order").(string)
+
+	compare := map[string]func(a, b film) int{
+		"name":   func(a, b film) int { return strings.Compare(a.Name, b.Name) },
+		"date":   func(a, b film) int { return strings.Compare(a.Date, b.Date) },
+		"rating": func(a, b film) int { return cmp.Compare(a.Rating, b.Rating) },
+	}[sortField]
+	if compare != nil {
+		slices.SortStableFunc(films, func(a, b film) int {
+			if strings.EqualFold(order, "desc") {
+				return compare(b, a)
+			}
+
+			return compare(a, b)
+		})
+	}
+
+	return r.withActors(films), nil
+}
+
+func (r *FilmsRepo) GetFilmByName(ctx context.Context, name string) ([]models.Film, error) {
+	defer r.store.rlock(ctx)()
+
+	query := toLatin(name)
+	films := make([]film, 0)
+	for _, f := range r.films() {
+		f.Score = wordSimilarity(query, toLatin(f.Name))
+		if f.Score >= r.search.SimilarityThreshold {
+			films = append(films, f)
+		}
+	}
+
+	sortByScore(films)
+
+	return r.withActors(films), nil
+}
+
+func (r *FilmsRepo) GetFilmByActor(ctx context.Context, actorName string) ([]models.Film, error) {
+	defer r.store.rlock(ctx)()
+
+	query := toLatin(actorName)
+	scores := make(map[uuid.UUID]float64)
+	for l := range r.store.links {
+		a := r.store.actors[l.actor]
+
+		score := wordSimilarity(query, actorNameLatin(a.Actor))
+		if score >= r.search.SimilarityThreshold {
+			scores[l.film] = max(scores[l.film], score)
+		}
+	}
+
+	films := make([]film, 0)
+	for _, f := range r.films() {
+		if score, ok := scores[f.ID]; ok {
+			f.Score = score
+			films = append(films, f)
+		}
+	}
+
+	sortByScore(films)
+
+	return r.withActors(films), nil
+}
+
+func (r *FilmsRepo) GetFilmById(ctx context.Context, filmId uuid.UUID) (models.Film, error) {
+	defer r.store.rlock(ctx)()
+
+	f, ok := r.store.films[filmId]
+	if !ok {
+		return models.Film{}, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film with this id: %v", filmId)}
+	}
+
+	return r.withActors([]film{f})[0], nil
+}
+
+// GetFilmsByIds returns the films with the given ids in the order of ids, skipping the ones that do
+// not exist.
+func (r *FilmsRepo) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error) {
+	defer r.store.rlock(ctx)()
+
+	films := make([]film, 0, len(ids))
+	for _, id := range ids {
+		if f, ok := r.store.films[id]; ok {
+			films = append(films, f)
+		}
+	}
+
+	return r.withActors(films), nil
+}
+
+// films returns every film in the order they were created.
+func (r *FilmsRepo) films() []film {
+	films := make([]film, 0, len(r.store.films))
+	for _, f := range r.store.films {
+		films = append(films, f)
+	}
+	slices.SortFunc(films, func(a, b film) int { return cmp.Compare(a.seq, b.seq) })
+
+	return films
+}
+
+func (r *FilmsRepo) withActors(films []film) []models.Film {
+	result := make([]models.Film, 0, len(films))
+	for _, f := range films {
+		f.Actors = r.store.filmActors(f.ID)
+		result = append(result, f.Film)
+	}
+
+	return result
+}
+
+func sortByScore(films []film) {
+	slices.SortStableFunc(films, func(a, b film) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"testing"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/models"
+)
+
+var search = config.SearchConfig{SimilarityThreshold: 0.3}
+
+func newActor(t *testing.T, repo *ActorsRepo, name string, secondName string) uuid.UUID {
+	id, err := repo.Create(context.Background(), models.Actor{
+		Name:        name,
+		SecondName:  secondName,
+		Patronymic:  "",
+		Sex:         "Мужчина",
+		DateOfBirth: "1970-01-01",
+	}, nil)
+	require.NoError(t, err)
+
+	return id
+}
+
+func newFilm(t *testing.T, repo *FilmsRepo, name string, date string, rating float64, actors ...uuid.UUID) uuid.UUID {
+	require.NoError(t, repo.Create(context.Background(), models.Film{Name: name, Description: "d", Date: date, Rating: rating}, actors))
+
+	films, err := repo.GetAllFilms(context.Background())
+	require.NoError(t, err)
+
+	return films[len(films)-1].ID
+}
+
+func TestFilmsRepo(t *testing.T) {
+	t.Run("links", func(t *testing.T) {
+		store := NewStore()
+		films, actors := NewFilmsRepo(store, search), NewActorsRepo(store, search)
+
+		actor := newActor(t, actors, "Сергей", "Бодров")
+		film := newFilm(t, films, "Брат", "1997-12-12", 8.5, actor)
+
+		got, err := films.GetFilmById(context.Background(), film)
+		require.NoError(t, err)
+		require.Len(t, got.Actors, 1)
+		assert.Equal(t, actor, got.Actors[0].ID)
+
+		a, err := actors.GetActorById(context.Background(), actor)
+		require.NoError(t, err)
+		assert.Equal(t, []models.ActorFilm{{ID: film, Name: "Брат"}}, a.Films)
+
+		require.NoError(t, films.Delete(context.Background(), film))
+
+		a, err = actors.GetActorById(context.Background(), actor)
+		require.NoError(t, err)
+		assert.Nil(t, a.Films)
+		assert.True(t, a.UpdatedAt.After(got.UpdatedAt) || a.UpdatedAt.Equal(got.UpdatedAt))
+	})
+
+	t.Run("failed write changes nothing", func(t *testing.T) {
+		store := NewStore()
+		films := NewFilmsRepo(store, search)
+
+		err := films.Create(context.Background(), models.Film{Name: "Брат", Description: "d", Date: "1997-12-12"}, []uuid.UUID{uuid.New()})
+		assert.Error(t, err)
+
+		all, err := films.GetAllFilms(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("duplicate link", func(t *testing.T) {
+		store := NewStore()
+		films, actors := NewFilmsRepo(store, search), NewActorsRepo(store, search)
+
+		actor := newActor(t, actors, "Сергей", "Бодров")
+		film := newFilm(t, films, "Брат", "1997-12-12", 8.5, actor)
+
+		err := films.Update(context.Background(), models.Film{ID: film, Name: "Брат", Description: "d", Date: "1997-12-12"}, []uuid.UUID{actor}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		films := NewFilmsRepo(NewStore(), search)
+
+		_, err := films.GetFilmById(context.Background(), uuid.New())
+		var customErr models.CustomError
+		require.True(t, errors.As(err, &customErr))
+		assert.Equal(t, http.StatusNotFound, customErr.Code)
+
+		err = films.Delete(context.Background(), uuid.New())
+		require.True(t, errors.As(err, &customErr))
+		assert.Equal(t, http.StatusNotFound, customErr.Code)
+	})
+
+	t.Run("sorting", func(t *testing.T) {
+		films := NewFilmsRepo(NewStore(), search)
+		newFilm(t, films, "Брат", "1997-12-12", 8.5)
+		newFilm(t, films, "Астрал", "2010-09-14", 6.8)
+		newFilm(t, films, "Война", "2002-03-14", 7.2)
+
+		names := func(sort string, order string) []string {
+			ctx := context.WithValue(context.Background(), "sort", sort)
+			ctx = context.WithValue(ctx, "order", order)
+
+			all, err := films.GetAllFilms(ctx)
+			require.NoError(t, err)
+
+			var result []string
+			for _, f := range all {
+				result = append(result, f.Name)
+			}
+			return result
+		}
+
+		assert.Equal(t, []string{"Астрал", "Брат", "Война"}, names("name", "ASC"))
+		assert.Equal(t, []string{"Астрал", "Война", "Брат"}, names("date", "DESC"))
+		assert.Equal(t, []string{"Брат", "Война", "Астрал"}, names("rating", "DESC"))
+	})
+
+	t.Run("search by name and actor", func(t *testing.T) {
+		store := NewStore()
+		films, actors := NewFilmsRepo(store, search), NewActorsRepo(store, search)
+
+		actor := newActor(t, actors, "Сергей", "Бодров")
+		newFilm(t, films, "Брат", "1997-12-12", 8.5, actor)
+		newFilm(t, films, "Брат 2", "2000-05-11", 8.0, actor)
+		newFilm(t, films, "Астрал", "2010-09-14", 6.8)
+
+		byName, err := films.GetFilmByName(context.Background(), "brat")
+		require.NoError(t, err)
+		require.Len(t, byName, 2)
+		assert.Equal(t, "Брат", byName[0].Name)
+
+		byActor, err := films.GetFilmByActor(context.Background(), "Бодров")
+		require.NoError(t, err)
+		assert.Len(t, byActor, 2)
+
+		none, err := films.GetFilmByName(context.Background(), "matrix")
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestStoreWithinTx(t *testing.T) {
+	store := NewStore()
+	films, actors := NewFilmsRepo(store, search), NewActorsRepo(store, search)
+
+	err := store.WithinTx(context.Background(), func(ctx context.Context) error {
+		_, err := actors.Create(ctx, models.Actor{Name: "Сергей", SecondName: "Бодров", Sex: "Мужчина", DateOfBirth: "1971-12-27"}, nil)
+		if err != nil {
+			return err
+		}
+
+		err = store.WithinTx(ctx, func(ctx context.Context) error {
+			return films.Create(ctx, models.Film{Name: "Брат", Date: "not a date"}, nil)
+		})
+		assert.Error(t, err)
+
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+
+	all, err := actors.GetAllActors(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestUsersRepo(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	store := NewStore()
+	id := store.AddUser("admin", string(hash), "администратор")
+	users := NewUsersRepo(store)
+
+	userId, role, err := users.GetUserIdRole("admin", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, id.String(), userId)
+	assert.Equal(t, "администратор", role)
+
+	_, _, err = users.GetUserIdRole("admin", "wrong")
+	assert.Error(t, err)
+
+	require.NoError(t, users.Delete(context.Background(), userId))
+	_, _, err = users.GetUserIdRole("admin", "secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliteration mirrors the transliteration table of the schema.
+var transliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// toLatin is the Go counterpart of the to_latin SQL function.
+func toLatin(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if latin, ok := transliteration[unicode.ToLower(r)]; ok {
+			b.WriteString(latin)
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return strings.ToLower(b.String())
+}
+
+// trigrams splits s into words of letters and digits and returns their trigrams in order,
+// each word padded with two spaces in front and one behind, as pg_trgm does.
+func trigrams(s string) []string {
+	var result []string
+
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			result = append(result, string(padded[i:i+3]))
+		}
+	}
+
+	return result
+}
+
+// wordSimilarity is the pg_trgm word_similarity(query, target): the greatest similarity between
+// the trigrams of query and any continuous extent of the trigrams of target.
+func wordSimilarity(query string, target string) float64 {
+	queryTrigrams := make(map[string]struct{})
+	for _, t := range trigrams(query) {
+		queryTrigrams[t] = struct{}{}
+	}
+	if len(queryTrigrams) == 0 {
+		return 0
+	}
+
+	targetTrigrams := trigrams(target)
+
+	var best float64
+	for lower := range targetTrigrams {
+		if _, ok := queryTrigrams[targetTrigrams[lower]]; !ok {
+			continue
+		}
+
+		extent := make(map[string]struct{})
+		common := 0
+		for upper := lower; upper < len(targetTrigrams); upper++ {
+			t := targetTrigrams[upper]
+			if _, seen := extent[t]; !seen {
+				extent[t] = struct{}{}
+				if _, ok := queryTrigrams[t]; ok {
+					common++
+				}
+			}
+
+			similarity := float64(common) / float64(len(queryTrigrams)+len(extent)-common)
+			best = max(best, similarity)
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestToLatin(t *testing.T) {
+	assert.Equal(t, "bodrov sergey", toLatin("Бодров Сергей"))
+	assert.Equal(t, "shchuka 2", toLatin("Щука 2"))
+	assert.Equal(t, "obekt", toLatin("Объект"))
+	assert.Equal(t, "matrix", toLatin("Matrix"))
+}
+
+func TestWordSimilarity(t *testing.T) {
+	tests := []struct {
+		query    string
+		target   string
+		expected float64
+	}{
+		{query: "word", target: "two words", expected: 0.8},
+		{query: "word", target: "word", expected: 1},
+		{query: "brat", target: "brat 2", expected: 1},
+		{query: "", target: "brat", expected: 0},
+		{query: "xyz", target: "brat", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+" in "+tt.target, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, wordSimilarity(tt.query, tt.target), 1e-9)
+		})
+	}
+}
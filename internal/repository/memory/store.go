@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+	"vk-test-spring/internal/models"
+)
+
+// Store keeps films, actors, their links and users in memory. It enforces what the schema
+// enforces: links need both sides to exist and are unique, deleting a record deletes its links,
+// and changing a record or its links moves updated_at of everything involved.
+type Store struct {
+	mu     sync.RWMutex
+	films  map[uuid.UUID]film
+	actors map[uuid.UUID]actor
+	links  map[link]struct{}
+	users  map[uuid.UUID]user
+	seq    uint64
+	// changed is when films, actors or links were last written, like the table_changes rows.
+	changed time.Time
+}
+
+type film struct {
+	models.Film
+	seq uint64
+}
+
+type actor struct {
+	models.Actor
+	seq uint64
+}
+
+type link struct {
+	actor uuid.UUID
+	film  uuid.UUID
+}
+
+type user struct {
+	models.User
+	passwordHash string
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{
+		films:  make(map[uuid.UUID]film),
+		actors: make(map[uuid.UUID]actor),
+		links:  make(map[link]struct{}),
+		users:  make(map[uuid.UUID]user),
+	}
+}
+
+// AddUser registers a user able to authenticate with name and the password of the bcrypt
+// passwordHash and returns its id.
+func (s *Store) AddUser(name string, passwordHash string, role string) uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.New()
+	s.users[id] = user{User: models.User{ID: id, Name: name, Role: role}, passwordHash: passwordHash}
+
+	return id
+}
+
+type txKey struct{}
+
+// WithinTx runs fn with the store locked for it alone and undoes every change of fn when it
+// returns an error. Nested calls undo only their own changes, like a savepoint. Every write of
+// the repositories runs in it, so a failing write changes nothing, as in its own SQL transaction.
+func (s *Store) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !s.inTx(ctx) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		ctx = context.WithValue(ctx, txKey{}, s)
+	}
+
+	films, actors, links, users, seq, changed := maps.Clone(s.films), maps.Clone(s.actors), maps.Clone(s.links), maps.Clone(s.users), s.seq, s.changed
+
+	if err := fn(ctx); err != nil {
+		s.films, s.actors, s.links, s.users, s.seq, s.changed = films, actors, links, users, seq, changed
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) inTx(ctx context.Context) bool {
+	store, _ := ctx.Value(txKey{}).(*Store)
+	return store == s
+}
+
+// rlock locks the store for reading, unless ctx runs in a transaction that already holds it.
+func (s *Store) rlock(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+func (s *Store) nextSeq() uint64 {
+	s.seq++
+	return s.seq
+}
+
+// change records a write to films, actors or links and returns its time, which is always later than
+// the time of the previous write, like table_changes_touch.
+func (s *Store) change() time.Time {
+	at := now()
+	if next := s.changed.Add(time.Microsecond); at.Before(next) {
+		at = next
+	}
+
+	s.changed = at
+	return at
+}
+
+// now mimics timestamptz: microsecond precision, no monotonic clock reading.
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// insertLink fails like the actors_films constraints when a side is missing or the link exists.
+func (s *Store) insertLink(l link, at time.Time) error {
+	if _, ok := s.actors[l.actor]; !ok {
+		return fmt.Errorf("insert on actors_films violates foreign key constraint: actor %v does not exist", l.actor)
+	}
+
+	if _, ok := s.films[l.film]; !ok {
+		return fmt.Errorf("insert on actors_films violates foreign key constraint: film %v does not exist", l.film)
+	}
+
+	if _, ok := s.links[l]; ok {
+		return fmt.Errorf("duplicate key value violates unique constraint actors_films_pkey: actor %v, film %v", l.actor, l.film)
+	}
+
+	s.links[l] = struct{}{}
+	s.touch(l, at)
+
+	return nil
+}
+
+func (s *Store) deleteLink(l link, at time.Time) {
+	if _, ok := s.links[l]; !ok {
+		return
+	}
+
+	delete(s.links, l)
+	s.touch(l, at)
+}
+
+// touch moves updated_at of both sides of l, like the actors_films_touch trigger.
+func (s *Store) touch(l link, at time.Time) {
+	if f, ok := s.films[l.film]; ok {
+		f.UpdatedAt = at
+		s.films[l.film] = f
+	}
+
+	if a, ok := s.actors[l.actor]; ok {
+		a.UpdatedAt = at
+		s.actors[l.actor] = a
+	}
+}
+
+func (s *Store) filmActors(filmId uuid.UUID) []models.FilmActors {
+	var actors []actor
+	for l := range s.links {
+		if l.film == filmId {
+			actors = append(actors, s.actors[l.actor])
+		}
+	}
+	slices.SortFunc(actors, func(a, b actor) int { return cmp.Compare(a.seq, b.seq) })
+
+	var result []models.FilmActors
+	for _, a := range actors {
+		result = append(result, models.FilmActors{ID: a.ID, Name: a.Name, SecondName: a.SecondName, Patronymic: a.Patronymic})
+	}
+
+	return result
+}
+
+func (s *Store) actorFilms(actorId uuid.UUID) []models.ActorFilm {
+	var films []film
+	for l := range s.links {
+		if l.actor == actorId {
+			films = append(films, s.films[l.film])
+		}
+	}
+	slices.SortFunc(films, func(a, b film) int { return cmp.Compare(a.seq, b.seq) })
+
+	var result []models.ActorFilm
+	for _, f := range films {
+		result = append(result, models.ActorFilm{ID: f.ID, Name: f.Name})
+	}
+
+	return result
+}
+
+// normalizeDate parses date like the date column does and formats it back.
+func normalizeDate(date string) (string, error) {
+	d, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return "", fmt.Errorf("invalid input syntax for type date: %q", date)
+	}
+
+	return d.Format(time.DateOnly), nil
+}
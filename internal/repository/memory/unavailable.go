@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"net/http"
+	"vk-test-spring/internal/models"
+)
+
+// UnavailableLists stands in for user lists, which the memory storage does not keep.
+// Every call fails with 501 Not Implemented.
+type UnavailableLists struct{}
+
+// UnavailableSearch stands in for the full-text search, which needs PostgreSQL.
+// Every call fails with 501 Not Implemented.
+type UnavailableSearch struct{}
+
+func unavailable(feature string) error {
+	return models.CustomError{Code: http.StatusNotImplemented, Message: feature + " are not available with the memory storage"}
+}
+
+func (UnavailableLists) Create(ctx context.Context, list models.UserList) (uuid.UUID, error) {
+	return uuid.Nil, unavailable("lists")
+}
+
+func (UnavailableLists) CreateBuiltin(ctx context.Context, userId uuid.UUID, names map[string]string) error {
+	return unavailable("lists")
+}
+
+func (UnavailableLists) Update(ctx context.Context, list models.UserList) error {
+	return unavailable("lists")
+}
+
+func (UnavailableLists) Delete(ctx context.Context, userId uuid.UUID, listId uuid.UUID) error {
+	return unavailable("lists")
+}
+
+func (UnavailableLists) GetUserLists(ctx context.Context, userId uuid.UUID) ([]models.UserList, error) {
+	return nil, unavailable("lists")
+}
+
+func (UnavailableLists) GetListById(ctx context.Context, userId uuid.UUID, listId uuid.UUID) (models.UserList, error) {
+	return models.UserList{}, unavailable("lists")
+}
+
+func (UnavailableLists) GetListByKind(ctx context.Context, userId uuid.UUID, kind string) (models.UserList, error) {
+	return models.UserList{}, unavailable("lists")
+}
+
+func (UnavailableLists) GetListByShareToken(ctx context.Context, token string) (models.UserList, error) {
+	return models.UserList{}, unavailable("lists")
+}
+
+func (UnavailableLists) AddFilm(ctx context.Context, listId uuid.UUID, film models.ListFilm) error {
+	return unavailable("lists")
+}
+
+func (UnavailableLists) UpdateFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID, position int, note *string) error {
+	return unavailable("lists")
+}
+
+func (UnavailableLists) RemoveFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID) error {
+	return unavailable("lists")
+}
+
+func (UnavailableSearch) Search(ctx context.Context, query string, limit int, offset int) ([]models.SearchResult, int, error) {
+	return nil, 0, unavailable("search results")
+}
+
+func (UnavailableSearch) SuggestFilms(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	return nil, unavailable("suggestions")
+}
+
+func (UnavailableSearch) SuggestActors(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	return nil, unavailable("suggestions")
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"vk-test-spring/internal/models"
+)
+
+type UsersRepo struct {
+	store *Store
+}
+
+func NewUsersRepo(store *Store) *UsersRepo {
+	return &UsersRepo{
+		store: store,
+	}
+}
+
+// Create adds a user without a password; such users exist but cannot authenticate.
+func (r *UsersRepo) Create(ctx context.Context, u models.User) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		if u.ID == uuid.Nil {
+			u.ID = uuid.New()
+		}
+
+		r.store.users[u.ID] = user{User: u}
+		return nil
+	})
+}
+
+func (r *UsersRepo) Delete(ctx context.Context, userId string) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := uuid.Parse(userId)
+		if err != nil {
+			return models.CustomError{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid user id: %v", userId)}
+		}
+
+		if _, ok := r.store.users[id]; !ok {
+			return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found user with this id: %v", userId)}
+		}
+
+		delete(r.store.users, id)
+		return nil
+	})
+}
+
+func (r *UsersRepo) Edit(ctx context.Context, u models.User) error {
+	return r.store.WithinTx(ctx, func(ctx context.Context) error {
+		stored, ok := r.store.users[u.ID]
+		if !ok {
+			return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found user with this id: %v", u.ID)}
+		}
+
+		stored.Name = u.Name
+		stored.Role = u.Role
+		r.store.users[u.ID] = stored
+
+		return nil
+	})
+}
+
+// unknownUserHash is compared with the password of an unknown user, so the answer takes as long as
+// for a known user with a wrong password and does not tell which names exist.
+var unknownUserHash, _ = bcrypt.GenerateFromPassword([]byte("unknown user"), bcrypt.DefaultCost)
+
+// GetUserIdRole checks password against the bcrypt hash stored for username.
+func (r *UsersRepo) GetUserIdRole(username string, password string) (string, string, error) {
+	unlock := r.store.rlock(context.Background())
+
+	hash := unknownUserHash
+	var found user
+	for _, u := range r.store.users {
+		if u.passwordHash != "" && u.Name == username {
+			found, hash = u, []byte(u.passwordHash)
+			break
+		}
+	}
+	unlock()
+
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || found.ID == uuid.Nil {
+		return "", "", models.CustomError{Code: http.StatusUnauthorized, Message: "invalid username or password"}
+	}
+
+	return found.ID.String(), found.Role, nil
+}
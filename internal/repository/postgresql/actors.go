@@ -6,23 +6,26 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"net/http"
 	"time"
+	"vk-test-spring/internal/config"
 	"vk-test-spring/internal/models"
+	database "vk-test-spring/pkg/database/postgresql"
 )
 
 type ActorsRepo struct {
-	db *pgxpool.Pool
+	db     *database.Cluster
+	search config.SearchConfig
 }
 
-func MewActorsRepo(db *pgxpool.Pool) *ActorsRepo {
+func MewActorsRepo(db *database.Cluster, search config.SearchConfig) *ActorsRepo {
 	return &ActorsRepo{
-		db: db,
+		db:     db,
+		search: search,
 	}
 }
 
-func (r *ActorsRepo) Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) error {
+func (r *ActorsRepo) Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) (uuid.UUID, error) {
 	var id uuid.UUID
 
 	query := `INSERT INTO actors (f_name, s_name, patronymic, birthday, sex) VALUES (@name, @secondName, @patron, @bd, @s) RETURNING id`
@@ -34,25 +37,24 @@ func (r *ActorsRepo) Create(ctx context.Context, actor models.Actor, actorFilms
 		"s":          actor.Sex,
 	}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginWrite(ctx)
 	if err != nil {
-		return err
+		return uuid.Nil, err
 	}
 
 	err = tx.QueryRow(ctx, query, args).Scan(&id)
 	if err != nil {
 		tx.Rollback(ctx)
-		return err
+		return uuid.Nil, err
 	}
 
 	err = r.insertIntoActorFilms(ctx, tx, id, actorFilms)
 	if err != nil {
 		tx.Rollback(ctx)
-		return err
+		return uuid.Nil, err
 	}
 
-	tx.Commit(ctx)
-	return err
+	return id, tx.Commit(ctx)
 }
 
 func (r *ActorsRepo) Edit(ctx context.Context, actor models.Actor, filmsToAdd []uuid.UUID, filmsToDel []uuid.UUID) error {
@@ -66,7 +68,7 @@ func (r *ActorsRepo) Edit(ctx context.Context, actor models.Actor, filmsToAdd []
 		"actor_id":   actor.ID,
 	}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -89,8 +91,7 @@ func (r *ActorsRepo) Edit(ctx context.Context, actor models.Actor, filmsToAdd []
 		return err
 	}
 
-	tx.Commit(ctx)
-	return err
+	return tx.Commit(ctx)
 }
 
 func (r *ActorsRepo) Delete(ctx context.Context, actorId uuid.UUID) error {
@@ -99,7 +100,7 @@ func (r *ActorsRepo) Delete(ctx context.Context, actorId uuid.UUID) error {
 		"actorId": actorId,
 	}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -115,118 +116,128 @@ func (r *ActorsRepo) Delete(ctx context.Context, actorId uuid.UUID) error {
 		return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found actor with this id: %v", actorId)}
 	}
 
-	tx.Commit(ctx)
-	return nil
+	return tx.Commit(ctx)
+}
+
+// LastModified returns when the actors, their films or the links between them last changed.
+func (r *ActorsRepo) LastModified(ctx context.Context) (time.Time, error) {
+	return libraryChanged(ctx, r.db)
 }
 
 func (r *ActorsRepo) GetAllActors(ctx context.Context) ([]models.Actor, error) {
-	query := `SELECT id, f_name, s_name, patronymic, birthday, sex FROM actors`
+	query := `SELECT id, f_name, s_name, patronymic, birthday, sex, updated_at FROM actors`
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	rows, err := tx.Query(ctx, query)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return nil, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintln("actors not found")}
-		}
-
 		tx.Rollback(ctx)
 		return nil, err
 	}
-	defer rows.Close()
 
 	actors := make([]models.Actor, 0)
 	for rows.Next() {
 		actor := models.Actor{}
 		var t time.Time
 
-		err := rows.Scan(&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex)
+		err := rows.Scan(&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex, &actor.UpdatedAt)
 		if err != nil {
+			rows.Close()
 			tx.Rollback(ctx)
 			return nil, err
 		}
 
 		actor.DateOfBirth = r.dateTypeToString(t)
+		actors = append(actors, actor)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
-		films, err := r.getActorFilms(ctx, tx, actor.ID)
+	for i := range actors {
+		actors[i].Films, err = r.getActorFilms(ctx, tx, actors[i].ID)
 		if err != nil {
 			tx.Rollback(ctx)
 			return nil, err
 		}
-
-		actor.Films = films
-
-		actors = append(actors, actor)
 	}
 
-	tx.Commit(ctx)
-	return actors, nil
+	return actors, tx.Commit(ctx)
 }
 
 func (r *ActorsRepo) GetActorsByName(ctx context.Context, name string) ([]models.Actor, error) {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := tx.Query(ctx, `SELECT actors.id, actors.f_name, actors.s_name, actors.patronymic, actors.birthday, actors.sex
-	FROM actors WHERE concat(actors.f_name, ' ', actors.s_name, ' ', actors.patronymic) LIKE '%$1%'`, name)
+	err = setWordSimilarityThreshold(ctx, tx, r.search.SimilarityThreshold)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT actors.id, actors.f_name, actors.s_name, actors.patronymic, actors.birthday, actors.sex, actors.updated_at,
+	word_similarity(to_latin($1), actors.name_latin)::float8 AS score
+	FROM actors WHERE to_latin($1) <% actors.name_latin
+	ORDER BY score DESC, actors.s_name, actors.f_name`, name)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return nil, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found. actors with name like this: %v", name)}
-		}
 		tx.Rollback(ctx)
 		return nil, err
 	}
-	defer rows.Close()
 
 	actors := make([]models.Actor, 0)
 	for rows.Next() {
 		actor := models.Actor{}
 		var t time.Time
 
-		err := rows.Scan(&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex)
+		err := rows.Scan(&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex, &actor.UpdatedAt, &actor.Score)
 		if err != nil {
+			rows.Close()
 			tx.Rollback(ctx)
 			return nil, err
 		}
 
 		actor.DateOfBirth = r.dateTypeToString(t)
+		actors = append(actors, actor)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
-		films, err := r.getActorFilms(ctx, tx, actor.ID)
+	for i := range actors {
+		actors[i].Films, err = r.getActorFilms(ctx, tx, actors[i].ID)
 		if err != nil {
 			tx.Rollback(ctx)
 			return nil, err
 		}
-
-		actor.Films = films
-
-		actors = append(actors, actor)
 	}
 
-	tx.Commit(ctx)
-	return actors, nil
+	return actors, tx.Commit(ctx)
 }
 
 func (r *ActorsRepo) GetActorById(ctx context.Context, actorId uuid.UUID) (models.Actor, error) {
 	var actor models.Actor
 	var t time.Time
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return models.Actor{}, err
 	}
 
-	err = tx.QueryRow(ctx, `SELECT id, f_name, s_name, patronymic, birthday, sex 
+	err = tx.QueryRow(ctx, `SELECT id, f_name, s_name, patronymic, birthday, sex, updated_at
 	FROM actors WHERE id=$1`, actorId).Scan(
-		&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex)
+		&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex, &actor.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			tx.Rollback(ctx)
 			return models.Actor{}, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found actor with this id: %v", actorId)}
 		}
 		tx.Rollback(ctx)
@@ -237,19 +248,88 @@ func (r *ActorsRepo) GetActorById(ctx context.Context, actorId uuid.UUID) (model
 
 	films, err := r.getActorFilms(ctx, tx, actorId)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return actor, nil
-		}
-
 		tx.Rollback(ctx)
 		return models.Actor{}, err
 	}
 
 	actor.Films = films
 
-	tx.Commit(ctx)
-	return actor, err
+	return actor, tx.Commit(ctx)
+}
+
+// GetActorsByIds returns the actors with the given ids in the order of ids, skipping the ones that
+// do not exist. The films of all the actors are read with one query.
+func (r *ActorsRepo) GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error) {
+	tx, err := r.db.BeginRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id, f_name, s_name, patronymic, birthday, sex, updated_at
+	FROM actors WHERE id = ANY($1)`, ids)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]*models.Actor, len(ids))
+	for rows.Next() {
+		actor := models.Actor{}
+		var t time.Time
+
+		err := rows.Scan(&actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic, &t, &actor.Sex, &actor.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		actor.DateOfBirth = r.dateTypeToString(t)
+		found[actor.ID] = &actor
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	rows, err = tx.Query(ctx, `SELECT af.fk_actor_id, f.id, f.name
+	FROM actors_films AS af JOIN films AS f ON f.id = af.fk_film_id
+	WHERE af.fk_actor_id = ANY($1)`, ids)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var actorId uuid.UUID
+		film := models.ActorFilm{}
+
+		err := rows.Scan(&actorId, &film.ID, &film.Name)
+		if err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		if actor, ok := found[actorId]; ok {
+			actor.Films = append(actor.Films, film)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	actors := make([]models.Actor, 0, len(found))
+	for _, id := range ids {
+		if actor, ok := found[id]; ok {
+			actors = append(actors, *actor)
+			delete(found, id)
+		}
+	}
+
+	return actors, tx.Commit(ctx)
 }
 
 func (r *ActorsRepo) insertIntoActorFilms(ctx context.Context, tx pgx.Tx, actorId uuid.UUID, filmsId []uuid.UUID) error {
@@ -289,16 +369,14 @@ func (r *ActorsRepo) deleteFromActorFilms(ctx context.Context, tx pgx.Tx, actorI
 	return nil
 }
 
+// getActorFilms reads the films of an actor through tx, which must have no rows left open.
 func (r *ActorsRepo) getActorFilms(ctx context.Context, tx pgx.Tx, actorId uuid.UUID) ([]models.ActorFilm, error) {
-	rows, err := r.db.Query(ctx, `SELECT films.id, films.name
+	rows, err := tx.Query(ctx, `SELECT films.id, films.name
 	FROM films
 	JOIN actors_films ON films.id = actors_films.fk_film_id
 	JOIN actors ON actors.id = actors_films.fk_actor_id
 	WHERE actors.id = $1`, actorId)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, err
-		}
 		return nil, err
 	}
 	defer rows.Close()
@@ -315,7 +393,7 @@ func (r *ActorsRepo) getActorFilms(ctx context.Context, tx pgx.Tx, actorId uuid.
 		films = append(films, film)
 	}
 
-	return films, err
+	return films, rows.Err()
 }
 
 func (r *ActorsRepo) dateTypeToString(t time.Time) string {
@@ -0,0 +1,26 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+	database "vk-test-spring/pkg/database/postgresql"
+)
+
+// libraryChanged returns when films, actors or their links were last written, deletes included.
+// A film lists its actors and an actor its films, so lists of either change with any of the three.
+func libraryChanged(ctx context.Context, db *database.Cluster) (time.Time, error) {
+	tx, err := db.BeginRead(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var changed time.Time
+	err = tx.QueryRow(ctx, `SELECT max(changed_at) FROM table_changes
+	WHERE table_name IN ('films', 'actors', 'actors_films')`).Scan(&changed)
+	if err != nil {
+		tx.Rollback(ctx)
+		return time.Time{}, err
+	}
+
+	return changed, tx.Commit(ctx)
+}
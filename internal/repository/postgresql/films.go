@@ -6,19 +6,23 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"net/http"
+	"strings"
 	"time"
+	"vk-test-spring/internal/config"
 	"vk-test-spring/internal/models"
+	database "vk-test-spring/pkg/database/postgresql"
 )
 
 type FilmsRepo struct {
-	db *pgxpool.Pool
+	db     *database.Cluster
+	search config.SearchConfig
 }
 
-func NewFilmsRepo(db *pgxpool.Pool) *FilmsRepo {
+func NewFilmsRepo(db *database.Cluster, search config.SearchConfig) *FilmsRepo {
 	return &FilmsRepo{
-		db: db,
+		db:     db,
+		search: search,
 	}
 }
 
@@ -33,7 +37,7 @@ func (r *FilmsRepo) Create(ctx context.Context, film models.Film, actors []uuid.
 		"rate": film.Rating,
 	}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -50,8 +54,7 @@ func (r *FilmsRepo) Create(ctx context.Context, film models.Film, actors []uuid.
 		return err
 	}
 
-	tx.Commit(ctx)
-	return err
+	return tx.Commit(ctx)
 }
 
 func (r *FilmsRepo) Update(ctx context.Context, film models.Film, actorsToAdd []uuid.UUID, actorsToDel []uuid.UUID) error {
@@ -64,7 +67,7 @@ func (r *FilmsRepo) Update(ctx context.Context, film models.Film, actorsToAdd []
 		"film_id": film.ID,
 	}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -87,8 +90,7 @@ func (r *FilmsRepo) Update(ctx context.Context, film models.Film, actorsToAdd []
 		return err
 	}
 
-	tx.Commit(ctx)
-	return err
+	return tx.Commit(ctx)
 }
 
 func (r *FilmsRepo) Delete(ctx context.Context, filmId uuid.UUID) error {
@@ -97,7 +99,7 @@ func (r *FilmsRepo) Delete(ctx context.Context, filmId uuid.UUID) error {
 		"filmId": filmId,
 	}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginWrite(ctx)
 	if err != nil {
 		return err
 	}
@@ -113,163 +115,193 @@ func (r *FilmsRepo) Delete(ctx context.Context, filmId uuid.UUID) error {
 		return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film with this id: %v", filmId)}
 	}
 
-	tx.Commit(ctx)
-	return err
+	return tx.Commit(ctx)
 }
 
+// LastModified returns when the films, their actors or the links between them last changed.
+func (r *FilmsRepo) LastModified(ctx context.Context) (time.Time, error) {
+	return libraryChanged(ctx, r.db)
+}
+
+// filmSortColumns are the columns films can be ordered by, under the "sort" values of ctx.
+var filmSortColumns = map[string]string{"name": "name", "date": "date", "rating": "rating"}
+
+// GetAllFilms orders films by the "sort" (name, date or rating) and "order" (ASC or DESC) values
+// of ctx, as the handler sets them. Other values are never written into the query.
 func (r *FilmsRepo) GetAllFilms(ctx context.Context) ([]models.Film, error) {
-	query := fmt.Sprintf("SELECT id, name, description, date, rating FROM films ORDER BY %s %s", ctx.Value("sort"), ctx.Value("order"))
+	query := "SELECT id, name, description, date, rating, updated_at FROM films"
+	sortField, _ := ctx.Value("sort").(string)
+	if column, ok := filmSortColumns[sortField]; ok {
+		order, _ := ctx.Value("order").(string)
+		direction := "ASC"
+		if strings.EqualFold(order, "desc") {
+			direction = "DESC"
+		}
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+		query += " ORDER BY " + column + " " + direction
+	}
+
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	rows, err := tx.Query(ctx, query)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return nil, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintln("films not found")}
-		}
-
 		tx.Rollback(ctx)
 		return nil, err
 	}
-	defer rows.Close()
 
 	films := make([]models.Film, 0)
 	for rows.Next() {
 		film := models.Film{}
 		var t time.Time
 
-		err := rows.Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating)
+		err := rows.Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating, &film.UpdatedAt)
 		if err != nil {
+			rows.Close()
 			tx.Rollback(ctx)
 			return nil, err
 		}
 
 		film.Date = r.dateTypeToString(t)
+		films = append(films, film)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
-		actors, err := r.getFilmActors(ctx, tx, film.ID)
+	for i := range films {
+		films[i].Actors, err = r.getFilmActors(ctx, tx, films[i].ID)
 		if err != nil {
 			tx.Rollback(ctx)
 			return nil, err
 		}
-
-		film.Actors = actors
-
-		films = append(films, film)
 	}
 
-	tx.Commit(ctx)
-	return films, err
+	return films, tx.Commit(ctx)
 }
 
 func (r *FilmsRepo) GetFilmByName(ctx context.Context, name string) ([]models.Film, error) {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := tx.Query(ctx, fmt.Sprintf(`SELECT id, name, description, date, rating FROM films WHERE films.name LIKE '%%%v%%'`, name))
+	err = setWordSimilarityThreshold(ctx, tx, r.search.SimilarityThreshold)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return nil, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film with name like this: %v", name)}
-		}
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
+	rows, err := tx.Query(ctx, `SELECT id, name, description, date, rating, updated_at, word_similarity(to_latin($1), name_latin)::float8 AS score
+	FROM films WHERE to_latin($1) <% name_latin
+	ORDER BY score DESC, name`, name)
+	if err != nil {
 		tx.Rollback(ctx)
 		return nil, err
 	}
-	defer rows.Close()
 
 	films := make([]models.Film, 0)
 	for rows.Next() {
 		film := models.Film{}
 		var t time.Time
 
-		err := rows.Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating)
+		err := rows.Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating, &film.UpdatedAt, &film.Score)
 		if err != nil {
+			rows.Close()
 			tx.Rollback(ctx)
 			return nil, err
 		}
 
 		film.Date = r.dateTypeToString(t)
+		films = append(films, film)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
-		actors, err := r.getFilmActors(ctx, tx, film.ID)
+	for i := range films {
+		films[i].Actors, err = r.getFilmActors(ctx, tx, films[i].ID)
 		if err != nil {
 			tx.Rollback(ctx)
 			return nil, err
 		}
-
-		film.Actors = actors
-
-		films = append(films, film)
 	}
 
-	tx.Commit(ctx)
-	return films, nil
+	return films, tx.Commit(ctx)
 }
 
 func (r *FilmsRepo) GetFilmByActor(ctx context.Context, actorName string) ([]models.Film, error) {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := tx.Query(ctx, fmt.Sprintf("SELECT films.id, films.description, films.date, films.rating"+
-		" FROM films JOIN actors_films as af ON films.id = af.fk_film_id JOIN actors as a ON af.fk_actor_id = a.id"+
-		" WHERE CONCAT(a.f_name, ' ', a.s_name, ' ', a.patronymic) LIKE '%%%v%%'", actorName))
+	err = setWordSimilarityThreshold(ctx, tx, r.search.SimilarityThreshold)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return nil, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film with actor name like this: %v", actorName)}
-		}
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
+	rows, err := tx.Query(ctx, `SELECT films.id, films.name, films.description, films.date, films.rating, films.updated_at,
+	max(word_similarity(to_latin($1), a.name_latin))::float8 AS score
+	FROM films JOIN actors_films as af ON films.id = af.fk_film_id JOIN actors as a ON af.fk_actor_id = a.id
+	WHERE to_latin($1) <% a.name_latin
+	GROUP BY films.id
+	ORDER BY score DESC, films.name`, actorName)
+	if err != nil {
 		tx.Rollback(ctx)
 		return nil, err
 	}
-	defer rows.Close()
 
 	films := make([]models.Film, 0)
 	for rows.Next() {
 		film := models.Film{}
 		var t time.Time
 
-		err := rows.Scan(&film.ID, &film.Description, &t, &film.Rating)
+		err := rows.Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating, &film.UpdatedAt, &film.Score)
 		if err != nil {
+			rows.Close()
 			tx.Rollback(ctx)
 			return nil, err
 		}
 
 		film.Date = r.dateTypeToString(t)
+		films = append(films, film)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
 
-		actors, err := r.getFilmActors(ctx, tx, film.ID)
+	for i := range films {
+		films[i].Actors, err = r.getFilmActors(ctx, tx, films[i].ID)
 		if err != nil {
 			tx.Rollback(ctx)
 			return nil, err
 		}
-
-		film.Actors = actors
-
-		films = append(films, film)
 	}
 
-	tx.Commit(ctx)
-	return films, err
+	return films, tx.Commit(ctx)
 }
 
 func (r *FilmsRepo) GetFilmById(ctx context.Context, filmId uuid.UUID) (models.Film, error) {
 	var film models.Film
 	var t time.Time
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := r.db.BeginRead(ctx)
 	if err != nil {
 		return models.Film{}, err
 	}
 
-	err = tx.QueryRow(ctx, `SELECT id, name, description, date, rating
-	FROM films WHERE id=$1`, filmId).Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating)
+	err = tx.QueryRow(ctx, `SELECT id, name, description, date, rating, updated_at
+	FROM films WHERE id=$1`, filmId).Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating, &film.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			tx.Rollback(ctx)
@@ -283,19 +315,88 @@ func (r *FilmsRepo) GetFilmById(ctx context.Context, filmId uuid.UUID) (models.F
 
 	actors, err := r.getFilmActors(ctx, tx, filmId)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			tx.Commit(ctx)
-			return film, nil
-		}
-
 		tx.Rollback(ctx)
 		return models.Film{}, err
 	}
 
 	film.Actors = actors
 
-	tx.Commit(ctx)
-	return film, err
+	return film, tx.Commit(ctx)
+}
+
+// GetFilmsByIds returns the films with the given ids in the order of ids, skipping the ones that do
+// not exist. The actors of all the films are read with one query.
+func (r *FilmsRepo) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error) {
+	tx, err := r.db.BeginRead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id, name, description, date, rating, updated_at
+	FROM films WHERE id = ANY($1)`, ids)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]*models.Film, len(ids))
+	for rows.Next() {
+		film := models.Film{}
+		var t time.Time
+
+		err := rows.Scan(&film.ID, &film.Name, &film.Description, &t, &film.Rating, &film.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		film.Date = r.dateTypeToString(t)
+		found[film.ID] = &film
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	rows, err = tx.Query(ctx, `SELECT af.fk_film_id, a.id, a.f_name, a.s_name, a.patronymic
+	FROM actors_films AS af JOIN actors AS a ON a.id = af.fk_actor_id
+	WHERE af.fk_film_id = ANY($1)`, ids)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var filmId uuid.UUID
+		actor := models.FilmActors{}
+
+		err := rows.Scan(&filmId, &actor.ID, &actor.Name, &actor.SecondName, &actor.Patronymic)
+		if err != nil {
+			tx.Rollback(ctx)
+			return nil, err
+		}
+
+		if film, ok := found[filmId]; ok {
+			film.Actors = append(film.Actors, actor)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+
+	films := make([]models.Film, 0, len(found))
+	for _, id := range ids {
+		if film, ok := found[id]; ok {
+			films = append(films, *film)
+			delete(found, id)
+		}
+	}
+
+	return films, tx.Commit(ctx)
 }
 
 func (r *FilmsRepo) insertIntoActorFilm(ctx context.Context, tx pgx.Tx, actorsId []uuid.UUID, filmId uuid.UUID) error {
@@ -335,17 +436,14 @@ func (r *FilmsRepo) deleteFromActorFilm(ctx context.Context, tx pgx.Tx, actorsId
 	return nil
 }
 
+// getFilmActors reads the actors of a film through tx, which must have no rows left open.
 func (r *FilmsRepo) getFilmActors(ctx context.Context, tx pgx.Tx, filmId uuid.UUID) ([]models.FilmActors, error) {
-	rows, err := r.db.Query(ctx, `SELECT actors.id, actors.f_name, actors.s_name, actors.patronymic
+	rows, err := tx.Query(ctx, `SELECT actors.id, actors.f_name, actors.s_name, actors.patronymic
 	FROM actors
 	JOIN actors_films ON actors.id = actors_films.fk_actor_id
 	JOIN films ON films.id = actors_films.fk_film_id
 	WHERE films.id = $1`, filmId)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, err
-		}
-
 		return nil, err
 	}
 	defer rows.Close()
@@ -362,7 +460,7 @@ func (r *FilmsRepo) getFilmActors(ctx context.Context, tx pgx.Tx, filmId uuid.UU
 		actors = append(actors, actor)
 	}
 
-	return actors, err
+	return actors, rows.Err()
 }
 
 func (r *FilmsRepo) dateTypeToString(t time.Time) string {
@@ -0,0 +1,401 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"net/http"
+	"time"
+	"vk-test-spring/internal/models"
+	database "vk-test-spring/pkg/database/postgresql"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type ListsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewListsRepo(db *pgxpool.Pool) *ListsRepo {
+	return &ListsRepo{
+		db: db,
+	}
+}
+
+func (r *ListsRepo) Create(ctx context.Context, list models.UserList) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	query := `INSERT INTO users_lists (fk_user_id, name, kind) VALUES (@user, @name, @kind) RETURNING id`
+	args := pgx.NamedArgs{
+		"user": list.UserID,
+		"name": list.Name,
+		"kind": list.Kind,
+	}
+
+	err := r.db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+func (r *ListsRepo) CreateBuiltin(ctx context.Context, userId uuid.UUID, names map[string]string) error {
+	query := `INSERT INTO users_lists (fk_user_id, name, kind) VALUES (@user, @name, @kind)
+	ON CONFLICT (fk_user_id, kind) WHERE kind <> 'custom' DO NOTHING`
+
+	tx, err := database.BeginTx(ctx, r.db, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	for kind, name := range names {
+		args := pgx.NamedArgs{
+			"user": userId,
+			"name": name,
+			"kind": kind,
+		}
+
+		_, err = tx.Exec(ctx, query, args)
+		if err != nil {
+			tx.Rollback(ctx)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ListsRepo) Update(ctx context.Context, list models.UserList) error {
+	query := `UPDATE users_lists SET name = @name, share_token = @token WHERE id = @id AND fk_user_id = @user`
+	args := pgx.NamedArgs{
+		"name":  list.Name,
+		"token": r.nullableToken(list.ShareToken),
+		"id":    list.ID,
+		"user":  list.UserID,
+	}
+
+	res, err := r.db.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found list with this id: %v", list.ID)}
+	}
+
+	return nil
+}
+
+func (r *ListsRepo) Delete(ctx context.Context, userId uuid.UUID, listId uuid.UUID) error {
+	query := `DELETE FROM users_lists WHERE id = @id AND fk_user_id = @user`
+	args := pgx.NamedArgs{
+		"id":   listId,
+		"user": userId,
+	}
+
+	res, err := r.db.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found list with this id: %v", listId)}
+	}
+
+	return nil
+}
+
+func (r *ListsRepo) GetUserLists(ctx context.Context, userId uuid.UUID) ([]models.UserList, error) {
+	rows, err := r.db.Query(ctx, `SELECT l.id, l.fk_user_id, l.name, l.kind, COALESCE(l.share_token, ''), l.created_at,
+	(SELECT count(*) FROM users_lists_films AS lf WHERE lf.fk_list_id = l.id)
+	FROM users_lists AS l WHERE l.fk_user_id = $1
+	ORDER BY l.kind = 'custom', l.kind, l.created_at`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := make([]models.UserList, 0)
+	for rows.Next() {
+		list, err := r.scanList(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		lists = append(lists, list)
+	}
+
+	return lists, rows.Err()
+}
+
+func (r *ListsRepo) GetListById(ctx context.Context, userId uuid.UUID, listId uuid.UUID) (models.UserList, error) {
+	return r.getList(ctx, `l.id = $1 AND l.fk_user_id = $2`, fmt.Sprintf("not found list with this id: %v", listId), listId, userId)
+}
+
+func (r *ListsRepo) GetListByKind(ctx context.Context, userId uuid.UUID, kind string) (models.UserList, error) {
+	return r.getList(ctx, `l.kind = $1 AND l.fk_user_id = $2`, fmt.Sprintf("not found list of this kind: %v", kind), kind, userId)
+}
+
+func (r *ListsRepo) GetListByShareToken(ctx context.Context, token string) (models.UserList, error) {
+	return r.getList(ctx, `l.share_token = $1`, "not found shared list", token)
+}
+
+func (r *ListsRepo) AddFilm(ctx context.Context, listId uuid.UUID, film models.ListFilm) error {
+	tx, err := database.BeginTx(ctx, r.db, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	last, err := r.lockList(ctx, tx, listId)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	position := film.Position
+	if position < 1 || position > last+1 {
+		position = last + 1
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users_lists_films SET position = position + 1
+	WHERE fk_list_id = $1 AND position >= $2`, listId, position)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	query := `INSERT INTO users_lists_films (fk_list_id, fk_film_id, position, note) VALUES (@list, @film, @pos, @note)`
+	args := pgx.NamedArgs{
+		"list": listId,
+		"film": film.ID,
+		"pos":  position,
+		"note": film.Note,
+	}
+
+	_, err = tx.Exec(ctx, query, args)
+	if err != nil {
+		tx.Rollback(ctx)
+		return r.mapError(err, listId, film.ID)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UpdateFilm moves the film to position, or keeps it in place when position is below 1, and sets its
+// note unless note is nil.
+func (r *ListsRepo) UpdateFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID, position int, note *string) error {
+	tx, err := database.BeginTx(ctx, r.db, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	last, err := r.lockList(ctx, tx, listId)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	var current int
+	err = tx.QueryRow(ctx, `SELECT position FROM users_lists_films WHERE fk_list_id = $1 AND fk_film_id = $2`,
+		listId, filmId).Scan(&current)
+	if err != nil {
+		tx.Rollback(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film in list with this id: %v", filmId)}
+		}
+		return err
+	}
+
+	if position < 1 {
+		position = current
+	}
+	if position > last {
+		position = last
+	}
+
+	switch {
+	case position < current:
+		_, err = tx.Exec(ctx, `UPDATE users_lists_films SET position = position + 1
+		WHERE fk_list_id = $1 AND position >= $2 AND position < $3`, listId, position, current)
+	case position > current:
+		_, err = tx.Exec(ctx, `UPDATE users_lists_films SET position = position - 1
+		WHERE fk_list_id = $1 AND position > $2 AND position <= $3`, listId, current, position)
+	}
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	query := `UPDATE users_lists_films SET position = @pos, note = COALESCE(@note, note)
+	WHERE fk_list_id = @list AND fk_film_id = @film`
+	args := pgx.NamedArgs{
+		"pos":  position,
+		"note": note,
+		"list": listId,
+		"film": filmId,
+	}
+
+	_, err = tx.Exec(ctx, query, args)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ListsRepo) RemoveFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID) error {
+	tx, err := database.BeginTx(ctx, r.db, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	if _, err = r.lockList(ctx, tx, listId); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	var position int
+	err = tx.QueryRow(ctx, `DELETE FROM users_lists_films WHERE fk_list_id = $1 AND fk_film_id = $2 RETURNING position`,
+		listId, filmId).Scan(&position)
+	if err != nil {
+		tx.Rollback(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film in list with this id: %v", filmId)}
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users_lists_films SET position = position - 1
+	WHERE fk_list_id = $1 AND position > $2`, listId, position)
+	if err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *ListsRepo) getList(ctx context.Context, where string, notFound string, args ...any) (models.UserList, error) {
+	tx, err := database.BeginTx(ctx, r.db, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return models.UserList{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	row := tx.QueryRow(ctx, `SELECT l.id, l.fk_user_id, l.name, l.kind, COALESCE(l.share_token, ''), l.created_at,
+	(SELECT count(*) FROM users_lists_films AS lf WHERE lf.fk_list_id = l.id)
+	FROM users_lists AS l WHERE `+where, args...)
+
+	list, err := r.scanList(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.UserList{}, models.CustomError{Code: http.StatusNotFound, Message: notFound}
+		}
+		return models.UserList{}, err
+	}
+
+	films, err := r.getListFilms(ctx, tx, list.ID)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	list.Films = films
+
+	return list, tx.Commit(ctx)
+}
+
+func (r *ListsRepo) getListFilms(ctx context.Context, tx pgx.Tx, listId uuid.UUID) ([]models.ListFilm, error) {
+	rows, err := tx.Query(ctx, `SELECT films.id, films.name, films.date, films.rating, lf.position, lf.note, lf.added_at
+	FROM users_lists_films AS lf
+	JOIN films ON films.id = lf.fk_film_id
+	WHERE lf.fk_list_id = $1
+	ORDER BY lf.position`, listId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	films := make([]models.ListFilm, 0)
+	for rows.Next() {
+		film := models.ListFilm{}
+		var date, added time.Time
+
+		err := rows.Scan(&film.ID, &film.Name, &date, &film.Rating, &film.Position, &film.Note, &added)
+		if err != nil {
+			return nil, err
+		}
+
+		film.Date = date.Format(time.DateOnly)
+		film.AddedAt = added.Format(time.RFC3339)
+
+		films = append(films, film)
+	}
+
+	return films, rows.Err()
+}
+
+// lockList takes a row lock on the list so concurrent reorderings of the same list are serialized,
+// and returns the position of its last film.
+func (r *ListsRepo) lockList(ctx context.Context, tx pgx.Tx, listId uuid.UUID) (int, error) {
+	var id uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM users_lists WHERE id = $1 FOR UPDATE`, listId).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found list with this id: %v", listId)}
+		}
+		return 0, err
+	}
+
+	var last int
+	err = tx.QueryRow(ctx, `SELECT COALESCE(max(position), 0) FROM users_lists_films WHERE fk_list_id = $1`, listId).Scan(&last)
+
+	return last, err
+}
+
+func (r *ListsRepo) scanList(row pgx.Row) (models.UserList, error) {
+	list := models.UserList{}
+	var created time.Time
+
+	err := row.Scan(&list.ID, &list.UserID, &list.Name, &list.Kind, &list.ShareToken, &created, &list.FilmsCount)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	list.Public = list.ShareToken != ""
+	list.CreatedAt = created.Format(time.RFC3339)
+
+	return list, nil
+}
+
+func (r *ListsRepo) nullableToken(token string) *string {
+	if token == "" {
+		return nil
+	}
+
+	return &token
+}
+
+func (r *ListsRepo) mapError(err error, listId uuid.UUID, filmId uuid.UUID) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case foreignKeyViolation:
+		return models.CustomError{Code: http.StatusNotFound, Message: fmt.Sprintf("not found film with this id: %v", filmId)}
+	case uniqueViolation:
+		return models.CustomError{Code: http.StatusConflict, Message: fmt.Sprintf("film %v is already in list %v", filmId, listId)}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"strings"
+	"vk-test-spring/internal/models"
+	database "vk-test-spring/pkg/database/postgresql"
+)
+
+// searchQuery matches the input against both text search configurations, so russian and english word forms are found.
+const searchQuery = `WITH q AS (SELECT websearch_to_tsquery('russian', @q) || websearch_to_tsquery('english', @q) AS query)`
+
+const headlineOptions = `'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type SearchRepo struct {
+	db *database.Cluster
+}
+
+func NewSearchRepo(db *database.Cluster) *SearchRepo {
+	return &SearchRepo{
+		db: db,
+	}
+}
+
+func (r *SearchRepo) Search(ctx context.Context, query string, limit int, offset int) ([]models.SearchResult, int, error) {
+	args := pgx.NamedArgs{
+		"q":      query,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	tx, err := r.db.BeginRead(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var total int
+	err = tx.QueryRow(ctx, searchQuery+`
+	SELECT (SELECT count(*) FROM films, q WHERE films.search_vector @@ q.query) +
+		(SELECT count(*) FROM actors, q WHERE actors.search_vector @@ q.query)`, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tx.Query(ctx, searchQuery+`
+	SELECT type, id, title, headline, rank FROM (
+		SELECT 'film' AS type, films.id, films.name AS title,
+			ts_headline('russian', films.name || '. ' || films.description, q.query, `+headlineOptions+`) AS headline,
+			ts_rank(films.search_vector, q.query)::float8 AS rank
+		FROM films, q WHERE films.search_vector @@ q.query
+		UNION ALL
+		SELECT 'actor', actors.id, concat_ws(' ', actors.f_name, actors.s_name, actors.patronymic),
+			ts_headline('russian', concat_ws(' ', actors.f_name, actors.s_name, actors.patronymic), q.query, `+headlineOptions+`),
+			ts_rank(actors.search_vector, q.query)::float8
+		FROM actors, q WHERE actors.search_vector @@ q.query
+	) AS results
+	ORDER BY rank DESC, title, id
+	LIMIT @limit OFFSET @offset`, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := make([]models.SearchResult, 0)
+	for rows.Next() {
+		result := models.SearchResult{}
+
+		err := rows.Scan(&result.Type, &result.ID, &result.Title, &result.Headline, &result.Rank)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return results, total, tx.Commit(ctx)
+}
+
+func (r *SearchRepo) SuggestFilms(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, `SELECT id, name, extract(year FROM date)::int
+	FROM films WHERE name_latin LIKE to_latin($1) || '%'
+	ORDER BY popularity DESC, rating DESC, name
+	LIMIT $2`, r.escapeLike(prefix), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.scanSuggestions(rows)
+}
+
+func (r *SearchRepo) SuggestActors(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	rows, err := r.db.Reader(ctx).Query(ctx, `SELECT actors.id, concat_ws(' ', actors.f_name, actors.s_name), extract(year FROM actors.birthday)::int
+	FROM actors
+	LEFT JOIN actors_films AS af ON af.fk_actor_id = actors.id
+	LEFT JOIN films ON films.id = af.fk_film_id
+	WHERE actors.name_latin LIKE to_latin($1) || '%' OR actors.name_latin LIKE '% ' || to_latin($1) || '%'
+	GROUP BY actors.id
+	ORDER BY COALESCE(sum(films.popularity), 0) DESC, COALESCE(avg(films.rating), 0) DESC, actors.s_name
+	LIMIT $2`, r.escapeLike(prefix), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.scanSuggestions(rows)
+}
+
+func (r *SearchRepo) scanSuggestions(rows pgx.Rows) ([]models.Suggestion, error) {
+	defer rows.Close()
+
+	suggestions := make([]models.Suggestion, 0)
+	for rows.Next() {
+		suggestion := models.Suggestion{}
+
+		err := rows.Scan(&suggestion.ID, &suggestion.Name, &suggestion.Year)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+func (r *SearchRepo) escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,14 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+	"strconv"
+)
+
+// setWordSimilarityThreshold sets the threshold used by the pg_trgm <% operator for the rest of the transaction.
+func setWordSimilarityThreshold(ctx context.Context, tx pgx.Tx, threshold float64) error {
+	_, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`,
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+	return err
+}
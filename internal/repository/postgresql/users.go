@@ -2,7 +2,11 @@ package postgresql
 
 import (
 	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
 	"vk-test-spring/internal/models"
 )
 
@@ -28,13 +32,28 @@ func (r *UsersRepo) Edit(ctx context.Context, user models.User) error {
 	return nil
 }
 
+// unknownUserHash is compared with the password of an unknown user, so the answer takes as long as
+// for a known user with a wrong password and does not tell which names exist.
+var unknownUserHash, _ = bcrypt.GenerateFromPassword([]byte("unknown user"), bcrypt.DefaultCost)
+
+// GetUserIdRole checks password against the bcrypt hash stored for username.
 func (r *UsersRepo) GetUserIdRole(username string, password string) (string, string, error) {
 	userId := ""
 	role := ""
+	hash := ""
+
+	err := r.db.QueryRow(context.Background(), "SELECT id, role, password_hash FROM users WHERE name=$1", username).Scan(&userId, &role, &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(unknownUserHash, []byte(password))
+		return "", "", models.CustomError{Code: http.StatusUnauthorized, Message: "invalid username or password"}
+	}
+	if err != nil {
+		return "", "", err
+	}
 
-	row := r.db.QueryRow(context.Background(), "SELECT id, role FROM users WHERE name=$1 AND password=$2", username, password).Scan(&userId, &role)
-	if row != nil {
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return "", "", models.CustomError{Code: http.StatusUnauthorized, Message: "invalid username or password"}
 	}
 
-	return userId, role, row
+	return userId, role, nil
 }
@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+	"vk-test-spring/internal/config"
 	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository/memory"
 	"vk-test-spring/internal/repository/postgresql"
+	database "vk-test-spring/pkg/database/postgresql"
 )
 
 type Films interface {
@@ -16,15 +19,19 @@ type Films interface {
 	GetFilmByActor(ctx context.Context, actorName string) ([]models.Film, error)
 	GetAllFilms(ctx context.Context) ([]models.Film, error)
 	GetFilmById(ctx context.Context, filmId uuid.UUID) (models.Film, error)
+	GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error)
+	LastModified(ctx context.Context) (time.Time, error)
 }
 
 type Actors interface {
-	Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) error
+	Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) (uuid.UUID, error)
 	Edit(ctx context.Context, actor models.Actor, filmsToAdd []uuid.UUID, filmsToDel []uuid.UUID) error
 	Delete(ctx context.Context, actorId uuid.UUID) error
 	GetAllActors(ctx context.Context) ([]models.Actor, error)
 	GetActorsByName(ctx context.Context, name string) ([]models.Actor, error)
 	GetActorById(ctx context.Context, actorId uuid.UUID) (models.Actor, error)
+	GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error)
+	LastModified(ctx context.Context) (time.Time, error)
 }
 
 type Users interface {
@@ -34,16 +41,68 @@ type Users interface {
 	GetUserIdRole(username string, password string) (string, string, error)
 }
 
+type Lists interface {
+	Create(ctx context.Context, list models.UserList) (uuid.UUID, error)
+	CreateBuiltin(ctx context.Context, userId uuid.UUID, names map[string]string) error
+	Update(ctx context.Context, list models.UserList) error
+	Delete(ctx context.Context, userId uuid.UUID, listId uuid.UUID) error
+	GetUserLists(ctx context.Context, userId uuid.UUID) ([]models.UserList, error)
+	GetListById(ctx context.Context, userId uuid.UUID, listId uuid.UUID) (models.UserList, error)
+	GetListByKind(ctx context.Context, userId uuid.UUID, kind string) (models.UserList, error)
+	GetListByShareToken(ctx context.Context, token string) (models.UserList, error)
+	AddFilm(ctx context.Context, listId uuid.UUID, film models.ListFilm) error
+	UpdateFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID, position int, note *string) error
+	RemoveFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID) error
+}
+
+type Search interface {
+	Search(ctx context.Context, query string, limit int, offset int) ([]models.SearchResult, int, error)
+	SuggestFilms(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error)
+	SuggestActors(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error)
+}
+
+// Transactor runs fn in one transaction carried by the context passed to fn. Repository calls made
+// with that context join the transaction; nested calls run in a savepoint.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type Repositories struct {
 	Films  Films
 	Actors Actors
 	Users  Users
+	Lists  Lists
+	Search Search
+	Tx     Transactor
 }
 
-func NewRepositories(db *pgxpool.Pool) *Repositories {
+// NewRepositories builds the repositories over db. Films, actors and search read from the replica when
+// one is configured; lists and users always use the primary, since users read them right after changing them.
+func NewRepositories(db *database.Cluster, search config.SearchConfig, tx config.TransactionConfig) *Repositories {
+	return &Repositories{
+		Tx:     database.NewTxManager(db, tx.Isolation, tx.MaxRetries, tx.RetryBackoff),
+		Films:  NewTracedFilms(postgresql.NewFilmsRepo(db, search)),
+		Actors: NewTracedActors(postgresql.MewActorsRepo(db, search)),
+		Users:  postgresql.NewUsersRepo(db.Primary()),
+		Lists:  postgresql.NewListsRepo(db.Primary()),
+		Search: NewTracedSearch(postgresql.NewSearchRepo(db)),
+	}
+}
+
+// NewMemoryRepositories builds the repositories over a fresh in-memory store holding users.
+// User lists and search are not available there and fail with 501 Not Implemented.
+func NewMemoryRepositories(users []config.StorageUserConfig, search config.SearchConfig) *Repositories {
+	store := memory.NewStore()
+	for _, u := range users {
+		store.AddUser(u.Name, u.PasswordHash, u.Role)
+	}
+
 	return &Repositories{
-		Films:  postgresql.NewFilmsRepo(db),
-		Actors: postgresql.MewActorsRepo(db),
-		Users:  postgresql.NewUsersRepo(db),
+		Tx:     store,
+		Films:  NewTracedFilms(memory.NewFilmsRepo(store, search)),
+		Actors: NewTracedActors(memory.NewActorsRepo(store, search)),
+		Users:  memory.NewUsersRepo(store),
+		Lists:  memory.UnavailableLists{},
+		Search: memory.UnavailableSearch{},
 	}
 }
@@ -0,0 +1,542 @@
+// Package repositorytest holds the contract every implementation of the films, actors and users
+// repositories has to meet. Implementations run it from their own tests through Run.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository"
+)
+
+// SimilarityThreshold is the search threshold implementations must be configured with for Run.
+const SimilarityThreshold = 0.3
+
+// PasswordHash hashes password as users are stored, at the lowest bcrypt cost to keep tests fast.
+func PasswordHash(t testing.TB, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	return string(hash)
+}
+
+// Fixture is a set of repositories under test over empty films and actors.
+type Fixture struct {
+	Films  repository.Films
+	Actors repository.Actors
+	Users  repository.Users
+	// AddUser stores a user able to authenticate with name and password and returns its id.
+	AddUser func(t *testing.T, name string, password string, role string) string
+}
+
+// Run runs the contract. newFixture is called for every case and must return repositories
+// that share one storage and hold no films and actors.
+func Run(t *testing.T, newFixture func(t *testing.T) Fixture) {
+	t.Run("films", func(t *testing.T) { runFilms(t, newFixture) })
+	t.Run("actors", func(t *testing.T) { runActors(t, newFixture) })
+	t.Run("users", func(t *testing.T) { runUsers(t, newFixture) })
+}
+
+var ctx = context.Background()
+
+func sorted(field string, order string) context.Context {
+	c := context.WithValue(ctx, "sort", field)
+	return context.WithValue(c, "order", order)
+}
+
+func film(name string, date string, rating float64) models.Film {
+	return models.Film{Name: name, Description: name + " description", Date: date, Rating: rating}
+}
+
+func actor(name string, secondName string) models.Actor {
+	return models.Actor{Name: name, SecondName: secondName, Patronymic: "", Sex: "Мужчина", DateOfBirth: "1971-12-27"}
+}
+
+func createFilm(t *testing.T, f Fixture, in models.Film, actors ...uuid.UUID) models.Film {
+	t.Helper()
+
+	require.NoError(t, f.Films.Create(ctx, in, actors))
+
+	all, err := f.Films.GetAllFilms(sorted("name", "ASC"))
+	require.NoError(t, err)
+
+	for _, got := range all {
+		if got.Name == in.Name {
+			return got
+		}
+	}
+
+	t.Fatalf("created film %q is not listed", in.Name)
+	return models.Film{}
+}
+
+func createActor(t *testing.T, f Fixture, in models.Actor, films ...uuid.UUID) uuid.UUID {
+	t.Helper()
+
+	id, err := f.Actors.Create(ctx, in, films)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, id)
+
+	return id
+}
+
+func assertCode(t *testing.T, code int, err error) {
+	t.Helper()
+
+	var customErr models.CustomError
+	if assert.True(t, errors.As(err, &customErr), "expected models.CustomError, got %v", err) {
+		assert.Equal(t, code, customErr.Code)
+	}
+}
+
+func filmNames(films []models.Film) []string {
+	names := make([]string, 0, len(films))
+	for _, f := range films {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+func castIds(f models.Film) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(f.Actors))
+	for _, a := range f.Actors {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+func runFilms(t *testing.T, newFixture func(t *testing.T) Fixture) {
+	t.Run("create and get", func(t *testing.T) {
+		f := newFixture(t)
+		a := createActor(t, f, actor("Сергей", "Бодров"))
+
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5), a)
+
+		got, err := f.Films.GetFilmById(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Брат", got.Name)
+		assert.Equal(t, "Брат description", got.Description)
+		assert.Equal(t, "1997-12-12", got.Date)
+		assert.Equal(t, 8.5, got.Rating)
+		assert.False(t, got.UpdatedAt.IsZero())
+		assert.Equal(t, []models.FilmActors{{ID: a, Name: "Сергей", SecondName: "Бодров", Patronymic: ""}}, got.Actors)
+	})
+
+	t.Run("film without actors", func(t *testing.T) {
+		f := newFixture(t)
+
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+		assert.Empty(t, created.Actors)
+	})
+
+	t.Run("update fields and links", func(t *testing.T) {
+		f := newFixture(t)
+		first := createActor(t, f, actor("Сергей", "Бодров"))
+		second := createActor(t, f, actor("Виктор", "Сухоруков"))
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5), first)
+
+		changed := film("Брат 2", "2000-05-11", 8.1)
+		changed.ID = created.ID
+		require.NoError(t, f.Films.Update(ctx, changed, []uuid.UUID{second}, []uuid.UUID{first}))
+
+		got, err := f.Films.GetFilmById(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Брат 2", got.Name)
+		assert.Equal(t, "2000-05-11", got.Date)
+		assert.Equal(t, 8.1, got.Rating)
+		assert.Equal(t, []uuid.UUID{second}, castIds(got))
+		assert.False(t, got.UpdatedAt.Before(created.UpdatedAt))
+
+		actorFilms, err := f.Actors.GetActorById(ctx, first)
+		require.NoError(t, err)
+		assert.Empty(t, actorFilms.Films)
+	})
+
+	t.Run("removing a missing link is a no-op", func(t *testing.T) {
+		f := newFixture(t)
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+
+		require.NoError(t, f.Films.Update(ctx, created, nil, []uuid.UUID{uuid.New()}))
+	})
+
+	linkErrors := []struct {
+		name   string
+		actors func(t *testing.T, f Fixture, linked uuid.UUID) []uuid.UUID
+	}{
+		{
+			name:   "unknown actor",
+			actors: func(t *testing.T, f Fixture, linked uuid.UUID) []uuid.UUID { return []uuid.UUID{uuid.New()} },
+		},
+		{
+			name:   "actor already linked",
+			actors: func(t *testing.T, f Fixture, linked uuid.UUID) []uuid.UUID { return []uuid.UUID{linked} },
+		},
+		{
+			name: "valid actor before an unknown one",
+			actors: func(t *testing.T, f Fixture, linked uuid.UUID) []uuid.UUID {
+				return []uuid.UUID{createActor(t, f, actor("Виктор", "Сухоруков")), uuid.New()}
+			},
+		},
+	}
+	for _, tt := range linkErrors {
+		t.Run("update fails on "+tt.name+" and changes nothing", func(t *testing.T) {
+			f := newFixture(t)
+			linked := createActor(t, f, actor("Сергей", "Бодров"))
+			created := createFilm(t, f, film("Брат", "1997-12-12", 8.5), linked)
+
+			changed := film("Брат 2", "2000-05-11", 8.1)
+			changed.ID = created.ID
+			assert.Error(t, f.Films.Update(ctx, changed, tt.actors(t, f, linked), nil))
+
+			got, err := f.Films.GetFilmById(ctx, created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Брат", got.Name)
+			assert.Equal(t, []uuid.UUID{linked}, castIds(got))
+		})
+	}
+
+	t.Run("create fails on unknown actor and changes nothing", func(t *testing.T) {
+		f := newFixture(t)
+
+		assert.Error(t, f.Films.Create(ctx, film("Брат", "1997-12-12", 8.5), []uuid.UUID{uuid.New()}))
+
+		all, err := f.Films.GetAllFilms(sorted("name", "ASC"))
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("delete removes links", func(t *testing.T) {
+		f := newFixture(t)
+		a := createActor(t, f, actor("Сергей", "Бодров"))
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5), a)
+
+		require.NoError(t, f.Films.Delete(ctx, created.ID))
+
+		_, err := f.Films.GetFilmById(ctx, created.ID)
+		assertCode(t, http.StatusNotFound, err)
+
+		got, err := f.Actors.GetActorById(ctx, a)
+		require.NoError(t, err)
+		assert.Empty(t, got.Films)
+	})
+
+	t.Run("last modified moves on every write", func(t *testing.T) {
+		f := newFixture(t)
+		changed := func() time.Time {
+			t.Helper()
+			films, err := f.Films.LastModified(ctx)
+			require.NoError(t, err)
+			actors, err := f.Actors.LastModified(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, films, actors)
+			return films
+		}
+
+		before := changed()
+		a := createActor(t, f, actor("Сергей", "Бодров"))
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5), a)
+		afterCreate := changed()
+		assert.True(t, afterCreate.After(before))
+
+		edited := actor("Сергей", "Бодров-младший")
+		edited.ID = a
+		require.NoError(t, f.Actors.Edit(ctx, edited, nil, []uuid.UUID{created.ID}))
+		afterEdit := changed()
+		assert.True(t, afterEdit.After(afterCreate))
+
+		require.NoError(t, f.Films.Delete(ctx, created.ID))
+		assert.True(t, changed().After(afterEdit), "a deletion leaves no row to carry its time")
+	})
+
+	notFound := []struct {
+		name string
+		call func(f Fixture) error
+	}{
+		{name: "get", call: func(f Fixture) error { _, err := f.Films.GetFilmById(ctx, uuid.New()); return err }},
+		{name: "delete", call: func(f Fixture) error { return f.Films.Delete(ctx, uuid.New()) }},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name+" unknown film", func(t *testing.T) {
+			assertCode(t, http.StatusNotFound, tt.call(newFixture(t)))
+		})
+	}
+
+	t.Run("get by ids", func(t *testing.T) {
+		f := newFixture(t)
+		a := createActor(t, f, actor("Сергей", "Бодров"))
+		first := createFilm(t, f, film("Брат", "1997-12-12", 8.5), a)
+		second := createFilm(t, f, film("Брат 2", "2000-05-11", 8.1))
+
+		got, err := f.Films.GetFilmsByIds(ctx, []uuid.UUID{second.ID, uuid.New(), first.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Брат 2", "Брат"}, filmNames(got))
+		assert.Empty(t, got[0].Actors)
+		assert.Equal(t, []uuid.UUID{a}, castIds(got[1]))
+		assert.Equal(t, "1997-12-12", got[1].Date)
+
+		got, err = f.Films.GetFilmsByIds(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("sorting", func(t *testing.T) {
+		f := newFixture(t)
+		createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+		createFilm(t, f, film("Астрал", "2010-09-14", 6.8))
+		createFilm(t, f, film("Война", "2002-03-14", 7.2))
+
+		tests := []struct {
+			field    string
+			order    string
+			expected []string
+		}{
+			{field: "name", order: "ASC", expected: []string{"Астрал", "Брат", "Война"}},
+			{field: "name", order: "DESC", expected: []string{"Война", "Брат", "Астрал"}},
+			{field: "date", order: "ASC", expected: []string{"Брат", "Война", "Астрал"}},
+			{field: "date", order: "DESC", expected: []string{"Астрал", "Война", "Брат"}},
+			{field: "rating", order: "ASC", expected: []string{"Астрал", "Война", "Брат"}},
+			{field: "rating", order: "DESC", expected: []string{"Брат", "Война", "Астрал"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.field+" "+tt.order, func(t *testing.T) {
+				all, err := f.Films.GetAllFilms(sorted(tt.field, tt.order))
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, filmNames(all))
+			})
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		f := newFixture(t)
+		bodrov := createActor(t, f, actor("Сергей", "Бодров"))
+		createFilm(t, f, film("Брат", "1997-12-12", 8.5), bodrov)
+		createFilm(t, f, film("Брат 2", "2000-05-11", 8.1), bodrov)
+		createFilm(t, f, film("Астрал", "2010-09-14", 6.8))
+
+		tests := []struct {
+			name     string
+			search   func() ([]models.Film, error)
+			expected []string
+		}{
+			{name: "by name", search: func() ([]models.Film, error) { return f.Films.GetFilmByName(ctx, "Брат") }, expected: []string{"Брат", "Брат 2"}},
+			{name: "by transliterated name", search: func() ([]models.Film, error) { return f.Films.GetFilmByName(ctx, "brat") }, expected: []string{"Брат", "Брат 2"}},
+			{name: "by name with a typo", search: func() ([]models.Film, error) { return f.Films.GetFilmByName(ctx, "Астрол") }, expected: []string{"Астрал"}},
+			{name: "by name without match", search: func() ([]models.Film, error) { return f.Films.GetFilmByName(ctx, "Матрица") }, expected: []string{}},
+			{name: "by actor", search: func() ([]models.Film, error) { return f.Films.GetFilmByActor(ctx, "Бодров") }, expected: []string{"Брат", "Брат 2"}},
+			{name: "by actor without match", search: func() ([]models.Film, error) { return f.Films.GetFilmByActor(ctx, "Киану") }, expected: []string{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := tt.search()
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, filmNames(got))
+
+				for _, film := range got {
+					assert.Greater(t, film.Score, 0.0)
+				}
+				assert.True(t, slices.IsSortedFunc(got, func(a, b models.Film) int {
+					switch {
+					case a.Score > b.Score:
+						return -1
+					case a.Score < b.Score:
+						return 1
+					default:
+						return 0
+					}
+				}))
+			})
+		}
+	})
+}
+
+func runActors(t *testing.T, newFixture func(t *testing.T) Fixture) {
+	t.Run("create and get", func(t *testing.T) {
+		f := newFixture(t)
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+
+		id := createActor(t, f, actor("Сергей", "Бодров"), created.ID)
+
+		got, err := f.Actors.GetActorById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+		assert.Equal(t, "Сергей", got.Name)
+		assert.Equal(t, "Бодров", got.SecondName)
+		assert.Equal(t, "Мужчина", got.Sex)
+		assert.Equal(t, "1971-12-27", got.DateOfBirth)
+		assert.Equal(t, []models.ActorFilm{{ID: created.ID, Name: "Брат"}}, got.Films)
+
+		linked, err := f.Films.GetFilmById(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{id}, castIds(linked))
+		assert.False(t, linked.UpdatedAt.Before(created.UpdatedAt))
+	})
+
+	t.Run("edit fields and links", func(t *testing.T) {
+		f := newFixture(t)
+		first := createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+		second := createFilm(t, f, film("Брат 2", "2000-05-11", 8.1))
+		id := createActor(t, f, actor("Сергей", "Бодров"), first.ID)
+
+		changed := actor("Сергей", "Бодров-младший")
+		changed.ID = id
+		require.NoError(t, f.Actors.Edit(ctx, changed, []uuid.UUID{second.ID}, []uuid.UUID{first.ID}))
+
+		got, err := f.Actors.GetActorById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Бодров-младший", got.SecondName)
+		assert.Equal(t, []models.ActorFilm{{ID: second.ID, Name: "Брат 2"}}, got.Films)
+	})
+
+	t.Run("edit fails on unknown film and changes nothing", func(t *testing.T) {
+		f := newFixture(t)
+		id := createActor(t, f, actor("Сергей", "Бодров"))
+
+		changed := actor("Сергей", "Бодров-младший")
+		changed.ID = id
+		assert.Error(t, f.Actors.Edit(ctx, changed, []uuid.UUID{uuid.New()}, nil))
+
+		got, err := f.Actors.GetActorById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Бодров", got.SecondName)
+	})
+
+	t.Run("create fails on unknown film", func(t *testing.T) {
+		f := newFixture(t)
+
+		_, err := f.Actors.Create(ctx, actor("Сергей", "Бодров"), []uuid.UUID{uuid.New()})
+		assert.Error(t, err)
+
+		all, err := f.Actors.GetAllActors(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("delete removes links", func(t *testing.T) {
+		f := newFixture(t)
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+		id := createActor(t, f, actor("Сергей", "Бодров"), created.ID)
+
+		require.NoError(t, f.Actors.Delete(ctx, id))
+
+		_, err := f.Actors.GetActorById(ctx, id)
+		assertCode(t, http.StatusNotFound, err)
+
+		got, err := f.Films.GetFilmById(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Empty(t, got.Actors)
+	})
+
+	notFound := []struct {
+		name string
+		call func(f Fixture) error
+	}{
+		{name: "get", call: func(f Fixture) error { _, err := f.Actors.GetActorById(ctx, uuid.New()); return err }},
+		{name: "delete", call: func(f Fixture) error { return f.Actors.Delete(ctx, uuid.New()) }},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name+" unknown actor", func(t *testing.T) {
+			assertCode(t, http.StatusNotFound, tt.call(newFixture(t)))
+		})
+	}
+
+	t.Run("get all", func(t *testing.T) {
+		f := newFixture(t)
+		first := createActor(t, f, actor("Сергей", "Бодров"))
+		second := createActor(t, f, actor("Виктор", "Сухоруков"))
+
+		all, err := f.Actors.GetAllActors(ctx)
+		require.NoError(t, err)
+
+		ids := make([]uuid.UUID, 0, len(all))
+		for _, a := range all {
+			ids = append(ids, a.ID)
+		}
+		assert.ElementsMatch(t, []uuid.UUID{first, second}, ids)
+	})
+
+	t.Run("get by ids", func(t *testing.T) {
+		f := newFixture(t)
+		created := createFilm(t, f, film("Брат", "1997-12-12", 8.5))
+		first := createActor(t, f, actor("Сергей", "Бодров"), created.ID)
+		second := createActor(t, f, actor("Виктор", "Сухоруков"))
+
+		got, err := f.Actors.GetActorsByIds(ctx, []uuid.UUID{second, uuid.New(), first})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, second, got[0].ID)
+		assert.Empty(t, got[0].Films)
+		assert.Equal(t, first, got[1].ID)
+		assert.Equal(t, "1971-12-27", got[1].DateOfBirth)
+		assert.Equal(t, []models.ActorFilm{{ID: created.ID, Name: "Брат"}}, got[1].Films)
+	})
+
+	t.Run("search by name", func(t *testing.T) {
+		f := newFixture(t)
+		createActor(t, f, actor("Сергей", "Бодров"))
+		createActor(t, f, actor("Виктор", "Сухоруков"))
+
+		tests := []struct {
+			query    string
+			expected []string
+		}{
+			{query: "Бодров", expected: []string{"Бодров"}},
+			{query: "bodrov", expected: []string{"Бодров"}},
+			{query: "Сухаруков", expected: []string{"Сухоруков"}},
+			{query: "Киану", expected: []string{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.query, func(t *testing.T) {
+				got, err := f.Actors.GetActorsByName(ctx, tt.query)
+				require.NoError(t, err)
+
+				names := make([]string, 0, len(got))
+				for _, a := range got {
+					names = append(names, a.SecondName)
+					assert.Greater(t, a.Score, 0.0)
+				}
+				assert.Equal(t, tt.expected, names)
+			})
+		}
+	})
+}
+
+func runUsers(t *testing.T, newFixture func(t *testing.T) Fixture) {
+	f := newFixture(t)
+	name := "contract-" + uuid.NewString()[:8]
+	id := f.AddUser(t, name, "secret", "администратор")
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		ok       bool
+	}{
+		{name: "valid credentials", username: name, password: "secret", ok: true},
+		{name: "wrong password", username: name, password: "wrong"},
+		{name: "unknown user", username: "contract-unknown", password: "secret"},
+		{name: "empty password", username: name, password: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userId, role, err := f.Users.GetUserIdRole(tt.username, tt.password)
+			if !tt.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, id, userId)
+			assert.Equal(t, "администратор", role)
+		})
+	}
+}
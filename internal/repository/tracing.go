@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/pkg/tracing"
+)
+
+type TracedFilms struct {
+	next Films
+}
+
+func NewTracedFilms(next Films) *TracedFilms {
+	return &TracedFilms{
+		next: next,
+	}
+}
+
+func (r *TracedFilms) Create(ctx context.Context, film models.Film, actors []uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.Create")
+	defer tracing.End(span, &err)
+
+	return r.next.Create(ctx, film, actors)
+}
+
+func (r *TracedFilms) Update(ctx context.Context, film models.Film, actorsToAdd []uuid.UUID, actorsToDel []uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.Update")
+	defer tracing.End(span, &err)
+
+	return r.next.Update(ctx, film, actorsToAdd, actorsToDel)
+}
+
+func (r *TracedFilms) Delete(ctx context.Context, filmId uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.Delete")
+	defer tracing.End(span, &err)
+
+	return r.next.Delete(ctx, filmId)
+}
+
+func (r *TracedFilms) GetFilmByName(ctx context.Context, name string) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.GetFilmByName")
+	defer tracing.End(span, &err)
+
+	return r.next.GetFilmByName(ctx, name)
+}
+
+func (r *TracedFilms) GetFilmByActor(ctx context.Context, actorName string) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.GetFilmByActor")
+	defer tracing.End(span, &err)
+
+	return r.next.GetFilmByActor(ctx, actorName)
+}
+
+func (r *TracedFilms) GetAllFilms(ctx context.Context) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.GetAllFilms")
+	defer tracing.End(span, &err)
+
+	return r.next.GetAllFilms(ctx)
+}
+
+func (r *TracedFilms) GetFilmById(ctx context.Context, filmId uuid.UUID) (film models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.GetFilmById")
+	defer tracing.End(span, &err)
+
+	return r.next.GetFilmById(ctx, filmId)
+}
+
+func (r *TracedFilms) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.GetFilmsByIds")
+	defer tracing.End(span, &err)
+
+	return r.next.GetFilmsByIds(ctx, ids)
+}
+
+func (r *TracedFilms) LastModified(ctx context.Context) (changed time.Time, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsRepo.LastModified")
+	defer tracing.End(span, &err)
+
+	return r.next.LastModified(ctx)
+}
+
+type TracedActors struct {
+	next Actors
+}
+
+func NewTracedActors(next Actors) *TracedActors {
+	return &TracedActors{
+		next: next,
+	}
+}
+
+func (r *TracedActors) Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) (id uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.Create")
+	defer tracing.End(span, &err)
+
+	return r.next.Create(ctx, actor, actorFilms)
+}
+
+func (r *TracedActors) Edit(ctx context.Context, actor models.Actor, filmsToAdd []uuid.UUID, filmsToDel []uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.Edit")
+	defer tracing.End(span, &err)
+
+	return r.next.Edit(ctx, actor, filmsToAdd, filmsToDel)
+}
+
+func (r *TracedActors) Delete(ctx context.Context, actorId uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.Delete")
+	defer tracing.End(span, &err)
+
+	return r.next.Delete(ctx, actorId)
+}
+
+func (r *TracedActors) GetAllActors(ctx context.Context) (actors []models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.GetAllActors")
+	defer tracing.End(span, &err)
+
+	return r.next.GetAllActors(ctx)
+}
+
+func (r *TracedActors) GetActorsByName(ctx context.Context, name string) (actors []models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.GetActorsByName")
+	defer tracing.End(span, &err)
+
+	return r.next.GetActorsByName(ctx, name)
+}
+
+func (r *TracedActors) GetActorById(ctx context.Context, actorId uuid.UUID) (actor models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.GetActorById")
+	defer tracing.End(span, &err)
+
+	return r.next.GetActorById(ctx, actorId)
+}
+
+func (r *TracedActors) GetActorsByIds(ctx context.Context, ids []uuid.UUID) (actors []models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.GetActorsByIds")
+	defer tracing.End(span, &err)
+
+	return r.next.GetActorsByIds(ctx, ids)
+}
+
+func (r *TracedActors) LastModified(ctx context.Context) (changed time.Time, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsRepo.LastModified")
+	defer tracing.End(span, &err)
+
+	return r.next.LastModified(ctx)
+}
+
+type TracedSearch struct {
+	next Search
+}
+
+func NewTracedSearch(next Search) *TracedSearch {
+	return &TracedSearch{
+		next: next,
+	}
+}
+
+func (r *TracedSearch) Search(ctx context.Context, query string, limit int, offset int) (results []models.SearchResult, total int, err error) {
+	ctx, span := tracing.Start(ctx, "SearchRepo.Search")
+	defer tracing.End(span, &err)
+
+	return r.next.Search(ctx, query, limit, offset)
+}
+
+func (r *TracedSearch) SuggestFilms(ctx context.Context, prefix string, limit int) (suggestions []models.Suggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SearchRepo.SuggestFilms")
+	defer tracing.End(span, &err)
+
+	return r.next.SuggestFilms(ctx, prefix, limit)
+}
+
+func (r *TracedSearch) SuggestActors(ctx context.Context, prefix string, limit int) (suggestions []models.Suggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SearchRepo.SuggestActors")
+	defer tracing.End(span, &err)
+
+	return r.next.SuggestActors(ctx, prefix, limit)
+}
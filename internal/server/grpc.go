@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"net"
+	"vk-test-spring/internal/config"
+)
+
+// GRPCServer runs the gRPC API on its own port, next to Server.
+type GRPCServer struct {
+	grpcServer *grpc.Server
+	addr       string
+}
+
+func NewGRPCServer(cfg *config.Config, grpcServer *grpc.Server) *GRPCServer {
+	return &GRPCServer{grpcServer: grpcServer, addr: ":" + cfg.GRPC.Port}
+}
+
+// Run listens on the configured address and serves until Stop is called.
+func (s *GRPCServer) Run() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on listener, which lets tests bind to a random port.
+func (s *GRPCServer) Serve(listener net.Listener) error {
+	return s.grpcServer.Serve(listener)
+}
+
+// Stop stops accepting connections and waits for in-flight calls, streams included, to finish.
+// If ctx expires first, the remaining calls are cancelled and ctx.Err() is returned.
+func (s *GRPCServer) Stop(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
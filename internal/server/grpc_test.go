@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"net"
+	"testing"
+	"time"
+	"vk-test-spring/internal/config"
+)
+
+func newTestGRPCServer(t *testing.T) (*GRPCServer, grpc_health_v1.HealthClient, chan error) {
+	s := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, health.NewServer())
+
+	srv := NewGRPCServer(&config.Config{}, s)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(listener)
+	}()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return srv, grpc_health_v1.NewHealthClient(conn), served
+}
+
+func TestGRPCServerStop(t *testing.T) {
+	t.Run("stops gracefully", func(t *testing.T) {
+		srv, client, served := newTestGRPCServer(t)
+
+		_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		assert.NoError(t, srv.Stop(context.Background()))
+		assert.NoError(t, <-served)
+	})
+
+	t.Run("deadline cuts off open streams", func(t *testing.T) {
+		srv, client, served := newTestGRPCServer(t)
+
+		stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, srv.Stop(ctx), context.DeadlineExceeded)
+		assert.NoError(t, <-served)
+
+		_, err = stream.Recv()
+		assert.Error(t, err)
+	})
+}
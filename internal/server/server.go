@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/http"
 	"vk-test-spring/internal/config"
 )
@@ -19,10 +21,43 @@ func NewServer(cfg *config.Config, mux *http.ServeMux) *Server {
 	}}
 }
 
+func NewAdminServer(cfg *config.Config, handler http.Handler) *Server {
+	return &Server{httpServer: &http.Server{
+		Addr:         ":" + cfg.Admin.Port,
+		ReadTimeout:  cfg.HTTP.ReadTimeout,
+		WriteTimeout: cfg.HTTP.WriteTimeout,
+		Handler:      handler,
+	}}
+}
+
+// Run listens on the configured address and serves until Stop is called. A server stopped
+// through Stop returns nil rather than http.ErrServerClosed.
 func (s *Server) Run() error {
-	return s.httpServer.ListenAndServe()
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
 }
 
+// Serve accepts connections on listener, which lets tests bind to a random port.
+func (s *Server) Serve(listener net.Listener) error {
+	err := s.httpServer.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Stop closes the listeners, so no new connections are accepted, and waits for in-flight requests
+// to finish. If ctx expires first, the remaining connections are closed and ctx.Err() is returned.
 func (s *Server) Stop(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		s.httpServer.Close()
+	}
+
+	return err
 }
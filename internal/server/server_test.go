@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+	"vk-test-spring/internal/config"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*Server, string, chan error) {
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+
+	srv := NewServer(&config.Config{HTTP: config.HTTPConfig{ReadTimeout: time.Second, WriteTimeout: 5 * time.Second}}, mux)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(listener)
+	}()
+
+	return srv, "http://" + listener.Addr().String(), served
+}
+
+func TestServerStop(t *testing.T) {
+	t.Run("drains in-flight requests", func(t *testing.T) {
+		const requests = 20
+
+		arrived := make(chan struct{}, requests)
+		release := make(chan struct{})
+
+		srv, url, served := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			arrived <- struct{}{}
+			<-release
+			fmt.Fprint(w, "done")
+		})
+
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+		var wg sync.WaitGroup
+		codes := make(chan int, requests)
+		errs := make(chan error, requests)
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				resp, err := client.Get(url)
+				if err != nil {
+					errs <- err
+					return
+				}
+				defer resp.Body.Close()
+
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != "done" {
+					errs <- fmt.Errorf("unexpected body %q", body)
+					return
+				}
+				codes <- resp.StatusCode
+			}()
+		}
+
+		for i := 0; i < requests; i++ {
+			<-arrived
+		}
+
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- srv.Stop(context.Background())
+		}()
+
+		// Shutdown closes the listener first, so a new request is refused while old ones still run.
+		require.Eventually(t, func() bool {
+			_, err := client.Get(url)
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+
+		close(release)
+		wg.Wait()
+		close(codes)
+		close(errs)
+
+		for err := range errs {
+			t.Errorf("request dropped: %v", err)
+		}
+
+		count := 0
+		for code := range codes {
+			assert.Equal(t, http.StatusOK, code)
+			count++
+		}
+		assert.Equal(t, requests, count)
+
+		assert.NoError(t, <-stopped)
+		assert.NoError(t, <-served)
+	})
+
+	t.Run("deadline cuts off slow requests", func(t *testing.T) {
+		arrived := make(chan struct{})
+		srv, url, served := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			close(arrived)
+			<-r.Context().Done()
+		})
+
+		go http.Get(url)
+		<-arrived
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, srv.Stop(ctx), context.DeadlineExceeded)
+		assert.NoError(t, <-served)
+	})
+}
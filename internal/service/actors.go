@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"slices"
 	"time"
+	"vk-test-spring/internal/metrics"
 	"vk-test-spring/internal/models"
 	"vk-test-spring/internal/repository"
 )
@@ -108,31 +109,38 @@ func (in *ActorInfo) validateDOB() error {
 	return err
 }
 
+func (in *ActorInfo) actor() models.Actor {
+	return models.Actor{
+		Name:        in.Name,
+		SecondName:  in.SecondName,
+		Patronymic:  in.Patronymic,
+		Sex:         in.Sex,
+		DateOfBirth: in.DateOfBirth,
+	}
+}
+
 type ActorCreateInput struct {
 	ActorInfo ActorInfo
 	Films     []uuid.UUID
 }
 
 func (s *ActorsService) AddActor(ctx context.Context, input ActorCreateInput) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
 	err := input.ActorInfo.validate()
 	if err != nil {
 		return models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
 	}
 
-	actor := models.Actor{
-		Name:        input.ActorInfo.Name,
-		SecondName:  input.ActorInfo.SecondName,
-		Patronymic:  input.ActorInfo.Patronymic,
-		Sex:         input.ActorInfo.Sex,
-		DateOfBirth: input.ActorInfo.DateOfBirth,
-	}
-
-	err = s.repo.Create(ctx, actor, input.Films)
+	_, err = s.repo.Create(ctx, input.ActorInfo.actor(), input.Films)
 	if err != nil {
 		return err
 	}
 
-	return err
+	metrics.ActorsCreated.Inc()
+	return nil
 }
 
 type ActorUpdateInput struct {
@@ -143,6 +151,10 @@ type ActorUpdateInput struct {
 }
 
 func (s *ActorsService) UpdateActor(ctx context.Context, input ActorUpdateInput) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
 	actor := models.Actor{
 		ID:          input.ID,
 		Name:        input.ActorInfo.Name,
@@ -190,6 +202,10 @@ func (s *ActorsService) UpdateActor(ctx context.Context, input ActorUpdateInput)
 }
 
 func (s *ActorsService) DeleteActor(ctx context.Context, actorId uuid.UUID) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
 	return s.repo.Delete(ctx, actorId)
 }
 
@@ -205,6 +221,15 @@ func (s *ActorsService) GetActorByName(ctx context.Context, name string) ([]mode
 	return s.repo.GetActorsByName(ctx, name)
 }
 
+func (s *ActorsService) GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error) {
+	return s.repo.GetActorsByIds(ctx, ids)
+}
+
+// LastModified returns when any actor, film or link between them last changed, deletes included.
+func (s *ActorsService) LastModified(ctx context.Context) (time.Time, error) {
+	return s.repo.LastModified(ctx)
+}
+
 func (s *ActorsService) mergeChanges(actor models.Actor, oldActor models.Actor) (models.Actor, error) {
 	if actor.Name == "" {
 		actor.Name = oldActor.Name
@@ -7,7 +7,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"net/http"
 	"testing"
+	"time"
 	"vk-test-spring/internal/models"
 )
 
@@ -15,9 +17,9 @@ type MockActorRepository struct {
 	mock.Mock
 }
 
-func (m *MockActorRepository) Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) error {
+func (m *MockActorRepository) Create(ctx context.Context, actor models.Actor, actorFilms []uuid.UUID) (uuid.UUID, error) {
 	args := m.Called(ctx, actor, actorFilms)
-	return args.Error(0)
+	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
 func (m *MockActorRepository) Edit(ctx context.Context, actor models.Actor, filmsToAdd []uuid.UUID, filmsToDel []uuid.UUID) error {
@@ -45,6 +47,16 @@ func (m *MockActorRepository) GetActorById(ctx context.Context, actorId uuid.UUI
 	return args.Get(0).(models.Actor), args.Error(1)
 }
 
+func (m *MockActorRepository) GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]models.Actor), args.Error(1)
+}
+
+func (m *MockActorRepository) LastModified(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 // TODO rewrite test cases like 1st
 func TestActorService_AddActor(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
@@ -62,19 +74,19 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", context.Background(), models.Actor{
+		repo.On("Create", adminCtx, models.Actor{
 			Name:        input.ActorInfo.Name,
 			SecondName:  input.ActorInfo.SecondName,
 			Patronymic:  input.ActorInfo.Patronymic,
 			Sex:         input.ActorInfo.Sex,
 			DateOfBirth: input.ActorInfo.DateOfBirth,
-		}, input.Films).Return(nil)
+		}, input.Films).Return(uuid.New(), nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "Create", context.Background(), models.Actor{
+		repo.AssertCalled(t, "Create", adminCtx, models.Actor{
 			Name:        input.ActorInfo.Name,
 			SecondName:  input.ActorInfo.SecondName,
 			Patronymic:  input.ActorInfo.Patronymic,
@@ -97,9 +109,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid name format. field must contain"+
@@ -121,9 +133,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid second_name format. field must contain"+
@@ -145,9 +157,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid patronymic format. field must contain"+
@@ -169,9 +181,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "input actors's name too short. Length of name must be between 1 and 150,"+
@@ -193,9 +205,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "input actors's second_name too short. Length of name must be between 1 and 150,"+
@@ -217,9 +229,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.NoError(t, err)
 		repo.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
@@ -239,9 +251,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "input actor's name too long. length of name must be between 1 and 150,"+
@@ -263,9 +275,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "input actor's second_name too long. length of name must be between 1 and 150,"+
@@ -287,9 +299,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "input actor's patronymic too long. length of name must be between 1 and 150,"+
@@ -311,9 +323,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.NoError(t, err)
 		repo.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
@@ -333,9 +345,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "invalid value in sex field. field value must be equal to"+
@@ -357,9 +369,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.NoError(t, err)
 		repo.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
@@ -379,9 +391,9 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
 		//assert.EqualError(t, err, "invalid value in sex field. field value must be equal to"+
@@ -403,13 +415,13 @@ func TestActorService_AddActor(t *testing.T) {
 			Films: nil,
 		}
 
-		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, nil)
 
-		err := actorService.AddActor(context.Background(), input)
+		err := actorService.AddActor(adminCtx, input)
 
 		assert.Error(t, err)
-		assert.EqualError(t, err, "input actors's birthday not in range. date must be in range 1900-01-01 and 2024-03-18, "+
-			"but has: 1885-01-01")
+		assert.EqualError(t, err, "input actors's birthday not in range. date must be in range 1900-01-01 and "+
+			time.Now().Format(time.DateOnly)+", but has: 1885-01-01")
 		repo.AssertNotCalled(t, "Create")
 	})
 }
@@ -443,32 +455,32 @@ func TestActorsService_UpdateActor(t *testing.T) {
 		}
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedFromGetActorById, nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedFromGetActorById, nil)
 
 		// Устанавливаем ожидание для вызова метода Edit
-		repo.On("Edit", context.Background(), expectedFromGetActorById, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("Edit", adminCtx, expectedFromGetActorById, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
 		// Вызываем метод UpdateActor
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		// Проверяем, что нет ошибок
 		assert.NoError(t, err)
 
 		// Проверяем, что метод GetActorById был вызван с правильными аргументами
-		repo.AssertCalled(t, "GetActorById", context.Background(), input.ID)
+		repo.AssertCalled(t, "GetActorById", adminCtx, input.ID)
 
 		// Проверяем, что метод Edit был вызван с правильными аргументами
-		repo.AssertCalled(t, "Edit", context.Background(), expectedFromGetActorById, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertCalled(t, "Edit", adminCtx, expectedFromGetActorById, input.FilmsToAdd, input.FilmsToDel)
 	})
 
 	t.Run("Record Not Found", func(t *testing.T) {
 		repo := new(MockActorRepository)
 		actorService := ActorsService{repo: repo}
 
-		repo.On("GetActorById", context.Background(), uuid.Nil).Return(models.Actor{ID: uuid.Nil}, errors.New("record not found"))
+		repo.On("GetActorById", adminCtx, uuid.Nil).Return(models.Actor{ID: uuid.Nil}, errors.New("record not found"))
 
 		// Вызываем метод UpdateActor
-		err := actorService.UpdateActor(context.Background(), ActorUpdateInput{ID: uuid.Nil})
+		err := actorService.UpdateActor(adminCtx, ActorUpdateInput{ID: uuid.Nil})
 		// Проверяем, что ошибка возвращается и она соответствует ожидаемой ошибке "запись не найдена".
 		//assert.Error(t, err)
 		assert.Equal(t, "record not found", err.Error())
@@ -504,14 +516,14 @@ func TestActorsService_UpdateActor(t *testing.T) {
 			Films:       nil,
 		}
 
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedGetActor, nil)
-		repo.On("Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedGetActor, nil)
+		repo.On("Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertCalled(t, "Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
 	})
 
 	t.Run("Good Parse List To del", func(t *testing.T) {
@@ -547,26 +559,26 @@ func TestActorsService_UpdateActor(t *testing.T) {
 		}
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedGetActor, nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedGetActor, nil)
 
 		// Создаём копию актёра с пустым списком фильмов
 		expectedEditedActor := expectedGetActor
 		expectedEditedActor.Films = nil
 
 		// Устанавливаем ожидание для вызова метода Edit
-		repo.On("Edit", context.Background(), expectedEditedActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("Edit", adminCtx, expectedEditedActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
 		// Вызываем метод UpdateActor
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		// Проверяем, что нет ошибок
 		assert.NoError(t, err)
 
 		// Проверяем, что метод GetActorById был вызван с правильными аргументами
-		repo.AssertCalled(t, "GetActorById", context.Background(), input.ID)
+		repo.AssertCalled(t, "GetActorById", adminCtx, input.ID)
 
 		// Проверяем, что метод Edit был вызван с правильными аргументами
-		repo.AssertCalled(t, "Edit", context.Background(), expectedEditedActor, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertCalled(t, "Edit", adminCtx, expectedEditedActor, input.FilmsToAdd, input.FilmsToDel)
 	})
 
 	t.Run("Bad Parse List To add", func(t *testing.T) {
@@ -601,15 +613,15 @@ func TestActorsService_UpdateActor(t *testing.T) {
 			}},
 		}
 
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedGetActor, nil)
-		//repo.On("Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedGetActor, nil)
+		//repo.On("Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("films_to_add film_id that is already in actors_films: %v", added))
 
-		repo.AssertNotCalled(t, "Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertNotCalled(t, "Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
 	})
 
 	t.Run("Bad Parse List To del", func(t *testing.T) {
@@ -641,15 +653,15 @@ func TestActorsService_UpdateActor(t *testing.T) {
 			Films:       nil,
 		}
 
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedGetActor, nil)
-		//repo.On("Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedGetActor, nil)
+		//repo.On("Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("films_to_del contains film_id that not in actors_films: %v", deleted))
 
-		repo.AssertNotCalled(t, "Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertNotCalled(t, "Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
 	})
 
 	t.Run("Bad Parse List To del and To add", func(t *testing.T) {
@@ -681,15 +693,15 @@ func TestActorsService_UpdateActor(t *testing.T) {
 			Films:       nil,
 		}
 
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedGetActor, nil)
-		//repo.On("Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedGetActor, nil)
+		//repo.On("Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("films_to_add and films_to_del contains same film_id: %v", deleted))
 
-		repo.AssertNotCalled(t, "Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertNotCalled(t, "Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
 	})
 
 	t.Run("Merge Changes", func(t *testing.T) {
@@ -721,15 +733,15 @@ func TestActorsService_UpdateActor(t *testing.T) {
 			Films:       nil,
 		}
 
-		repo.On("GetActorById", context.Background(), input.ID).Return(expectedGetActor, nil)
-		repo.On("Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
+		repo.On("GetActorById", adminCtx, input.ID).Return(expectedGetActor, nil)
+		repo.On("Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel).Return(nil)
 
-		err := actorService.UpdateActor(context.Background(), input)
+		err := actorService.UpdateActor(adminCtx, input)
 
 		assert.NoError(t, err)
 		//assert.EqualError(t, err, fmt.Sprintf("films_to_add and films_to_del contains same film_id: %v", deleted))
 
-		repo.AssertCalled(t, "Edit", context.Background(), expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
+		repo.AssertCalled(t, "Edit", adminCtx, expectedGetActor, input.FilmsToAdd, input.FilmsToDel)
 	})
 }
 
@@ -740,15 +752,15 @@ func TestActorsService_DeleteActor(t *testing.T) {
 
 		toDel := uuid.New()
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("Delete", context.Background(), toDel).Return(nil)
+		repo.On("Delete", adminCtx, toDel).Return(nil)
 
 		// Вызываем метод UpdateActor
-		err := actorService.DeleteActor(context.Background(), toDel)
+		err := actorService.DeleteActor(adminCtx, toDel)
 
 		// Проверяем, что нет ошибок
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "Delete", context.Background(), toDel)
+		repo.AssertCalled(t, "Delete", adminCtx, toDel)
 	})
 
 	t.Run("Record Not Found", func(t *testing.T) {
@@ -757,15 +769,25 @@ func TestActorsService_DeleteActor(t *testing.T) {
 
 		toDel := uuid.New()
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("Delete", context.Background(), toDel).Return(errors.New("record not found"))
+		repo.On("Delete", adminCtx, toDel).Return(errors.New("record not found"))
 
 		// Вызываем метод UpdateActor
-		err := actorService.DeleteActor(context.Background(), toDel)
+		err := actorService.DeleteActor(adminCtx, toDel)
 
 		// Проверяем, что нет ошибок
 		assert.Error(t, err)
 		assert.EqualError(t, err, "record not found")
-		repo.AssertCalled(t, "Delete", context.Background(), toDel)
+		repo.AssertCalled(t, "Delete", adminCtx, toDel)
+	})
+	t.Run("Forbidden for users", func(t *testing.T) {
+		repo := new(MockActorRepository)
+		actorService := ActorsService{repo: repo}
+
+		userCtx := context.WithValue(context.Background(), "role", models.RoleUser)
+		err := actorService.DeleteActor(userCtx, uuid.New())
+
+		assert.Equal(t, models.CustomError{Code: http.StatusForbidden, Message: "Forbidden"}, err)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 	})
 }
 
@@ -775,15 +797,15 @@ func TestActorsService_GetAllActors(t *testing.T) {
 		actorService := ActorsService{repo: repo}
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetAllActors", context.Background()).Return([]models.Actor{{ID: uuid.New()}}, nil)
+		repo.On("GetAllActors", adminCtx).Return([]models.Actor{{ID: uuid.New()}}, nil)
 
 		// Вызываем метод UpdateActor
-		_, err := actorService.GetAllActors(context.Background())
+		_, err := actorService.GetAllActors(adminCtx)
 
 		// Проверяем, что нет ошибок
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "GetAllActors", context.Background())
+		repo.AssertCalled(t, "GetAllActors", adminCtx)
 	})
 
 	t.Run("Records Not Found", func(t *testing.T) {
@@ -791,16 +813,16 @@ func TestActorsService_GetAllActors(t *testing.T) {
 		actorService := ActorsService{repo: repo}
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetAllActors", context.Background()).Return([]models.Actor{}, errors.New("records not found"))
+		repo.On("GetAllActors", adminCtx).Return([]models.Actor{}, errors.New("records not found"))
 
 		// Вызываем метод UpdateActor
-		_, err := actorService.GetAllActors(context.Background())
+		_, err := actorService.GetAllActors(adminCtx)
 
 		// Проверяем, что нет ошибок
 		assert.Error(t, err)
 		assert.EqualError(t, err, "records not found")
 
-		repo.AssertCalled(t, "GetAllActors", context.Background())
+		repo.AssertCalled(t, "GetAllActors", adminCtx)
 	})
 }
 
@@ -812,15 +834,15 @@ func TestActorsService_GetActorById(t *testing.T) {
 		id := uuid.New()
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetActorById", context.Background(), id).Return(models.Actor{ID: id}, nil)
+		repo.On("GetActorById", adminCtx, id).Return(models.Actor{ID: id}, nil)
 
 		// Вызываем метод UpdateActor
-		_, err := actorService.GetActorById(context.Background(), id)
+		_, err := actorService.GetActorById(adminCtx, id)
 
 		// Проверяем, что нет ошибок
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "GetActorById", context.Background(), id)
+		repo.AssertCalled(t, "GetActorById", adminCtx, id)
 	})
 
 	t.Run("not found actor by id", func(t *testing.T) {
@@ -830,16 +852,16 @@ func TestActorsService_GetActorById(t *testing.T) {
 		id := uuid.New()
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetActorById", context.Background(), id).Return(models.Actor{}, errors.New("record not found"))
+		repo.On("GetActorById", adminCtx, id).Return(models.Actor{}, errors.New("record not found"))
 
 		// Вызываем метод UpdateActor
-		_, err := actorService.GetActorById(context.Background(), id)
+		_, err := actorService.GetActorById(adminCtx, id)
 
 		// Проверяем, что нет ошибок
 		assert.Error(t, err)
 		assert.EqualError(t, err, "record not found")
 
-		repo.AssertCalled(t, "GetActorById", context.Background(), id)
+		repo.AssertCalled(t, "GetActorById", adminCtx, id)
 	})
 }
 
@@ -851,15 +873,15 @@ func TestActorsService_GetActorByName(t *testing.T) {
 		name := "test"
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetActorsByName", context.Background(), name).Return([]models.Actor{{Name: name}}, nil)
+		repo.On("GetActorsByName", adminCtx, name).Return([]models.Actor{{Name: name}}, nil)
 
 		// Вызываем метод UpdateActor
-		_, err := actorService.GetActorByName(context.Background(), name)
+		_, err := actorService.GetActorByName(adminCtx, name)
 
 		// Проверяем, что нет ошибок
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "GetActorsByName", context.Background(), name)
+		repo.AssertCalled(t, "GetActorsByName", adminCtx, name)
 	})
 
 	t.Run("not found actor by id", func(t *testing.T) {
@@ -869,15 +891,15 @@ func TestActorsService_GetActorByName(t *testing.T) {
 		name := "test"
 
 		// Устанавливаем ожидание для вызова метода GetActorById
-		repo.On("GetActorsByName", context.Background(), name).Return([]models.Actor{}, errors.New("records not found"))
+		repo.On("GetActorsByName", adminCtx, name).Return([]models.Actor{}, errors.New("records not found"))
 
 		// Вызываем метод UpdateActor
-		_, err := actorService.GetActorByName(context.Background(), name)
+		_, err := actorService.GetActorByName(adminCtx, name)
 
 		// Проверяем, что нет ошибок
 		assert.Error(t, err)
 		assert.EqualError(t, err, "records not found")
 
-		repo.AssertCalled(t, "GetActorsByName", context.Background(), name)
+		repo.AssertCalled(t, "GetActorsByName", adminCtx, name)
 	})
 }
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"vk-test-spring/internal/models"
+)
+
+// IsAdmin reports whether the request in ctx was made by an administrator, by the role the transport
+// authenticated it with.
+func IsAdmin(ctx context.Context) bool {
+	return ctx.Value("role") == models.RoleAdmin
+}
+
+// requireAdmin guards the operations open to administrators only. They are checked here rather than
+// in the transports, so REST, GraphQL and gRPC let the same users through.
+func requireAdmin(ctx context.Context) error {
+	if !IsAdmin(ctx) {
+		return models.CustomError{Code: http.StatusForbidden, Message: "Forbidden"}
+	}
+
+	return nil
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"sync/atomic"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/pkg/cache"
+	database "vk-test-spring/pkg/database/postgresql"
+	"vk-test-spring/pkg/logger"
+)
+
+// Cache tags. Collection tags mark results of queries over a whole table, record tags mark every
+// entry that contains the record, including nested films of an actor and nested actors of a film.
+const (
+	filmsCollectionTag  = "films"
+	actorsCollectionTag = "actors"
+)
+
+func filmTag(id uuid.UUID) string {
+	return "film:" + id.String()
+}
+
+func actorTag(id uuid.UUID) string {
+	return "actor:" + id.String()
+}
+
+func filmsTags(films []models.Film, tags ...string) []string {
+	for _, f := range films {
+		tags = append(tags, filmTag(f.ID))
+		for _, a := range f.Actors {
+			tags = append(tags, actorTag(a.ID))
+		}
+	}
+
+	return tags
+}
+
+func actorsTags(actors []models.Actor, tags ...string) []string {
+	for _, a := range actors {
+		tags = append(tags, actorTag(a.ID))
+		for _, f := range a.Films {
+			tags = append(tags, filmTag(f.ID))
+		}
+	}
+
+	return tags
+}
+
+func idsTags(ids []uuid.UUID, tag func(uuid.UUID) string, tags ...string) []string {
+	for _, id := range ids {
+		tags = append(tags, tag(id))
+	}
+
+	return tags
+}
+
+// WriteMark is when films and actors last changed by the writes of this process, as the primary
+// reports it. The cached films and actors services share one to tell replica reads that include
+// those writes from reads of a replica still behind them.
+type WriteMark struct {
+	at atomic.Int64
+}
+
+// unknownWrite marks a write whose time could not be read: no replica read is current until the next one.
+const unknownWrite = math.MaxInt64
+
+// record moves the mark to the change time, read from the primary, of a write that has just committed.
+func (m *WriteMark) record(ctx context.Context, lastModified func(ctx context.Context) (time.Time, error)) {
+	changed, err := lastModified(database.WithReadYourWrites(ctx, true))
+	if err != nil {
+		logger.FromContext(ctx).Warn().Err(err).Msg("reading the change time of a write, replica reads are not cached until the next one")
+		m.at.Store(unknownWrite)
+		return
+	}
+
+	for {
+		at := m.at.Load()
+		if at != unknownWrite && at >= changed.UnixMicro() {
+			return
+		}
+		if m.at.CompareAndSwap(at, changed.UnixMicro()) {
+			return
+		}
+	}
+}
+
+// libraryCache is the cache of the films or actors service with what it needs to check replica reads:
+// the shared WriteMark and the change time of the library as the reads of a request see it.
+type libraryCache struct {
+	cache.Cache
+	mark         *WriteMark
+	lastModified func(ctx context.Context) (time.Time, error)
+}
+
+// written drops the entries tagged with tags after a write that committed with ctx, first moving the
+// mark, so loads that start after the entries are gone check the replica against the write.
+func (c libraryCache) written(ctx context.Context, tags ...string) {
+	c.mark.record(ctx, c.lastModified)
+	c.Invalidate(tags...)
+}
+
+// cached returns the value stored under key or loads, stores and returns it. Errors are never cached,
+// nor are values whose tags were invalidated while they were loaded. A value read from the replica is
+// stored only when the replica had already replayed the last write of this process before the load;
+// otherwise it may predate that write. Requests that read from the primary skip stored values.
+func cached[T any](ctx context.Context, c libraryCache, key string, load func(ctx context.Context) (T, error), tags func(T) []string) (T, error) {
+	if !database.ReadsPrimary(ctx) {
+		if data, ok := c.Get(key); ok {
+			var v T
+			err := json.Unmarshal(data, &v)
+			if err == nil {
+				return v, nil
+			}
+
+			logger.FromContext(ctx).Warn().Err(err).Str("key", key).Msg("dropping unreadable cache entry")
+		}
+	}
+
+	generation := c.Generation()
+
+	// The replica only moves forward, so what it returns after it reached the mark includes the write.
+	current := true
+	if written := c.mark.at.Load(); written != 0 && !database.ReadsPrimary(ctx) {
+		versionCtx, replicaVersion := database.TrackReplicaReads(ctx)
+		changed, err := c.lastModified(versionCtx)
+		current = err == nil && replicaVersion() && changed.UnixMicro() >= written
+	}
+
+	ctx, replicaRead := database.TrackReplicaReads(ctx)
+	v, err := load(ctx)
+	if err != nil || replicaRead() && !current {
+		return v, err
+	}
+
+	if data, err := json.Marshal(v); err == nil {
+		c.SetSince(generation, key, data, tags(v)...)
+	}
+
+	return v, nil
+}
+
+type CachedFilmsService struct {
+	next  Films
+	cache libraryCache
+}
+
+func NewCachedFilmsService(next Films, c cache.Cache, mark *WriteMark) *CachedFilmsService {
+	return &CachedFilmsService{
+		next:  next,
+		cache: libraryCache{Cache: c, mark: mark, lastModified: next.LastModified},
+	}
+}
+
+func (s *CachedFilmsService) AddNewFilm(ctx context.Context, input FilmCreateInput) error {
+	err := s.next.AddNewFilm(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	tags := idsTags(input.Actors, actorTag, filmsCollectionTag)
+	if len(input.NewActors) > 0 {
+		tags = append(tags, actorsCollectionTag)
+	}
+
+	s.cache.written(ctx, tags...)
+	return nil
+}
+
+func (s *CachedFilmsService) EditFilm(ctx context.Context, input FilmUpdateInput) error {
+	err := s.next.EditFilm(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	tags := idsTags(input.ActorsToAdd, actorTag, filmsCollectionTag, filmTag(input.ID))
+	s.cache.written(ctx, idsTags(input.ActorsToDel, actorTag, tags...)...)
+	return nil
+}
+
+func (s *CachedFilmsService) DeleteFilm(ctx context.Context, filmId uuid.UUID) error {
+	err := s.next.DeleteFilm(ctx, filmId)
+	if err != nil {
+		return err
+	}
+
+	s.cache.written(ctx, filmsCollectionTag, filmTag(filmId))
+	return nil
+}
+
+func (s *CachedFilmsService) GetAllFilms(ctx context.Context) ([]models.Film, error) {
+	key := fmt.Sprintf("films:all:%v:%v", ctx.Value("sort"), ctx.Value("order"))
+
+	return cached(ctx, s.cache, key, func(ctx context.Context) ([]models.Film, error) {
+		return s.next.GetAllFilms(ctx)
+	}, func(films []models.Film) []string {
+		return filmsTags(films, filmsCollectionTag)
+	})
+}
+
+func (s *CachedFilmsService) GetAllFilmsByName(ctx context.Context, name string) ([]models.Film, error) {
+	return cached(ctx, s.cache, "films:name:"+name, func(ctx context.Context) ([]models.Film, error) {
+		return s.next.GetAllFilmsByName(ctx, name)
+	}, func(films []models.Film) []string {
+		return filmsTags(films, filmsCollectionTag)
+	})
+}
+
+func (s *CachedFilmsService) GetAllFilmsByActor(ctx context.Context, actorsName string) ([]models.Film, error) {
+	return cached(ctx, s.cache, "films:actor:"+actorsName, func(ctx context.Context) ([]models.Film, error) {
+		return s.next.GetAllFilmsByActor(ctx, actorsName)
+	}, func(films []models.Film) []string {
+		return filmsTags(films, filmsCollectionTag, actorsCollectionTag)
+	})
+}
+
+func (s *CachedFilmsService) GetFilmById(ctx context.Context, filmId uuid.UUID) (models.Film, error) {
+	return cached(ctx, s.cache, "films:id:"+filmId.String(), func(ctx context.Context) (models.Film, error) {
+		return s.next.GetFilmById(ctx, filmId)
+	}, func(film models.Film) []string {
+		return filmsTags([]models.Film{film})
+	})
+}
+
+// GetFilmsByIds is not cached: the sets of ids of batched lookups rarely repeat.
+func (s *CachedFilmsService) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error) {
+	return s.next.GetFilmsByIds(ctx, ids)
+}
+
+// LastModified is not cached: it has to move as soon as a write commits.
+func (s *CachedFilmsService) LastModified(ctx context.Context) (time.Time, error) {
+	return s.next.LastModified(ctx)
+}
+
+type CachedActorsService struct {
+	next  Actors
+	cache libraryCache
+}
+
+func NewCachedActorsService(next Actors, c cache.Cache, mark *WriteMark) *CachedActorsService {
+	return &CachedActorsService{
+		next:  next,
+		cache: libraryCache{Cache: c, mark: mark, lastModified: next.LastModified},
+	}
+}
+
+func (s *CachedActorsService) AddActor(ctx context.Context, input ActorCreateInput) error {
+	err := s.next.AddActor(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	s.cache.written(ctx, idsTags(input.Films, filmTag, actorsCollectionTag)...)
+	return nil
+}
+
+func (s *CachedActorsService) UpdateActor(ctx context.Context, input ActorUpdateInput) error {
+	err := s.next.UpdateActor(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	tags := idsTags(input.FilmsToAdd, filmTag, actorsCollectionTag, actorTag(input.ID))
+	s.cache.written(ctx, idsTags(input.FilmsToDel, filmTag, tags...)...)
+	return nil
+}
+
+func (s *CachedActorsService) DeleteActor(ctx context.Context, actorId uuid.UUID) error {
+	err := s.next.DeleteActor(ctx, actorId)
+	if err != nil {
+		return err
+	}
+
+	s.cache.written(ctx, actorsCollectionTag, actorTag(actorId))
+	return nil
+}
+
+func (s *CachedActorsService) GetAllActors(ctx context.Context) ([]models.Actor, error) {
+	return cached(ctx, s.cache, "actors:all", func(ctx context.Context) ([]models.Actor, error) {
+		return s.next.GetAllActors(ctx)
+	}, func(actors []models.Actor) []string {
+		return actorsTags(actors, actorsCollectionTag)
+	})
+}
+
+func (s *CachedActorsService) GetActorById(ctx context.Context, actorId uuid.UUID) (models.Actor, error) {
+	return cached(ctx, s.cache, "actors:id:"+actorId.String(), func(ctx context.Context) (models.Actor, error) {
+		return s.next.GetActorById(ctx, actorId)
+	}, func(actor models.Actor) []string {
+		return actorsTags([]models.Actor{actor})
+	})
+}
+
+func (s *CachedActorsService) GetActorByName(ctx context.Context, name string) ([]models.Actor, error) {
+	return cached(ctx, s.cache, "actors:name:"+name, func(ctx context.Context) ([]models.Actor, error) {
+		return s.next.GetActorByName(ctx, name)
+	}, func(actors []models.Actor) []string {
+		return actorsTags(actors, actorsCollectionTag)
+	})
+}
+
+// GetActorsByIds is not cached: the sets of ids of batched lookups rarely repeat.
+func (s *CachedActorsService) GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error) {
+	return s.next.GetActorsByIds(ctx, ids)
+}
+
+// LastModified is not cached: it has to move as soon as a write commits.
+func (s *CachedActorsService) LastModified(ctx context.Context) (time.Time, error) {
+	return s.next.LastModified(ctx)
+}
+
+// credentialsTag marks verified credentials, dropped together whenever a user changes.
+const credentialsTag = "credentials"
+
+// verifiedUser is what GetUserIdRole stores for credentials that were checked.
+type verifiedUser struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+// CachedUsersService keeps credentials that passed the bcrypt check for the lifetime of cache entries,
+// so requests of an authenticated client do not pay for the hash every time. Entries are keyed by an
+// HMAC of the name and password under a key generated at start, never by the password itself.
+// Wrong credentials are not stored.
+type CachedUsersService struct {
+	next  Users
+	cache cache.Cache
+	key   []byte
+}
+
+func NewCachedUsersService(next Users, c cache.Cache) *CachedUsersService {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("service: generating the credentials key: %v", err))
+	}
+
+	return &CachedUsersService{
+		next:  next,
+		cache: c,
+		key:   key,
+	}
+}
+
+func (s *CachedUsersService) CreateUser(ctx context.Context, input UserInput) error {
+	return s.next.CreateUser(ctx, input)
+}
+
+func (s *CachedUsersService) DeleteUser(ctx context.Context, userId string) error {
+	err := s.next.DeleteUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(credentialsTag)
+	return nil
+}
+
+func (s *CachedUsersService) ChangeRole(ctx context.Context, userId string, role string) error {
+	err := s.next.ChangeRole(ctx, userId, role)
+	if err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(credentialsTag)
+	return nil
+}
+
+func (s *CachedUsersService) GetUserIdRole(username string, password string) (string, string, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(username))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	key := "credentials:" + hex.EncodeToString(mac.Sum(nil))
+
+	var user verifiedUser
+	if data, ok := s.cache.Get(key); ok && json.Unmarshal(data, &user) == nil {
+		return user.ID, user.Role, nil
+	}
+
+	generation := s.cache.Generation()
+	userId, role, err := s.next.GetUserIdRole(username, password)
+	if err != nil {
+		return "", "", err
+	}
+
+	if data, err := json.Marshal(verifiedUser{ID: userId, Role: role}); err == nil {
+		s.cache.SetSince(generation, key, data, credentialsTag)
+	}
+
+	return userId, role, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/pkg/cache"
+)
+
+func TestCachedActorsService_GetActorById(t *testing.T) {
+	t.Run("Served from cache", func(t *testing.T) {
+		repo := new(MockActorRepository)
+		c := cache.NewLRU(10, time.Minute)
+		actorsService := NewCachedActorsService(&ActorsService{repo: repo}, c, new(WriteMark))
+
+		actor := models.Actor{ID: uuid.New(), Name: "Сергей", SecondName: "Бодров"}
+		repo.On("GetActorById", mock.Anything, actor.ID).Return(actor, nil)
+
+		first, err := actorsService.GetActorById(adminCtx, actor.ID)
+		assert.NoError(t, err)
+		second, err := actorsService.GetActorById(adminCtx, actor.ID)
+		assert.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		repo.AssertNumberOfCalls(t, "GetActorById", 1)
+		assert.Equal(t, cache.Stats{Hits: 1, Misses: 1, Entries: 1}, c.Stats())
+	})
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		repo := new(MockActorRepository)
+		c := cache.NewLRU(10, time.Minute)
+		actorsService := NewCachedActorsService(&ActorsService{repo: repo}, c, new(WriteMark))
+
+		actorId := uuid.New()
+		repo.On("GetActorById", mock.Anything, actorId).Return(models.Actor{}, models.CustomError{Code: 404, Message: "not found"})
+
+		actorsService.GetActorById(adminCtx, actorId)
+		actorsService.GetActorById(adminCtx, actorId)
+
+		repo.AssertNumberOfCalls(t, "GetActorById", 2)
+	})
+
+	t.Run("Invalidated by actor update", func(t *testing.T) {
+		repo := new(MockActorRepository)
+		c := cache.NewLRU(10, time.Minute)
+		actorsService := NewCachedActorsService(&ActorsService{repo: repo}, c, new(WriteMark))
+
+		actor := models.Actor{ID: uuid.New(), Name: "Sergey", SecondName: "Bodrov", Sex: "Мужчина", DateOfBirth: "1971-12-27"}
+		repo.On("GetActorById", mock.Anything, actor.ID).Return(actor, nil)
+		repo.On("Edit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		repo.On("LastModified", mock.Anything).Return(time.Now(), nil)
+
+		actorsService.GetActorById(adminCtx, actor.ID)
+		err := actorsService.UpdateActor(adminCtx, ActorUpdateInput{ID: actor.ID, ActorInfo: ActorInfo{Name: "Sergei"}})
+		assert.NoError(t, err)
+		actorsService.GetActorById(adminCtx, actor.ID)
+
+		// one call to cache the actor, one inside UpdateActor and one after invalidation
+		repo.AssertNumberOfCalls(t, "GetActorById", 3)
+	})
+
+	t.Run("Not cached when invalidated while loading", func(t *testing.T) {
+		repo := new(MockActorRepository)
+		c := cache.NewLRU(10, time.Minute)
+		actorsService := NewCachedActorsService(&ActorsService{repo: repo}, c, new(WriteMark))
+
+		actor := models.Actor{ID: uuid.New(), Name: "Сергей"}
+		repo.On("GetActorById", mock.Anything, actor.ID).Return(actor, nil).Once().Run(func(mock.Arguments) {
+			// an update commits after the actor was read, before it is stored
+			c.Invalidate(actorTag(actor.ID))
+		})
+		repo.On("GetActorById", mock.Anything, actor.ID).Return(actor, nil)
+
+		actorsService.GetActorById(adminCtx, actor.ID)
+		actorsService.GetActorById(adminCtx, actor.ID)
+		actorsService.GetActorById(adminCtx, actor.ID)
+
+		repo.AssertNumberOfCalls(t, "GetActorById", 2)
+	})
+}
+
+func TestCachedFilmsService_EditFilm(t *testing.T) {
+	t.Run("Invalidates actors linked to film", func(t *testing.T) {
+		filmsRepo := new(MockFilmRepository)
+		actorsRepo := new(MockActorRepository)
+		c := cache.NewLRU(10, time.Minute)
+		mark := new(WriteMark)
+		filmsService := NewCachedFilmsService(&FilmsService{repo: filmsRepo}, c, mark)
+		actorsService := NewCachedActorsService(&ActorsService{repo: actorsRepo}, c, mark)
+
+		linked := models.Actor{ID: uuid.New(), Name: "Sergey"}
+		unrelated := models.Actor{ID: uuid.New(), Name: "Viktor"}
+		film := models.Film{ID: uuid.New(), Name: "Брат", Description: "Фильм", Date: "1997-12-12", Rating: 8}
+
+		actorsRepo.On("GetActorById", mock.Anything, linked.ID).Return(linked, nil)
+		actorsRepo.On("GetActorById", mock.Anything, unrelated.ID).Return(unrelated, nil)
+		filmsRepo.On("GetFilmById", mock.Anything, film.ID).Return(film, nil)
+		filmsRepo.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		filmsRepo.On("LastModified", mock.Anything).Return(time.Now(), nil)
+		actorsRepo.On("LastModified", mock.Anything).Return(time.Now(), nil)
+
+		actorsService.GetActorById(adminCtx, linked.ID)
+		actorsService.GetActorById(adminCtx, unrelated.ID)
+
+		err := filmsService.EditFilm(adminCtx, FilmUpdateInput{ID: film.ID, ActorsToAdd: []uuid.UUID{linked.ID}})
+		assert.NoError(t, err)
+
+		actorsService.GetActorById(adminCtx, linked.ID)
+		actorsService.GetActorById(adminCtx, unrelated.ID)
+
+		actorsRepo.AssertNumberOfCalls(t, "GetActorById", 3)
+	})
+}
+
+func TestCachedUsersService_GetUserIdRole(t *testing.T) {
+	t.Run("Verified credentials served from cache", func(t *testing.T) {
+		repo := new(MockUsersRepository)
+		usersService := NewCachedUsersService(NewUsersService(repo), cache.NewLRU(10, time.Minute))
+
+		repo.On("GetUserIdRole", "admin", "admin").Return("42", models.RoleAdmin, nil)
+
+		for i := 0; i < 3; i++ {
+			userId, role, err := usersService.GetUserIdRole("admin", "admin")
+			assert.NoError(t, err)
+			assert.Equal(t, "42", userId)
+			assert.Equal(t, models.RoleAdmin, role)
+		}
+
+		repo.AssertNumberOfCalls(t, "GetUserIdRole", 1)
+	})
+
+	t.Run("Wrong credentials are checked every time", func(t *testing.T) {
+		repo := new(MockUsersRepository)
+		usersService := NewCachedUsersService(NewUsersService(repo), cache.NewLRU(10, time.Minute))
+
+		repo.On("GetUserIdRole", "admin", "admin").Return("42", models.RoleAdmin, nil)
+		repo.On("GetUserIdRole", "admin", "wrong").Return("", "", models.CustomError{Code: 401, Message: "invalid username or password"})
+
+		usersService.GetUserIdRole("admin", "admin")
+		for i := 0; i < 2; i++ {
+			_, _, err := usersService.GetUserIdRole("admin", "wrong")
+			assert.Error(t, err)
+		}
+
+		repo.AssertNumberOfCalls(t, "GetUserIdRole", 3)
+	})
+
+	t.Run("Invalidated by a role change", func(t *testing.T) {
+		repo := new(MockUsersRepository)
+		usersService := NewCachedUsersService(NewUsersService(repo), cache.NewLRU(10, time.Minute))
+
+		repo.On("GetUserIdRole", "admin", "admin").Return("42", models.RoleAdmin, nil)
+
+		usersService.GetUserIdRole("admin", "admin")
+		assert.NoError(t, usersService.ChangeRole(adminCtx, "42", models.RoleUser))
+		usersService.GetUserIdRole("admin", "admin")
+
+		repo.AssertNumberOfCalls(t, "GetUserIdRole", 2)
+	})
+}
@@ -8,17 +8,24 @@ import (
 	"net/http"
 	"slices"
 	"time"
+	"vk-test-spring/internal/metrics"
 	"vk-test-spring/internal/models"
 	"vk-test-spring/internal/repository"
 )
 
 type FilmsService struct {
-	repo repository.Films
+	repo   repository.Films
+	actors repository.Actors
+	tx     repository.Transactor
 }
 
-func NewFilmsService(repo repository.Films) *FilmsService {
+// NewFilmsService returns the films service. actors and tx are used to create a film together
+// with its new actors in one transaction.
+func NewFilmsService(repo repository.Films, actors repository.Actors, tx repository.Transactor) *FilmsService {
 	return &FilmsService{
-		repo: repo,
+		repo:   repo,
+		actors: actors,
+		tx:     tx,
 	}
 }
 
@@ -106,17 +113,34 @@ func (in *FilmInfo) validateRating() error {
 	}
 }
 
+// FilmCreateInput describes a new film. NewActors are created along with the film and cast in it
+// next to the existing Actors; if any of them cannot be created, neither is the film.
 type FilmCreateInput struct {
-	FilmInfo FilmInfo
-	Actors   []uuid.UUID
+	FilmInfo  FilmInfo
+	Actors    []uuid.UUID
+	NewActors []ActorCreateInput
 }
 
 func (s *FilmsService) AddNewFilm(ctx context.Context, input FilmCreateInput) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
 	err := input.FilmInfo.validate()
 	if err != nil {
 		return models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
 	}
 
+	newActors := make([]models.Actor, 0, len(input.NewActors))
+	for _, a := range input.NewActors {
+		err = a.ActorInfo.validate()
+		if err != nil {
+			return models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+
+		newActors = append(newActors, a.ActorInfo.actor())
+	}
+
 	film := models.Film{
 		Name:        input.FilmInfo.Name,
 		Description: input.FilmInfo.Description,
@@ -124,12 +148,36 @@ func (s *FilmsService) AddNewFilm(ctx context.Context, input FilmCreateInput) er
 		Rating:      input.FilmInfo.Rating,
 	}
 
-	err = s.repo.Create(ctx, film, input.Actors)
+	if len(newActors) == 0 {
+		err = s.repo.Create(ctx, film, input.Actors)
+		if err != nil {
+			return err
+		}
+
+		metrics.FilmsCreated.Inc()
+		return nil
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		actors := slices.Clone(input.Actors)
+		for _, a := range newActors {
+			id, err := s.actors.Create(ctx, a, nil)
+			if err != nil {
+				return err
+			}
+
+			actors = append(actors, id)
+		}
+
+		return s.repo.Create(ctx, film, actors)
+	})
 	if err != nil {
 		return err
 	}
 
-	return err
+	metrics.ActorsCreated.Add(float64(len(newActors)))
+	metrics.FilmsCreated.Inc()
+	return nil
 }
 
 type FilmUpdateInput struct {
@@ -140,6 +188,10 @@ type FilmUpdateInput struct {
 }
 
 func (s *FilmsService) EditFilm(ctx context.Context, input FilmUpdateInput) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
 	film := models.Film{
 		ID:          input.ID,
 		Name:        input.FilmInfo.Name,
@@ -184,6 +236,10 @@ func (s *FilmsService) EditFilm(ctx context.Context, input FilmUpdateInput) erro
 	return err
 }
 func (s *FilmsService) DeleteFilm(ctx context.Context, filmId uuid.UUID) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
 	return s.repo.Delete(ctx, filmId)
 }
 func (s *FilmsService) GetAllFilms(ctx context.Context) ([]models.Film, error) {
@@ -196,6 +252,17 @@ func (s *FilmsService) GetAllFilmsByActor(ctx context.Context, actorsName string
 
 	return s.repo.GetFilmByActor(ctx, actorsName)
 }
+func (s *FilmsService) GetFilmById(ctx context.Context, filmId uuid.UUID) (models.Film, error) {
+	return s.repo.GetFilmById(ctx, filmId)
+}
+func (s *FilmsService) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error) {
+	return s.repo.GetFilmsByIds(ctx, ids)
+}
+
+// LastModified returns when any film, actor or link between them last changed, deletes included.
+func (s *FilmsService) LastModified(ctx context.Context) (time.Time, error) {
+	return s.repo.LastModified(ctx)
+}
 
 func (s *FilmsService) mergeChanges(film models.Film, oldFilm models.Film) (models.Film, error) {
 	if film.Name == "" {
@@ -7,10 +7,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"net/http"
 	"testing"
+	"time"
 	"vk-test-spring/internal/models"
 )
 
+// adminCtx is the context of a request made by an administrator, who may change films and actors.
+var adminCtx = context.WithValue(context.Background(), "role", models.RoleAdmin)
+
 type MockFilmRepository struct {
 	mock.Mock
 }
@@ -60,6 +65,25 @@ func (m *MockFilmRepository) GetFilmById(ctx context.Context, filmId uuid.UUID)
 	return args.Get(0).(models.Film), args.Error(1)
 }
 
+func (m *MockFilmRepository) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]models.Film), args.Error(1)
+}
+
+func (m *MockFilmRepository) LastModified(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+type fakeTransactor struct {
+	calls int
+}
+
+func (f *fakeTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
 func TestFilmsService_AddNewFilm(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		repo := new(MockFilmRepository)
@@ -75,18 +99,18 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 			Actors: nil,
 		}
 
-		repo.On("Create", context.Background(), models.Film{
+		repo.On("Create", adminCtx, models.Film{
 			Name:        input.FilmInfo.Name,
 			Description: input.FilmInfo.Description,
 			Date:        input.FilmInfo.Date,
 			Rating:      input.FilmInfo.Rating,
 		}, input.Actors).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "Create", context.Background(), models.Film{
+		repo.AssertCalled(t, "Create", adminCtx, models.Film{
 			Name:        input.FilmInfo.Name,
 			Description: input.FilmInfo.Description,
 			Date:        input.FilmInfo.Date,
@@ -94,6 +118,94 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 		}, input.Actors)
 	})
 
+	t.Run("new actors in one transaction", func(t *testing.T) {
+		repo := new(MockFilmRepository)
+		actorsRepo := new(MockActorRepository)
+		tx := &fakeTransactor{}
+		filmService := NewFilmsService(repo, actorsRepo, tx)
+
+		existing := uuid.New()
+		created := uuid.New()
+		actorInfo := ActorInfo{
+			Name:        "Иван",
+			SecondName:  "Иванов",
+			Patronymic:  "Иванович",
+			Sex:         "Мужчина",
+			DateOfBirth: "2000-01-01",
+		}
+
+		input := FilmCreateInput{
+			FilmInfo: FilmInfo{
+				Name:        "Film",
+				Description: "films description",
+				Date:        "2000-01-01",
+				Rating:      5.2,
+			},
+			Actors:    []uuid.UUID{existing},
+			NewActors: []ActorCreateInput{{ActorInfo: actorInfo}},
+		}
+
+		actorsRepo.On("Create", adminCtx, actorInfo.actor(), []uuid.UUID(nil)).Return(created, nil)
+		repo.On("Create", adminCtx, mock.Anything, []uuid.UUID{existing, created}).Return(nil)
+
+		err := filmService.AddNewFilm(adminCtx, input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, tx.calls)
+		repo.AssertCalled(t, "Create", adminCtx, mock.Anything, []uuid.UUID{existing, created})
+	})
+
+	t.Run("failed new actor stops the film", func(t *testing.T) {
+		repo := new(MockFilmRepository)
+		actorsRepo := new(MockActorRepository)
+		filmService := NewFilmsService(repo, actorsRepo, &fakeTransactor{})
+
+		input := FilmCreateInput{
+			FilmInfo: FilmInfo{
+				Name:        "Film",
+				Description: "films description",
+				Date:        "2000-01-01",
+				Rating:      5.2,
+			},
+			NewActors: []ActorCreateInput{{ActorInfo: ActorInfo{
+				Name:        "Иван",
+				SecondName:  "Иванов",
+				Patronymic:  "Иванович",
+				Sex:         "Мужчина",
+				DateOfBirth: "2000-01-01",
+			}}},
+		}
+
+		actorsRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, errors.New("insert failed"))
+
+		err := filmService.AddNewFilm(adminCtx, input)
+
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Create")
+	})
+
+	t.Run("invalid new actor", func(t *testing.T) {
+		repo := new(MockFilmRepository)
+		actorsRepo := new(MockActorRepository)
+		tx := &fakeTransactor{}
+		filmService := NewFilmsService(repo, actorsRepo, tx)
+
+		input := FilmCreateInput{
+			FilmInfo: FilmInfo{
+				Name:        "Film",
+				Description: "films description",
+				Date:        "2000-01-01",
+				Rating:      5.2,
+			},
+			NewActors: []ActorCreateInput{{ActorInfo: ActorInfo{}}},
+		}
+
+		err := filmService.AddNewFilm(adminCtx, input)
+
+		assert.Equal(t, http.StatusBadRequest, err.(models.CustomError).Code)
+		assert.Equal(t, 0, tx.calls)
+	})
+
 	t.Run("empty film name", func(t *testing.T) {
 		repo := new(MockFilmRepository)
 		filmService := FilmsService{repo: repo}
@@ -110,7 +222,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "input film's name is empty")
@@ -133,7 +245,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("input film's name too long. length of name must be between 1 and 150,"+
@@ -166,7 +278,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("input film's description too long. length of name must be between 1 and 1000,"+
@@ -190,7 +302,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "empty film's description")
@@ -213,7 +325,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 
@@ -236,7 +348,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("input films's rating is negative. rating value must be in range between 0 and 10,"+
@@ -260,7 +372,7 @@ func TestFilmsService_AddNewFilm(t *testing.T) {
 
 		repo.On("Create", mock.Anything, mock.Anything).Return(nil)
 
-		err := filmService.AddNewFilm(context.Background(), input)
+		err := filmService.AddNewFilm(adminCtx, input)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, fmt.Sprintf("input films's rating is too big. rating value must be in range between 0 and 10,"+
@@ -295,26 +407,26 @@ func TestFilmsService_EditFilm(t *testing.T) {
 			Actors:      nil,
 		}
 
-		repo.On("GetFilmById", context.Background(), input.ID).Return(expectedFromGetFilmById, nil)
+		repo.On("GetFilmById", adminCtx, input.ID).Return(expectedFromGetFilmById, nil)
 
-		repo.On("Update", context.Background(), expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel).Return(nil)
+		repo.On("Update", adminCtx, expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel).Return(nil)
 
-		err := filmService.EditFilm(context.Background(), input)
+		err := filmService.EditFilm(adminCtx, input)
 
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "GetFilmById", context.Background(), input.ID)
+		repo.AssertCalled(t, "GetFilmById", adminCtx, input.ID)
 
-		repo.AssertCalled(t, "Update", context.Background(), expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel)
+		repo.AssertCalled(t, "Update", adminCtx, expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel)
 	})
 
 	t.Run("record not found", func(t *testing.T) {
 		repo := new(MockFilmRepository)
 		filmService := FilmsService{repo: repo}
 
-		repo.On("GetFilmById", context.Background(), uuid.Nil).Return(models.Film{}, errors.New("record not found"))
+		repo.On("GetFilmById", adminCtx, uuid.Nil).Return(models.Film{}, errors.New("record not found"))
 
-		err := filmService.EditFilm(context.Background(), FilmUpdateInput{ID: uuid.Nil})
+		err := filmService.EditFilm(adminCtx, FilmUpdateInput{ID: uuid.Nil})
 
 		assert.Equal(t, err.Error(), "record not found")
 
@@ -346,15 +458,15 @@ func TestFilmsService_EditFilm(t *testing.T) {
 			Actors:      nil,
 		}
 
-		repo.On("GetFilmById", context.Background(), input.ID).Return(expectedFromGetFilmById, nil)
+		repo.On("GetFilmById", adminCtx, input.ID).Return(expectedFromGetFilmById, nil)
 
-		repo.On("Update", context.Background(), expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel).Return(nil)
+		repo.On("Update", adminCtx, expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel).Return(nil)
 
-		err := filmService.EditFilm(context.Background(), input)
+		err := filmService.EditFilm(adminCtx, input)
 
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "Update", context.Background(), expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel)
+		repo.AssertCalled(t, "Update", adminCtx, expectedFromGetFilmById, input.ActorsToAdd, input.ActorsToDel)
 	})
 
 	t.Run("good parse list to del", func(t *testing.T) {
@@ -387,20 +499,20 @@ func TestFilmsService_EditFilm(t *testing.T) {
 			}},
 		}
 
-		repo.On("GetFilmById", context.Background(), input.ID).Return(expectedFromGetFilmById, nil)
+		repo.On("GetFilmById", adminCtx, input.ID).Return(expectedFromGetFilmById, nil)
 
 		expectedEdited := expectedFromGetFilmById
 		expectedEdited.Actors = nil
 
-		repo.On("Update", context.Background(), expectedEdited, input.ActorsToAdd, input.ActorsToDel).Return(nil)
+		repo.On("Update", adminCtx, expectedEdited, input.ActorsToAdd, input.ActorsToDel).Return(nil)
 
-		err := filmService.EditFilm(context.Background(), input)
+		err := filmService.EditFilm(adminCtx, input)
 
 		assert.NoError(t, err)
 
-		repo.AssertCalled(t, "GetFilmById", context.Background(), input.ID)
+		repo.AssertCalled(t, "GetFilmById", adminCtx, input.ID)
 
-		repo.AssertCalled(t, "Update", context.Background(), expectedEdited, input.ActorsToAdd, input.ActorsToDel)
+		repo.AssertCalled(t, "Update", adminCtx, expectedEdited, input.ActorsToAdd, input.ActorsToDel)
 	})
 
 	t.Run("Bad parse list to add", func(t *testing.T) {
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository"
+)
+
+var builtinLists = map[string]string{
+	models.ListKindWatchlist:  "Watchlist",
+	models.ListKindFavourites: "Favourites",
+	models.ListKindSeen:       "Seen",
+}
+
+type ListsService struct {
+	repo repository.Lists
+}
+
+func NewListsService(repo repository.Lists) *ListsService {
+	return &ListsService{
+		repo: repo,
+	}
+}
+
+type ListInput struct {
+	Name string
+}
+
+func (in *ListInput) validate() error {
+	switch {
+	case len(in.Name) > 150:
+		return errors.New(fmt.Sprintf("input list's name too long. length of name must be between 1 and 150,"+
+			" but got: %v", len(in.Name)))
+	case len(in.Name) == 0:
+		return errors.New("input list's name is empty")
+	default:
+		return nil
+	}
+}
+
+type ListUpdateInput struct {
+	Name   string
+	Public *bool
+}
+
+type ListFilmInput struct {
+	FilmID   uuid.UUID
+	Position int
+	Note     string
+}
+
+// ListFilmUpdateInput changes the fields that are set: Position when it is above 0, Note when it is not nil.
+type ListFilmUpdateInput struct {
+	FilmID   uuid.UUID
+	Position int
+	Note     *string
+}
+
+func (in *ListFilmUpdateInput) validate() error {
+	note := ListFilmInput{Position: in.Position}
+	if in.Note != nil {
+		note.Note = *in.Note
+	}
+
+	return note.validate()
+}
+
+func (in *ListFilmInput) validate() error {
+	switch {
+	case len(in.Note) > 1000:
+		return errors.New(fmt.Sprintf("input note too long. length of note must be between 0 and 1000,"+
+			" but got: %v", len(in.Note)))
+	case in.Position < 0:
+		return errors.New(fmt.Sprintf("input position is negative. position must be greater than 0,"+
+			" but got: %v", in.Position))
+	default:
+		return nil
+	}
+}
+
+func (s *ListsService) GetUserLists(ctx context.Context, userId string) ([]models.UserList, error) {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := s.repo.GetUserLists(ctx, id)
+	if err != nil || s.hasBuiltin(lists) {
+		return lists, err
+	}
+
+	// the built-in lists are created on the first read that misses them, later reads don't write
+	err = s.repo.CreateBuiltin(ctx, id, builtinLists)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserLists(ctx, id)
+}
+
+func (s *ListsService) GetList(ctx context.Context, userId string, listRef string) (models.UserList, error) {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	return s.getList(ctx, id, listRef)
+}
+
+func (s *ListsService) CreateList(ctx context.Context, userId string, input ListInput) (models.UserList, error) {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	err = input.validate()
+	if err != nil {
+		return models.UserList{}, models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	listId, err := s.repo.Create(ctx, models.UserList{
+		UserID: id,
+		Name:   input.Name,
+		Kind:   models.ListKindCustom,
+	})
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	return s.repo.GetListById(ctx, id, listId)
+}
+
+func (s *ListsService) UpdateList(ctx context.Context, userId string, listRef string, input ListUpdateInput) (models.UserList, error) {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	list, err := s.getList(ctx, id, listRef)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	if input.Name != "" && input.Name != list.Name {
+		if list.Kind != models.ListKindCustom {
+			return models.UserList{}, models.CustomError{Code: http.StatusBadRequest,
+				Message: fmt.Sprintf("built-in list can't be renamed: %v", list.Kind)}
+		}
+
+		nameValidation := ListInput{Name: input.Name}
+		err = nameValidation.validate()
+		if err != nil {
+			return models.UserList{}, models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+
+		list.Name = input.Name
+	}
+
+	if input.Public != nil {
+		switch {
+		case *input.Public && list.ShareToken == "":
+			list.ShareToken, err = s.newShareToken()
+			if err != nil {
+				return models.UserList{}, err
+			}
+		case !*input.Public:
+			list.ShareToken = ""
+		}
+	}
+
+	err = s.repo.Update(ctx, list)
+	if err != nil {
+		return models.UserList{}, err
+	}
+
+	return s.repo.GetListById(ctx, id, list.ID)
+}
+
+func (s *ListsService) DeleteList(ctx context.Context, userId string, listRef string) error {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return err
+	}
+
+	list, err := s.getList(ctx, id, listRef)
+	if err != nil {
+		return err
+	}
+
+	if list.Kind != models.ListKindCustom {
+		return models.CustomError{Code: http.StatusBadRequest, Message: fmt.Sprintf("built-in list can't be deleted: %v", list.Kind)}
+	}
+
+	return s.repo.Delete(ctx, id, list.ID)
+}
+
+func (s *ListsService) AddFilm(ctx context.Context, userId string, listRef string, input ListFilmInput) error {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return err
+	}
+
+	err = input.validate()
+	if err != nil {
+		return models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	list, err := s.getList(ctx, id, listRef)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.AddFilm(ctx, list.ID, models.ListFilm{
+		ID:       input.FilmID,
+		Position: input.Position,
+		Note:     input.Note,
+	})
+}
+
+func (s *ListsService) UpdateFilm(ctx context.Context, userId string, listRef string, input ListFilmUpdateInput) error {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return err
+	}
+
+	err = input.validate()
+	if err != nil {
+		return models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	list, err := s.getList(ctx, id, listRef)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateFilm(ctx, list.ID, input.FilmID, input.Position, input.Note)
+}
+
+func (s *ListsService) RemoveFilm(ctx context.Context, userId string, listRef string, filmId uuid.UUID) error {
+	id, err := s.parseUserId(userId)
+	if err != nil {
+		return err
+	}
+
+	list, err := s.getList(ctx, id, listRef)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RemoveFilm(ctx, list.ID, filmId)
+}
+
+func (s *ListsService) GetSharedList(ctx context.Context, token string) (models.UserList, error) {
+	if token == "" {
+		return models.UserList{}, models.CustomError{Code: http.StatusNotFound, Message: "not found shared list"}
+	}
+
+	return s.repo.GetListByShareToken(ctx, token)
+}
+
+// getList resolves listRef, which is either a list id or the kind of a built-in list.
+func (s *ListsService) getList(ctx context.Context, userId uuid.UUID, listRef string) (models.UserList, error) {
+	if _, ok := builtinLists[listRef]; ok {
+		list, err := s.repo.GetListByKind(ctx, userId, listRef)
+
+		var e models.CustomError
+		if !errors.As(err, &e) || e.Code != http.StatusNotFound {
+			return list, err
+		}
+
+		err = s.repo.CreateBuiltin(ctx, userId, builtinLists)
+		if err != nil {
+			return models.UserList{}, err
+		}
+
+		return s.repo.GetListByKind(ctx, userId, listRef)
+	}
+
+	listId, err := uuid.Parse(listRef)
+	if err != nil {
+		return models.UserList{}, models.CustomError{Code: http.StatusBadRequest,
+			Message: fmt.Sprintf("invalid list reference. must be list id or one of watchlist, favourites, seen, but has: %v", listRef)}
+	}
+
+	return s.repo.GetListById(ctx, userId, listId)
+}
+
+// hasBuiltin reports whether lists hold every built-in list.
+func (s *ListsService) hasBuiltin(lists []models.UserList) bool {
+	kinds := make(map[string]bool)
+	for _, l := range lists {
+		if _, ok := builtinLists[l.Kind]; ok {
+			kinds[l.Kind] = true
+		}
+	}
+
+	return len(kinds) == len(builtinLists)
+}
+
+func (s *ListsService) parseUserId(userId string) (uuid.UUID, error) {
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		return uuid.UUID{}, models.CustomError{Code: http.StatusUnauthorized, Message: "unknown user"}
+	}
+
+	return id, nil
+}
+
+func (s *ListsService) newShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
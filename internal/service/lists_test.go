@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strings"
+	"testing"
+	"vk-test-spring/internal/models"
+)
+
+type MockListsRepository struct {
+	mock.Mock
+}
+
+func (m *MockListsRepository) Create(ctx context.Context, list models.UserList) (uuid.UUID, error) {
+	args := m.Called(ctx, list)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockListsRepository) CreateBuiltin(ctx context.Context, userId uuid.UUID, names map[string]string) error {
+	args := m.Called(ctx, userId, names)
+	return args.Error(0)
+}
+
+func (m *MockListsRepository) Update(ctx context.Context, list models.UserList) error {
+	args := m.Called(ctx, list)
+	return args.Error(0)
+}
+
+func (m *MockListsRepository) Delete(ctx context.Context, userId uuid.UUID, listId uuid.UUID) error {
+	args := m.Called(ctx, userId, listId)
+	return args.Error(0)
+}
+
+func (m *MockListsRepository) GetUserLists(ctx context.Context, userId uuid.UUID) ([]models.UserList, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).([]models.UserList), args.Error(1)
+}
+
+func (m *MockListsRepository) GetListById(ctx context.Context, userId uuid.UUID, listId uuid.UUID) (models.UserList, error) {
+	args := m.Called(ctx, userId, listId)
+	return args.Get(0).(models.UserList), args.Error(1)
+}
+
+func (m *MockListsRepository) GetListByKind(ctx context.Context, userId uuid.UUID, kind string) (models.UserList, error) {
+	args := m.Called(ctx, userId, kind)
+	return args.Get(0).(models.UserList), args.Error(1)
+}
+
+func (m *MockListsRepository) GetListByShareToken(ctx context.Context, token string) (models.UserList, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(models.UserList), args.Error(1)
+}
+
+func (m *MockListsRepository) AddFilm(ctx context.Context, listId uuid.UUID, film models.ListFilm) error {
+	args := m.Called(ctx, listId, film)
+	return args.Error(0)
+}
+
+func (m *MockListsRepository) UpdateFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID, position int, note *string) error {
+	args := m.Called(ctx, listId, filmId, position, note)
+	return args.Error(0)
+}
+
+func (m *MockListsRepository) RemoveFilm(ctx context.Context, listId uuid.UUID, filmId uuid.UUID) error {
+	args := m.Called(ctx, listId, filmId)
+	return args.Error(0)
+}
+
+func TestListsService_GetUserLists(t *testing.T) {
+	t.Run("Reads only once the built-in lists exist", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		lists := []models.UserList{
+			{ID: uuid.New(), UserID: userId, Name: "Watchlist", Kind: models.ListKindWatchlist},
+			{ID: uuid.New(), UserID: userId, Name: "Favourites", Kind: models.ListKindFavourites},
+			{ID: uuid.New(), UserID: userId, Name: "Seen", Kind: models.ListKindSeen},
+			{ID: uuid.New(), UserID: userId, Name: "Later", Kind: models.ListKindCustom},
+		}
+
+		repo.On("GetUserLists", context.Background(), userId).Return(lists, nil)
+
+		result, err := listsService.GetUserLists(context.Background(), userId.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, lists, result)
+		repo.AssertNotCalled(t, "CreateBuiltin", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Creates built-in lists on the first read", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		lists := []models.UserList{{ID: uuid.New(), UserID: userId, Name: "Watchlist", Kind: models.ListKindWatchlist}}
+
+		repo.On("GetUserLists", context.Background(), userId).Return([]models.UserList{}, nil).Once()
+		repo.On("CreateBuiltin", context.Background(), userId, builtinLists).Return(nil)
+		repo.On("GetUserLists", context.Background(), userId).Return(lists, nil).Once()
+
+		result, err := listsService.GetUserLists(context.Background(), userId.String())
+
+		assert.NoError(t, err)
+		assert.Equal(t, lists, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		_, err := listsService.GetUserLists(context.Background(), "")
+
+		assert.Equal(t, models.CustomError{Code: http.StatusUnauthorized, Message: "unknown user"}, err)
+		repo.AssertNotCalled(t, "GetUserLists")
+	})
+}
+
+func TestListsService_CreateList(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		listId := uuid.New()
+		list := models.UserList{ID: listId, UserID: userId, Name: "Weekend", Kind: models.ListKindCustom}
+
+		repo.On("Create", context.Background(), models.UserList{UserID: userId, Name: "Weekend", Kind: models.ListKindCustom}).
+			Return(listId, nil)
+		repo.On("GetListById", context.Background(), userId, listId).Return(list, nil)
+
+		result, err := listsService.CreateList(context.Background(), userId.String(), ListInput{Name: "Weekend"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, list, result)
+	})
+
+	t.Run("Empty Name", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		_, err := listsService.CreateList(context.Background(), uuid.New().String(), ListInput{})
+
+		assert.EqualError(t, err, "input list's name is empty")
+		repo.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestListsService_UpdateList(t *testing.T) {
+	t.Run("Share list", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		list := models.UserList{ID: uuid.New(), UserID: userId, Name: "Weekend", Kind: models.ListKindCustom}
+		public := true
+
+		repo.On("GetListById", context.Background(), userId, list.ID).Return(list, nil)
+		repo.On("Update", context.Background(), mock.MatchedBy(func(l models.UserList) bool {
+			return l.ID == list.ID && len(l.ShareToken) == 64
+		})).Return(nil)
+
+		_, err := listsService.UpdateList(context.Background(), userId.String(), list.ID.String(), ListUpdateInput{Public: &public})
+
+		assert.NoError(t, err)
+		repo.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("Rename built-in list", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		list := models.UserList{ID: uuid.New(), UserID: userId, Name: "Seen", Kind: models.ListKindSeen}
+
+		repo.On("GetListByKind", context.Background(), userId, models.ListKindSeen).Return(list, nil)
+
+		_, err := listsService.UpdateList(context.Background(), userId.String(), models.ListKindSeen, ListUpdateInput{Name: "Watched"})
+
+		assert.EqualError(t, err, "built-in list can't be renamed: seen")
+		repo.AssertNotCalled(t, "Update")
+	})
+}
+
+func TestListsService_DeleteList(t *testing.T) {
+	t.Run("Built-in list", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		list := models.UserList{ID: uuid.New(), UserID: userId, Name: "Watchlist", Kind: models.ListKindWatchlist}
+
+		repo.On("GetListByKind", context.Background(), userId, models.ListKindWatchlist).Return(list, nil)
+
+		err := listsService.DeleteList(context.Background(), userId.String(), models.ListKindWatchlist)
+
+		assert.EqualError(t, err, "built-in list can't be deleted: watchlist")
+		repo.AssertNotCalled(t, "Delete")
+	})
+
+	t.Run("Invalid reference", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		err := listsService.DeleteList(context.Background(), uuid.New().String(), "later")
+
+		assert.EqualError(t, err, "invalid list reference. must be list id or one of watchlist, favourites, seen, but has: later")
+	})
+}
+
+func TestListsService_AddFilm(t *testing.T) {
+	t.Run("Adds to the referenced list only", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		filmId := uuid.New()
+		seen := models.UserList{ID: uuid.New(), UserID: userId, Kind: models.ListKindSeen}
+
+		repo.On("GetListByKind", context.Background(), userId, models.ListKindSeen).Return(seen, nil)
+		repo.On("AddFilm", context.Background(), seen.ID, models.ListFilm{ID: filmId, Note: "great"}).Return(nil)
+
+		err := listsService.AddFilm(context.Background(), userId.String(), models.ListKindSeen,
+			ListFilmInput{FilmID: filmId, Note: "great"})
+
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "RemoveFilm", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Creates the built-in list it misses", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		filmId := uuid.New()
+		watchlist := models.UserList{ID: uuid.New(), UserID: userId, Kind: models.ListKindWatchlist}
+		notFound := models.CustomError{Code: http.StatusNotFound, Message: "not found list"}
+
+		repo.On("GetListByKind", context.Background(), userId, models.ListKindWatchlist).Return(models.UserList{}, notFound).Once()
+		repo.On("CreateBuiltin", context.Background(), userId, builtinLists).Return(nil)
+		repo.On("GetListByKind", context.Background(), userId, models.ListKindWatchlist).Return(watchlist, nil).Once()
+		repo.On("AddFilm", context.Background(), watchlist.ID, models.ListFilm{ID: filmId}).Return(nil)
+
+		err := listsService.AddFilm(context.Background(), userId.String(), models.ListKindWatchlist, ListFilmInput{FilmID: filmId})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Negative position", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		err := listsService.AddFilm(context.Background(), uuid.New().String(), uuid.New().String(),
+			ListFilmInput{FilmID: uuid.New(), Position: -1})
+
+		assert.EqualError(t, err, "input position is negative. position must be greater than 0, but got: -1")
+		repo.AssertNotCalled(t, "AddFilm")
+	})
+}
+
+func TestListsService_UpdateFilm(t *testing.T) {
+	t.Run("Moving keeps the note", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		userId := uuid.New()
+		filmId := uuid.New()
+		watchlist := models.UserList{ID: uuid.New(), UserID: userId, Kind: models.ListKindWatchlist}
+
+		repo.On("GetListByKind", context.Background(), userId, models.ListKindWatchlist).Return(watchlist, nil)
+		repo.On("UpdateFilm", context.Background(), watchlist.ID, filmId, 2, (*string)(nil)).Return(nil)
+
+		err := listsService.UpdateFilm(context.Background(), userId.String(), models.ListKindWatchlist,
+			ListFilmUpdateInput{FilmID: filmId, Position: 2})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Note too long", func(t *testing.T) {
+		repo := new(MockListsRepository)
+		listsService := ListsService{repo: repo}
+
+		note := strings.Repeat("a", 1001)
+		err := listsService.UpdateFilm(context.Background(), uuid.New().String(), models.ListKindSeen,
+			ListFilmUpdateInput{FilmID: uuid.New(), Note: &note})
+
+		assert.EqualError(t, err, "input note too long. length of note must be between 0 and 1000, but got: 1001")
+		repo.AssertNotCalled(t, "UpdateFilm")
+	})
+}
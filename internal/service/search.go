@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchQueryLen  = 200
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{
+		repo: repo,
+	}
+}
+
+type SearchInput struct {
+	Query string
+	Page  int
+	Limit int
+}
+
+func (in *SearchInput) validate() error {
+	in.Query = strings.TrimSpace(in.Query)
+
+	switch {
+	case in.Query == "":
+		return errors.New("search query is empty")
+	case utf8.RuneCountInString(in.Query) > maxSearchQueryLen:
+		return errors.New(fmt.Sprintf("search query too long. length of query must be between 1 and %v,"+
+			" but got: %v", maxSearchQueryLen, utf8.RuneCountInString(in.Query)))
+	case in.Page < 0:
+		return errors.New(fmt.Sprintf("page is negative. page must be greater than 0, but got: %v", in.Page))
+	case in.Limit < 0 || in.Limit > maxSearchLimit:
+		return errors.New(fmt.Sprintf("limit not in range. limit must be between 1 and %v, but got: %v",
+			maxSearchLimit, in.Limit))
+	}
+
+	if in.Page == 0 {
+		in.Page = 1
+	}
+
+	if in.Limit == 0 {
+		in.Limit = defaultSearchLimit
+	}
+
+	return nil
+}
+
+func (s *SearchService) Search(ctx context.Context, input SearchInput) (models.SearchPage, error) {
+	err := input.validate()
+	if err != nil {
+		return models.SearchPage{}, models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	items, total, err := s.repo.Search(ctx, input.Query, input.Limit, (input.Page-1)*input.Limit)
+	if err != nil {
+		return models.SearchPage{}, err
+	}
+
+	return models.SearchPage{
+		Items: items,
+		Total: total,
+		Page:  input.Page,
+		Limit: input.Limit,
+	}, nil
+}
+
+const (
+	SuggestTypeFilms  = "films"
+	SuggestTypeActors = "actors"
+
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 20
+	minSuggestQueryLen  = 2
+)
+
+type SuggestInput struct {
+	Query string
+	Type  string
+	Limit int
+}
+
+func (in *SuggestInput) validate() error {
+	in.Query = strings.TrimSpace(in.Query)
+
+	switch {
+	case in.Type != SuggestTypeFilms && in.Type != SuggestTypeActors:
+		return errors.New(fmt.Sprintf("invalid suggest type. type must be equal to '%v' or '%v', but has: %v",
+			SuggestTypeFilms, SuggestTypeActors, in.Type))
+	case utf8.RuneCountInString(in.Query) > maxSearchQueryLen:
+		return errors.New(fmt.Sprintf("suggest query too long. length of query must be between %v and %v,"+
+			" but got: %v", minSuggestQueryLen, maxSearchQueryLen, utf8.RuneCountInString(in.Query)))
+	case in.Limit < 0 || in.Limit > maxSuggestLimit:
+		return errors.New(fmt.Sprintf("limit not in range. limit must be between 1 and %v, but got: %v",
+			maxSuggestLimit, in.Limit))
+	}
+
+	if in.Limit == 0 {
+		in.Limit = defaultSuggestLimit
+	}
+
+	return nil
+}
+
+func (s *SearchService) Suggest(ctx context.Context, input SuggestInput) ([]models.Suggestion, error) {
+	err := input.validate()
+	if err != nil {
+		return nil, models.CustomError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	// too short prefixes match most of the catalogue, so they are not worth a query
+	if utf8.RuneCountInString(input.Query) < minSuggestQueryLen {
+		return []models.Suggestion{}, nil
+	}
+
+	if input.Type == SuggestTypeActors {
+		return s.repo.SuggestActors(ctx, input.Query, input.Limit)
+	}
+
+	return s.repo.SuggestFilms(ctx, input.Query, input.Limit)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"vk-test-spring/internal/models"
+)
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, query string, limit int, offset int) ([]models.SearchResult, int, error) {
+	args := m.Called(ctx, query, limit, offset)
+	return args.Get(0).([]models.SearchResult), args.Int(1), args.Error(2)
+}
+
+func (m *MockSearchRepository) SuggestFilms(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]models.Suggestion), args.Error(1)
+}
+
+func (m *MockSearchRepository) SuggestActors(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]models.Suggestion), args.Error(1)
+}
+
+func TestSearchService_Search(t *testing.T) {
+	t.Run("Success with defaults", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		items := []models.SearchResult{{Type: models.SearchTypeFilm, ID: uuid.New(), Title: "Брат", Rank: 0.6}}
+
+		repo.On("Search", context.Background(), "брат", defaultSearchLimit, 0).Return(items, 1, nil)
+
+		page, err := searchService.Search(context.Background(), SearchInput{Query: "  брат "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.SearchPage{Items: items, Total: 1, Page: 1, Limit: defaultSearchLimit}, page)
+	})
+
+	t.Run("Offset from page", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		repo.On("Search", context.Background(), "war", 10, 20).Return([]models.SearchResult{}, 25, nil)
+
+		page, err := searchService.Search(context.Background(), SearchInput{Query: "war", Page: 3, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 25, page.Total)
+		repo.AssertCalled(t, "Search", context.Background(), "war", 10, 20)
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		_, err := searchService.Search(context.Background(), SearchInput{Query: " "})
+
+		assert.EqualError(t, err, "search query is empty")
+		repo.AssertNotCalled(t, "Search")
+	})
+
+	t.Run("Too long query", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		_, err := searchService.Search(context.Background(), SearchInput{Query: strings.Repeat("я", 201)})
+
+		assert.EqualError(t, err, "search query too long. length of query must be between 1 and 200, but got: 201")
+		repo.AssertNotCalled(t, "Search")
+	})
+
+	t.Run("Limit not in range", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		_, err := searchService.Search(context.Background(), SearchInput{Query: "war", Limit: 101})
+
+		assert.EqualError(t, err, "limit not in range. limit must be between 1 and 100, but got: 101")
+		repo.AssertNotCalled(t, "Search")
+	})
+}
+
+func TestSearchService_Suggest(t *testing.T) {
+	t.Run("Films", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		suggestions := []models.Suggestion{{ID: uuid.New(), Name: "Брат", Year: 1997}}
+
+		repo.On("SuggestFilms", context.Background(), "бр", defaultSuggestLimit).Return(suggestions, nil)
+
+		result, err := searchService.Suggest(context.Background(), SuggestInput{Query: "бр", Type: SuggestTypeFilms})
+
+		assert.NoError(t, err)
+		assert.Equal(t, suggestions, result)
+		repo.AssertNotCalled(t, "SuggestActors")
+	})
+
+	t.Run("Actors", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		repo.On("SuggestActors", context.Background(), "bod", 5).Return([]models.Suggestion{}, nil)
+
+		_, err := searchService.Suggest(context.Background(), SuggestInput{Query: "bod", Type: SuggestTypeActors, Limit: 5})
+
+		assert.NoError(t, err)
+		repo.AssertCalled(t, "SuggestActors", context.Background(), "bod", 5)
+	})
+
+	t.Run("Too short query", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		result, err := searchService.Suggest(context.Background(), SuggestInput{Query: "б", Type: SuggestTypeFilms})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		repo.AssertNotCalled(t, "SuggestFilms")
+	})
+
+	t.Run("Invalid type", func(t *testing.T) {
+		repo := new(MockSearchRepository)
+		searchService := SearchService{repo: repo}
+
+		_, err := searchService.Suggest(context.Background(), SuggestInput{Query: "бр", Type: "users"})
+
+		assert.EqualError(t, err, "invalid suggest type. type must be equal to 'films' or 'actors', but has: users")
+	})
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 	"vk-test-spring/internal/models"
 	"vk-test-spring/internal/repository"
+	"vk-test-spring/pkg/cache"
 )
 
 type Films interface {
@@ -14,6 +16,9 @@ type Films interface {
 	GetAllFilms(ctx context.Context) ([]models.Film, error)
 	GetAllFilmsByName(ctx context.Context, name string) ([]models.Film, error)
 	GetAllFilmsByActor(ctx context.Context, actorsName string) ([]models.Film, error)
+	GetFilmById(ctx context.Context, filmId uuid.UUID) (models.Film, error)
+	GetFilmsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Film, error)
+	LastModified(ctx context.Context) (time.Time, error)
 }
 
 type Actors interface {
@@ -23,6 +28,8 @@ type Actors interface {
 	GetAllActors(ctx context.Context) ([]models.Actor, error)
 	GetActorById(ctx context.Context, actorId uuid.UUID) (models.Actor, error)
 	GetActorByName(ctx context.Context, name string) ([]models.Actor, error)
+	GetActorsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Actor, error)
+	LastModified(ctx context.Context) (time.Time, error)
 }
 
 type Users interface {
@@ -32,16 +39,53 @@ type Users interface {
 	GetUserIdRole(username string, password string) (string, string, error)
 }
 
+type Lists interface {
+	GetUserLists(ctx context.Context, userId string) ([]models.UserList, error)
+	GetList(ctx context.Context, userId string, listRef string) (models.UserList, error)
+	CreateList(ctx context.Context, userId string, input ListInput) (models.UserList, error)
+	UpdateList(ctx context.Context, userId string, listRef string, input ListUpdateInput) (models.UserList, error)
+	DeleteList(ctx context.Context, userId string, listRef string) error
+	AddFilm(ctx context.Context, userId string, listRef string, input ListFilmInput) error
+	UpdateFilm(ctx context.Context, userId string, listRef string, input ListFilmUpdateInput) error
+	RemoveFilm(ctx context.Context, userId string, listRef string, filmId uuid.UUID) error
+	GetSharedList(ctx context.Context, token string) (models.UserList, error)
+}
+
+type Search interface {
+	Search(ctx context.Context, input SearchInput) (models.SearchPage, error)
+	Suggest(ctx context.Context, input SuggestInput) ([]models.Suggestion, error)
+}
+
 type Services struct {
 	Films  Films
 	Actors Actors
 	Users  Users
+	Lists  Lists
+	Search Search
 }
 
-func NewServices(repos *repository.Repositories) *Services {
-	return &Services{
-		Films:  NewFilmsService(repos.Films),
+// NewServices builds the services over repos. When c is not nil, films and actors reads are served through it,
+// and so are the checks of credentials users have already authenticated with.
+// Tracing wraps the cache, so cache hits still show up as short service spans.
+func NewServices(repos *repository.Repositories, c cache.Cache) *Services {
+	services := &Services{
+		Films:  NewFilmsService(repos.Films, repos.Actors, repos.Tx),
 		Actors: NewActorsService(repos.Actors),
 		Users:  NewUsersService(repos.Users),
+		Lists:  NewListsService(repos.Lists),
+		Search: NewSearchService(repos.Search),
 	}
+
+	if c != nil {
+		mark := new(WriteMark)
+		services.Films = NewCachedFilmsService(services.Films, c, mark)
+		services.Actors = NewCachedActorsService(services.Actors, c, mark)
+		services.Users = NewCachedUsersService(services.Users, c)
+	}
+
+	services.Films = NewTracedFilmsService(services.Films)
+	services.Actors = NewTracedActorsService(services.Actors)
+	services.Search = NewTracedSearchService(services.Search)
+
+	return services
 }
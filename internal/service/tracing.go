@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/pkg/tracing"
+)
+
+type TracedFilmsService struct {
+	next Films
+}
+
+func NewTracedFilmsService(next Films) *TracedFilmsService {
+	return &TracedFilmsService{
+		next: next,
+	}
+}
+
+func (s *TracedFilmsService) AddNewFilm(ctx context.Context, input FilmCreateInput) (err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.AddNewFilm")
+	defer tracing.End(span, &err)
+
+	return s.next.AddNewFilm(ctx, input)
+}
+
+func (s *TracedFilmsService) EditFilm(ctx context.Context, input FilmUpdateInput) (err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.EditFilm")
+	defer tracing.End(span, &err)
+
+	return s.next.EditFilm(ctx, input)
+}
+
+func (s *TracedFilmsService) DeleteFilm(ctx context.Context, filmId uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.DeleteFilm")
+	defer tracing.End(span, &err)
+
+	return s.next.DeleteFilm(ctx, filmId)
+}
+
+func (s *TracedFilmsService) GetAllFilms(ctx context.Context) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.GetAllFilms")
+	defer tracing.End(span, &err)
+
+	return s.next.GetAllFilms(ctx)
+}
+
+func (s *TracedFilmsService) GetAllFilmsByName(ctx context.Context, name string) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.GetAllFilmsByName")
+	defer tracing.End(span, &err)
+
+	return s.next.GetAllFilmsByName(ctx, name)
+}
+
+func (s *TracedFilmsService) GetAllFilmsByActor(ctx context.Context, actorsName string) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.GetAllFilmsByActor")
+	defer tracing.End(span, &err)
+
+	return s.next.GetAllFilmsByActor(ctx, actorsName)
+}
+
+func (s *TracedFilmsService) GetFilmById(ctx context.Context, filmId uuid.UUID) (film models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.GetFilmById")
+	defer tracing.End(span, &err)
+
+	return s.next.GetFilmById(ctx, filmId)
+}
+
+func (s *TracedFilmsService) GetFilmsByIds(ctx context.Context, ids []uuid.UUID) (films []models.Film, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.GetFilmsByIds")
+	defer tracing.End(span, &err)
+
+	return s.next.GetFilmsByIds(ctx, ids)
+}
+
+func (s *TracedFilmsService) LastModified(ctx context.Context) (changed time.Time, err error) {
+	ctx, span := tracing.Start(ctx, "FilmsService.LastModified")
+	defer tracing.End(span, &err)
+
+	return s.next.LastModified(ctx)
+}
+
+type TracedActorsService struct {
+	next Actors
+}
+
+func NewTracedActorsService(next Actors) *TracedActorsService {
+	return &TracedActorsService{
+		next: next,
+	}
+}
+
+func (s *TracedActorsService) AddActor(ctx context.Context, input ActorCreateInput) (err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.AddActor")
+	defer tracing.End(span, &err)
+
+	return s.next.AddActor(ctx, input)
+}
+
+func (s *TracedActorsService) UpdateActor(ctx context.Context, input ActorUpdateInput) (err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.UpdateActor")
+	defer tracing.End(span, &err)
+
+	return s.next.UpdateActor(ctx, input)
+}
+
+func (s *TracedActorsService) DeleteActor(ctx context.Context, actorId uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.DeleteActor")
+	defer tracing.End(span, &err)
+
+	return s.next.DeleteActor(ctx, actorId)
+}
+
+func (s *TracedActorsService) GetAllActors(ctx context.Context) (actors []models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.GetAllActors")
+	defer tracing.End(span, &err)
+
+	return s.next.GetAllActors(ctx)
+}
+
+func (s *TracedActorsService) GetActorById(ctx context.Context, actorId uuid.UUID) (actor models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.GetActorById")
+	defer tracing.End(span, &err)
+
+	return s.next.GetActorById(ctx, actorId)
+}
+
+func (s *TracedActorsService) GetActorByName(ctx context.Context, name string) (actors []models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.GetActorByName")
+	defer tracing.End(span, &err)
+
+	return s.next.GetActorByName(ctx, name)
+}
+
+func (s *TracedActorsService) GetActorsByIds(ctx context.Context, ids []uuid.UUID) (actors []models.Actor, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.GetActorsByIds")
+	defer tracing.End(span, &err)
+
+	return s.next.GetActorsByIds(ctx, ids)
+}
+
+func (s *TracedActorsService) LastModified(ctx context.Context) (changed time.Time, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsService.LastModified")
+	defer tracing.End(span, &err)
+
+	return s.next.LastModified(ctx)
+}
+
+type TracedSearchService struct {
+	next Search
+}
+
+func NewTracedSearchService(next Search) *TracedSearchService {
+	return &TracedSearchService{
+		next: next,
+	}
+}
+
+func (s *TracedSearchService) Search(ctx context.Context, input SearchInput) (page models.SearchPage, err error) {
+	ctx, span := tracing.Start(ctx, "SearchService.Search")
+	defer tracing.End(span, &err)
+
+	return s.next.Search(ctx, input)
+}
+
+func (s *TracedSearchService) Suggest(ctx context.Context, input SuggestInput) (suggestions []models.Suggestion, err error) {
+	ctx, span := tracing.Start(ctx, "SearchService.Suggest")
+	defer tracing.End(span, &err)
+
+	return s.next.Suggest(ctx, input)
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"net/http"
+	"vk-test-spring/internal/models"
 	"vk-test-spring/internal/repository"
 )
 
@@ -32,9 +34,12 @@ func (s *UsersService) ChangeRole(ctx context.Context, userId string, role strin
 
 func (s *UsersService) GetUserIdRole(username string, password string) (string, string, error) {
 	userId, role, err := s.repo.GetUserIdRole(username, password)
-	if userId == "" || role == "" || err != nil {
+	if err != nil {
 		return "", "", err
 	}
+	if userId == "" || role == "" {
+		return "", "", models.CustomError{Code: http.StatusUnauthorized, Message: "invalid username or password"}
+	}
 
 	return userId, role, nil
 }
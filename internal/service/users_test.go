@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"testing"
+	"vk-test-spring/internal/models"
+)
+
+type MockUsersRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsersRepository) Create(ctx context.Context, user models.User) error {
+	return m.Called(ctx, user).Error(0)
+}
+
+func (m *MockUsersRepository) Delete(ctx context.Context, userId string) error {
+	return m.Called(ctx, userId).Error(0)
+}
+
+func (m *MockUsersRepository) Edit(ctx context.Context, user models.User) error {
+	return m.Called(ctx, user).Error(0)
+}
+
+func (m *MockUsersRepository) GetUserIdRole(username string, password string) (string, string, error) {
+	args := m.Called(username, password)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func TestUsersService_GetUserIdRole(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(MockUsersRepository)
+		usersService := NewUsersService(repo)
+
+		repo.On("GetUserIdRole", "admin", "admin").Return("42", models.RoleAdmin, nil)
+
+		userId, role, err := usersService.GetUserIdRole("admin", "admin")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "42", userId)
+		assert.Equal(t, models.RoleAdmin, role)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		repo := new(MockUsersRepository)
+		usersService := NewUsersService(repo)
+
+		repo.On("GetUserIdRole", "admin", "wrong").Return("", "", nil)
+
+		_, _, err := usersService.GetUserIdRole("admin", "wrong")
+
+		assert.Equal(t, models.CustomError{Code: http.StatusUnauthorized, Message: "invalid username or password"}, err)
+	})
+}
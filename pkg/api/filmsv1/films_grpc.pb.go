@@ -0,0 +1,879 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: filmsv1/films.proto
+
+// Films library API for internal consumers. Every call is authenticated with the same credentials
+// as the REST API, sent as "authorization: Basic <base64 of name:password>" metadata.
+
+package filmsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	FilmsService_CreateFilm_FullMethodName = "/films.v1.FilmsService/CreateFilm"
+	FilmsService_UpdateFilm_FullMethodName = "/films.v1.FilmsService/UpdateFilm"
+	FilmsService_DeleteFilm_FullMethodName = "/films.v1.FilmsService/DeleteFilm"
+	FilmsService_GetFilm_FullMethodName    = "/films.v1.FilmsService/GetFilm"
+	FilmsService_ListFilms_FullMethodName  = "/films.v1.FilmsService/ListFilms"
+)
+
+// FilmsServiceClient is the client API for FilmsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FilmsServiceClient interface {
+	CreateFilm(ctx context.Context, in *CreateFilmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateFilm(ctx context.Context, in *UpdateFilmRequest, opts ...grpc.CallOption) (*Film, error)
+	DeleteFilm(ctx context.Context, in *DeleteFilmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetFilm(ctx context.Context, in *GetFilmRequest, opts ...grpc.CallOption) (*Film, error)
+	// ListFilms streams the films matching the request, one message per film.
+	ListFilms(ctx context.Context, in *ListFilmsRequest, opts ...grpc.CallOption) (FilmsService_ListFilmsClient, error)
+}
+
+type filmsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFilmsServiceClient(cc grpc.ClientConnInterface) FilmsServiceClient {
+	return &filmsServiceClient{cc}
+}
+
+func (c *filmsServiceClient) CreateFilm(ctx context.Context, in *CreateFilmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FilmsService_CreateFilm_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filmsServiceClient) UpdateFilm(ctx context.Context, in *UpdateFilmRequest, opts ...grpc.CallOption) (*Film, error) {
+	out := new(Film)
+	err := c.cc.Invoke(ctx, FilmsService_UpdateFilm_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filmsServiceClient) DeleteFilm(ctx context.Context, in *DeleteFilmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FilmsService_DeleteFilm_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filmsServiceClient) GetFilm(ctx context.Context, in *GetFilmRequest, opts ...grpc.CallOption) (*Film, error) {
+	out := new(Film)
+	err := c.cc.Invoke(ctx, FilmsService_GetFilm_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filmsServiceClient) ListFilms(ctx context.Context, in *ListFilmsRequest, opts ...grpc.CallOption) (FilmsService_ListFilmsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FilmsService_ServiceDesc.Streams[0], FilmsService_ListFilms_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &filmsServiceListFilmsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FilmsService_ListFilmsClient interface {
+	Recv() (*Film, error)
+	grpc.ClientStream
+}
+
+type filmsServiceListFilmsClient struct {
+	grpc.ClientStream
+}
+
+func (x *filmsServiceListFilmsClient) Recv() (*Film, error) {
+	m := new(Film)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FilmsServiceServer is the server API for FilmsService service.
+// All implementations must embed UnimplementedFilmsServiceServer
+// for forward compatibility
+type FilmsServiceServer interface {
+	CreateFilm(context.Context, *CreateFilmRequest) (*emptypb.Empty, error)
+	UpdateFilm(context.Context, *UpdateFilmRequest) (*Film, error)
+	DeleteFilm(context.Context, *DeleteFilmRequest) (*emptypb.Empty, error)
+	GetFilm(context.Context, *GetFilmRequest) (*Film, error)
+	// ListFilms streams the films matching the request, one message per film.
+	ListFilms(*ListFilmsRequest, FilmsService_ListFilmsServer) error
+	mustEmbedUnimplementedFilmsServiceServer()
+}
+
+// UnimplementedFilmsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFilmsServiceServer struct {
+}
+
+func (UnimplementedFilmsServiceServer) CreateFilm(context.Context, *CreateFilmRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFilm not implemented")
+}
+func (UnimplementedFilmsServiceServer) UpdateFilm(context.Context, *UpdateFilmRequest) (*Film, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFilm not implemented")
+}
+func (UnimplementedFilmsServiceServer) DeleteFilm(context.Context, *DeleteFilmRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFilm not implemented")
+}
+func (UnimplementedFilmsServiceServer) GetFilm(context.Context, *GetFilmRequest) (*Film, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFilm not implemented")
+}
+func (UnimplementedFilmsServiceServer) ListFilms(*ListFilmsRequest, FilmsService_ListFilmsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListFilms not implemented")
+}
+func (UnimplementedFilmsServiceServer) mustEmbedUnimplementedFilmsServiceServer() {}
+
+// UnsafeFilmsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FilmsServiceServer will
+// result in compilation errors.
+type UnsafeFilmsServiceServer interface {
+	mustEmbedUnimplementedFilmsServiceServer()
+}
+
+func RegisterFilmsServiceServer(s grpc.ServiceRegistrar, srv FilmsServiceServer) {
+	s.RegisterService(&FilmsService_ServiceDesc, srv)
+}
+
+func _FilmsService_CreateFilm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFilmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilmsServiceServer).CreateFilm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilmsService_CreateFilm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilmsServiceServer).CreateFilm(ctx, req.(*CreateFilmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilmsService_UpdateFilm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFilmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilmsServiceServer).UpdateFilm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilmsService_UpdateFilm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilmsServiceServer).UpdateFilm(ctx, req.(*UpdateFilmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilmsService_DeleteFilm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFilmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilmsServiceServer).DeleteFilm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilmsService_DeleteFilm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilmsServiceServer).DeleteFilm(ctx, req.(*DeleteFilmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilmsService_GetFilm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFilmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilmsServiceServer).GetFilm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilmsService_GetFilm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilmsServiceServer).GetFilm(ctx, req.(*GetFilmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilmsService_ListFilms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListFilmsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FilmsServiceServer).ListFilms(m, &filmsServiceListFilmsServer{stream})
+}
+
+type FilmsService_ListFilmsServer interface {
+	Send(*Film) error
+	grpc.ServerStream
+}
+
+type filmsServiceListFilmsServer struct {
+	grpc.ServerStream
+}
+
+func (x *filmsServiceListFilmsServer) Send(m *Film) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// FilmsService_ServiceDesc is the grpc.ServiceDesc for FilmsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FilmsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "films.v1.FilmsService",
+	HandlerType: (*FilmsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFilm",
+			Handler:    _FilmsService_CreateFilm_Handler,
+		},
+		{
+			MethodName: "UpdateFilm",
+			Handler:    _FilmsService_UpdateFilm_Handler,
+		},
+		{
+			MethodName: "DeleteFilm",
+			Handler:    _FilmsService_DeleteFilm_Handler,
+		},
+		{
+			MethodName: "GetFilm",
+			Handler:    _FilmsService_GetFilm_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListFilms",
+			Handler:       _FilmsService_ListFilms_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filmsv1/films.proto",
+}
+
+const (
+	ActorsService_CreateActor_FullMethodName = "/films.v1.ActorsService/CreateActor"
+	ActorsService_UpdateActor_FullMethodName = "/films.v1.ActorsService/UpdateActor"
+	ActorsService_DeleteActor_FullMethodName = "/films.v1.ActorsService/DeleteActor"
+	ActorsService_GetActor_FullMethodName    = "/films.v1.ActorsService/GetActor"
+	ActorsService_ListActors_FullMethodName  = "/films.v1.ActorsService/ListActors"
+)
+
+// ActorsServiceClient is the client API for ActorsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ActorsServiceClient interface {
+	CreateActor(ctx context.Context, in *CreateActorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateActor(ctx context.Context, in *UpdateActorRequest, opts ...grpc.CallOption) (*Actor, error)
+	DeleteActor(ctx context.Context, in *DeleteActorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetActor(ctx context.Context, in *GetActorRequest, opts ...grpc.CallOption) (*Actor, error)
+	// ListActors streams the actors matching the request, one message per actor.
+	ListActors(ctx context.Context, in *ListActorsRequest, opts ...grpc.CallOption) (ActorsService_ListActorsClient, error)
+}
+
+type actorsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActorsServiceClient(cc grpc.ClientConnInterface) ActorsServiceClient {
+	return &actorsServiceClient{cc}
+}
+
+func (c *actorsServiceClient) CreateActor(ctx context.Context, in *CreateActorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ActorsService_CreateActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) UpdateActor(ctx context.Context, in *UpdateActorRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorsService_UpdateActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) DeleteActor(ctx context.Context, in *DeleteActorRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ActorsService_DeleteActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) GetActor(ctx context.Context, in *GetActorRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorsService_GetActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) ListActors(ctx context.Context, in *ListActorsRequest, opts ...grpc.CallOption) (ActorsService_ListActorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ActorsService_ServiceDesc.Streams[0], ActorsService_ListActors_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actorsServiceListActorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActorsService_ListActorsClient interface {
+	Recv() (*Actor, error)
+	grpc.ClientStream
+}
+
+type actorsServiceListActorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *actorsServiceListActorsClient) Recv() (*Actor, error) {
+	m := new(Actor)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ActorsServiceServer is the server API for ActorsService service.
+// All implementations must embed UnimplementedActorsServiceServer
+// for forward compatibility
+type ActorsServiceServer interface {
+	CreateActor(context.Context, *CreateActorRequest) (*emptypb.Empty, error)
+	UpdateActor(context.Context, *UpdateActorRequest) (*Actor, error)
+	DeleteActor(context.Context, *DeleteActorRequest) (*emptypb.Empty, error)
+	GetActor(context.Context, *GetActorRequest) (*Actor, error)
+	// ListActors streams the actors matching the request, one message per actor.
+	ListActors(*ListActorsRequest, ActorsService_ListActorsServer) error
+	mustEmbedUnimplementedActorsServiceServer()
+}
+
+// UnimplementedActorsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedActorsServiceServer struct {
+}
+
+func (UnimplementedActorsServiceServer) CreateActor(context.Context, *CreateActorRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateActor not implemented")
+}
+func (UnimplementedActorsServiceServer) UpdateActor(context.Context, *UpdateActorRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateActor not implemented")
+}
+func (UnimplementedActorsServiceServer) DeleteActor(context.Context, *DeleteActorRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteActor not implemented")
+}
+func (UnimplementedActorsServiceServer) GetActor(context.Context, *GetActorRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActor not implemented")
+}
+func (UnimplementedActorsServiceServer) ListActors(*ListActorsRequest, ActorsService_ListActorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListActors not implemented")
+}
+func (UnimplementedActorsServiceServer) mustEmbedUnimplementedActorsServiceServer() {}
+
+// UnsafeActorsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActorsServiceServer will
+// result in compilation errors.
+type UnsafeActorsServiceServer interface {
+	mustEmbedUnimplementedActorsServiceServer()
+}
+
+func RegisterActorsServiceServer(s grpc.ServiceRegistrar, srv ActorsServiceServer) {
+	s.RegisterService(&ActorsService_ServiceDesc, srv)
+}
+
+func _ActorsService_CreateActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).CreateActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_CreateActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).CreateActor(ctx, req.(*CreateActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_UpdateActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).UpdateActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_UpdateActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).UpdateActor(ctx, req.(*UpdateActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_DeleteActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).DeleteActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_DeleteActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).DeleteActor(ctx, req.(*DeleteActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_GetActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).GetActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_GetActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).GetActor(ctx, req.(*GetActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_ListActors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListActorsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActorsServiceServer).ListActors(m, &actorsServiceListActorsServer{stream})
+}
+
+type ActorsService_ListActorsServer interface {
+	Send(*Actor) error
+	grpc.ServerStream
+}
+
+type actorsServiceListActorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *actorsServiceListActorsServer) Send(m *Actor) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ActorsService_ServiceDesc is the grpc.ServiceDesc for ActorsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ActorsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "films.v1.ActorsService",
+	HandlerType: (*ActorsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateActor",
+			Handler:    _ActorsService_CreateActor_Handler,
+		},
+		{
+			MethodName: "UpdateActor",
+			Handler:    _ActorsService_UpdateActor_Handler,
+		},
+		{
+			MethodName: "DeleteActor",
+			Handler:    _ActorsService_DeleteActor_Handler,
+		},
+		{
+			MethodName: "GetActor",
+			Handler:    _ActorsService_GetActor_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListActors",
+			Handler:       _ActorsService_ListActors_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filmsv1/films.proto",
+}
+
+const (
+	UsersService_GetMe_FullMethodName      = "/films.v1.UsersService/GetMe"
+	UsersService_CreateUser_FullMethodName = "/films.v1.UsersService/CreateUser"
+	UsersService_DeleteUser_FullMethodName = "/films.v1.UsersService/DeleteUser"
+	UsersService_ChangeRole_FullMethodName = "/films.v1.UsersService/ChangeRole"
+)
+
+// UsersServiceClient is the client API for UsersService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UsersServiceClient interface {
+	GetMe(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*User, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ChangeRole(ctx context.Context, in *ChangeRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type usersServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUsersServiceClient(cc grpc.ClientConnInterface) UsersServiceClient {
+	return &usersServiceClient{cc}
+}
+
+func (c *usersServiceClient) GetMe(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, UsersService_GetMe_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UsersService_CreateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UsersService_DeleteUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersServiceClient) ChangeRole(ctx context.Context, in *ChangeRoleRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UsersService_ChangeRole_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UsersServiceServer is the server API for UsersService service.
+// All implementations must embed UnimplementedUsersServiceServer
+// for forward compatibility
+type UsersServiceServer interface {
+	GetMe(context.Context, *emptypb.Empty) (*User, error)
+	CreateUser(context.Context, *CreateUserRequest) (*emptypb.Empty, error)
+	DeleteUser(context.Context, *DeleteUserRequest) (*emptypb.Empty, error)
+	ChangeRole(context.Context, *ChangeRoleRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedUsersServiceServer()
+}
+
+// UnimplementedUsersServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedUsersServiceServer struct {
+}
+
+func (UnimplementedUsersServiceServer) GetMe(context.Context, *emptypb.Empty) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMe not implemented")
+}
+func (UnimplementedUsersServiceServer) CreateUser(context.Context, *CreateUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUsersServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUsersServiceServer) ChangeRole(context.Context, *ChangeRoleRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeRole not implemented")
+}
+func (UnimplementedUsersServiceServer) mustEmbedUnimplementedUsersServiceServer() {}
+
+// UnsafeUsersServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UsersServiceServer will
+// result in compilation errors.
+type UnsafeUsersServiceServer interface {
+	mustEmbedUnimplementedUsersServiceServer()
+}
+
+func RegisterUsersServiceServer(s grpc.ServiceRegistrar, srv UsersServiceServer) {
+	s.RegisterService(&UsersService_ServiceDesc, srv)
+}
+
+func _UsersService_GetMe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).GetMe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_GetMe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).GetMe(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersService_ChangeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServiceServer).ChangeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersService_ChangeRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServiceServer).ChangeRole(ctx, req.(*ChangeRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UsersService_ServiceDesc is the grpc.ServiceDesc for UsersService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UsersService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "films.v1.UsersService",
+	HandlerType: (*UsersServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMe",
+			Handler:    _UsersService_GetMe_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _UsersService_CreateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UsersService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "ChangeRole",
+			Handler:    _UsersService_ChangeRole_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "filmsv1/films.proto",
+}
+
+const (
+	ExportService_Export_FullMethodName = "/films.v1.ExportService/Export"
+)
+
+// ExportServiceClient is the client API for ExportService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExportServiceClient interface {
+	// Export streams every actor, then every film with its cast.
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (ExportService_ExportClient, error)
+}
+
+type exportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExportServiceClient(cc grpc.ClientConnInterface) ExportServiceClient {
+	return &exportServiceClient{cc}
+}
+
+func (c *exportServiceClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (ExportService_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ExportService_ServiceDesc.Streams[0], ExportService_Export_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &exportServiceExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ExportService_ExportClient interface {
+	Recv() (*ExportRecord, error)
+	grpc.ClientStream
+}
+
+type exportServiceExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *exportServiceExportClient) Recv() (*ExportRecord, error) {
+	m := new(ExportRecord)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ExportServiceServer is the server API for ExportService service.
+// All implementations must embed UnimplementedExportServiceServer
+// for forward compatibility
+type ExportServiceServer interface {
+	// Export streams every actor, then every film with its cast.
+	Export(*ExportRequest, ExportService_ExportServer) error
+	mustEmbedUnimplementedExportServiceServer()
+}
+
+// UnimplementedExportServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedExportServiceServer struct {
+}
+
+func (UnimplementedExportServiceServer) Export(*ExportRequest, ExportService_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedExportServiceServer) mustEmbedUnimplementedExportServiceServer() {}
+
+// UnsafeExportServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExportServiceServer will
+// result in compilation errors.
+type UnsafeExportServiceServer interface {
+	mustEmbedUnimplementedExportServiceServer()
+}
+
+func RegisterExportServiceServer(s grpc.ServiceRegistrar, srv ExportServiceServer) {
+	s.RegisterService(&ExportService_ServiceDesc, srv)
+}
+
+func _ExportService_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ExportServiceServer).Export(m, &exportServiceExportServer{stream})
+}
+
+type ExportService_ExportServer interface {
+	Send(*ExportRecord) error
+	grpc.ServerStream
+}
+
+type exportServiceExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *exportServiceExportServer) Send(m *ExportRecord) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ExportService_ServiceDesc is the grpc.ServiceDesc for ExportService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "films.v1.ExportService",
+	HandlerType: (*ExportServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _ExportService_Export_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filmsv1/films.proto",
+}
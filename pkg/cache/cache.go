@@ -0,0 +1,22 @@
+package cache
+
+// Cache stores serialized values under string keys. Every entry can be labelled with tags,
+// so related entries can be dropped together when the records behind them change.
+//
+// A value loaded while its records change must not be stored: take Generation before loading and
+// store with SetSince, which skips the value if any of its tags was invalidated in the meantime.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, tags ...string)
+	SetSince(generation uint64, key string, value []byte, tags ...string) bool
+	Generation() uint64
+	Invalidate(tags ...string)
+	Stats() Stats
+}
+
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process Cache that evicts the least recently used entry once it holds size entries.
+// Entries older than ttl are treated as missing.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	items   map[string]*list.Element
+	order   *list.List
+	tags    map[string]map[string]struct{}
+	stats   Stats
+	nowFunc func() time.Time
+
+	// generation counts invalidations. invalidated holds the generation each tag was last
+	// invalidated at, down to floor: tags invalidated before floor have been forgotten.
+	generation  uint64
+	invalidated map[string]uint64
+	floor       uint64
+}
+
+// maxInvalidatedTags bounds the tags whose invalidation is remembered for SetSince.
+const maxInvalidatedTags = 4096
+
+type entry struct {
+	key     string
+	value   []byte
+	tags    []string
+	expires time.Time
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:    size,
+		ttl:     ttl,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+		tags:    make(map[string]map[string]struct{}),
+		nowFunc: time.Now,
+
+		invalidated: make(map[string]uint64),
+	}
+}
+
+func (c *LRU) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if c.ttl > 0 && c.nowFunc().After(e.expires) {
+		c.remove(el)
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	c.stats.Hits++
+
+	return e.value, true
+}
+
+func (c *LRU) Set(key string, value []byte, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, tags)
+}
+
+// SetSince stores value unless one of its tags was invalidated after generation, see Generation.
+// It reports whether the value was stored.
+func (c *LRU) SetSince(generation uint64, key string, value []byte, tags ...string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation < c.floor {
+		return false
+	}
+	for _, tag := range tags {
+		if c.invalidated[tag] > generation {
+			return false
+		}
+	}
+
+	c.set(key, value, tags)
+	return true
+}
+
+// Generation returns the current invalidation generation.
+func (c *LRU) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+func (c *LRU) set(key string, value []byte, tags []string) {
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+
+	e := &entry{
+		key:     key,
+		value:   value,
+		tags:    tags,
+		expires: c.nowFunc().Add(c.ttl),
+	}
+	c.items[key] = c.order.PushFront(e)
+
+	for _, tag := range tags {
+		keys, ok := c.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.size > 0 && c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *LRU) Invalidate(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if len(c.invalidated)+len(tags) > maxInvalidatedTags {
+		c.invalidated = make(map[string]uint64)
+		c.floor = c.generation
+	}
+
+	for _, tag := range tags {
+		c.invalidated[tag] = c.generation
+
+		for key := range c.tags[tag] {
+			if el, ok := c.items[key]; ok {
+				c.remove(el)
+			}
+		}
+	}
+}
+
+func (c *LRU) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+
+	return stats
+}
+
+func (c *LRU) remove(el *list.Element) {
+	e := el.Value.(*entry)
+
+	c.order.Remove(el)
+	delete(c.items, e.key)
+
+	for _, tag := range e.tags {
+		keys := c.tags[tag]
+		delete(keys, e.key)
+		if len(keys) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
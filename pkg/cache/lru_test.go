@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	t.Run("Hit and miss", func(t *testing.T) {
+		c := NewLRU(10, time.Minute)
+
+		c.Set("a", []byte("1"))
+
+		v, ok := c.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), v)
+
+		_, ok = c.Get("b")
+		assert.False(t, ok)
+
+		assert.Equal(t, Stats{Hits: 1, Misses: 1, Entries: 1}, c.Stats())
+	})
+
+	t.Run("Evicts least recently used", func(t *testing.T) {
+		c := NewLRU(2, time.Minute)
+
+		c.Set("a", []byte("1"))
+		c.Set("b", []byte("2"))
+		c.Get("a")
+		c.Set("c", []byte("3"))
+
+		_, ok := c.Get("b")
+		assert.False(t, ok)
+		_, ok = c.Get("a")
+		assert.True(t, ok)
+		_, ok = c.Get("c")
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), c.Stats().Evictions)
+	})
+
+	t.Run("Expires after ttl", func(t *testing.T) {
+		c := NewLRU(10, time.Minute)
+		now := time.Now()
+		c.nowFunc = func() time.Time { return now }
+
+		c.Set("a", []byte("1"))
+
+		now = now.Add(2 * time.Minute)
+		_, ok := c.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Stats().Entries)
+	})
+}
+
+func TestLRU_Invalidate(t *testing.T) {
+	t.Run("Drops only tagged entries", func(t *testing.T) {
+		c := NewLRU(10, time.Minute)
+
+		c.Set("films", []byte("[]"), "films", "film:1")
+		c.Set("actor", []byte("{}"), "actor:2", "film:1")
+		c.Set("other", []byte("{}"), "actor:3")
+
+		c.Invalidate("film:1")
+
+		_, ok := c.Get("films")
+		assert.False(t, ok)
+		_, ok = c.Get("actor")
+		assert.False(t, ok)
+		_, ok = c.Get("other")
+		assert.True(t, ok)
+	})
+
+	t.Run("Overwrite drops old tags", func(t *testing.T) {
+		c := NewLRU(10, time.Minute)
+
+		c.Set("a", []byte("1"), "old")
+		c.Set("a", []byte("2"), "new")
+
+		c.Invalidate("old")
+
+		v, ok := c.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("2"), v)
+	})
+}
+
+func TestLRU_SetSince(t *testing.T) {
+	t.Run("Skips values whose tags were invalidated", func(t *testing.T) {
+		c := NewLRU(10, time.Minute)
+
+		generation := c.Generation()
+		c.Invalidate("film:1")
+
+		assert.False(t, c.SetSince(generation, "films", []byte("[]"), "films", "film:1"))
+		assert.True(t, c.SetSince(generation, "actor", []byte("{}"), "actor:2"))
+		assert.True(t, c.SetSince(c.Generation(), "films", []byte("[]"), "films", "film:1"))
+
+		_, ok := c.Get("films")
+		assert.True(t, ok)
+		_, ok = c.Get("actor")
+		assert.True(t, ok)
+	})
+
+	t.Run("Forgotten invalidations skip older values", func(t *testing.T) {
+		c := NewLRU(10, time.Minute)
+
+		generation := c.Generation()
+		for i := 0; i <= maxInvalidatedTags; i++ {
+			c.Invalidate(fmt.Sprintf("film:%d", i))
+		}
+
+		assert.False(t, c.SetSince(generation, "actor", []byte("{}"), "actor:2"))
+		assert.True(t, c.SetSince(c.Generation(), "actor", []byte("{}"), "actor:2"))
+	})
+}
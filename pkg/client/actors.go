@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Actor struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	SecondName  string      `json:"second_name"`
+	Patronymic  string      `json:"patronymic"`
+	Sex         string      `json:"sex"`
+	DateOfBirth string      `json:"date_of_birth"`
+	Films       []ActorFilm `json:"films"`
+	Score       float64     `json:"score,omitempty"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type ActorFilm struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Sex values of actors.
+const (
+	SexMale   = "Мужчина"
+	SexFemale = "Женщина"
+)
+
+type ActorInput struct {
+	Name        string   `json:"name"`
+	SecondName  string   `json:"second_name"`
+	Patronymic  string   `json:"patronymic"`
+	Sex         string   `json:"sex"`
+	DateOfBirth string   `json:"date_of_birth"`
+	Films       []string `json:"films,omitempty"`
+}
+
+// ActorUpdate changes the fields that are set.
+type ActorUpdate struct {
+	Name        string   `json:"name,omitempty"`
+	SecondName  string   `json:"second_name,omitempty"`
+	Patronymic  string   `json:"patronymic,omitempty"`
+	Sex         string   `json:"sex,omitempty"`
+	DateOfBirth string   `json:"date_of_birth,omitempty"`
+	FilmsToAdd  []string `json:"films_to_add,omitempty"`
+	FilmsToDel  []string `json:"films_to_del,omitempty"`
+}
+
+type ActorsService struct {
+	client *Client
+}
+
+func (s *ActorsService) List(ctx context.Context) ([]Actor, error) {
+	var actors []Actor
+	err := s.client.do(ctx, http.MethodGet, "/actors", nil, nil, &actors)
+
+	return actors, err
+}
+
+// SearchByName returns the actors with a full name similar to name, the most similar first.
+func (s *ActorsService) SearchByName(ctx context.Context, name string) ([]Actor, error) {
+	var actors []Actor
+	err := s.client.do(ctx, http.MethodGet, "/actors", url.Values{"name": {name}}, nil, &actors)
+
+	return actors, err
+}
+
+func (s *ActorsService) Get(ctx context.Context, id string) (Actor, error) {
+	var actor Actor
+	err := s.client.do(ctx, http.MethodGet, "/actors/"+url.PathEscape(id), nil, nil, &actor)
+
+	return actor, err
+}
+
+// Create adds an actor. The API does not return the new id; find the actor with SearchByName.
+func (s *ActorsService) Create(ctx context.Context, input ActorInput) error {
+	return s.client.do(ctx, http.MethodPost, "/actors", nil, input, nil)
+}
+
+func (s *ActorsService) Update(ctx context.Context, id string, input ActorUpdate) error {
+	return s.client.do(ctx, http.MethodPatch, "/actors/"+url.PathEscape(id), nil, input, nil)
+}
+
+func (s *ActorsService) Delete(ctx context.Context, id string) error {
+	return s.client.do(ctx, http.MethodDelete, "/actors/"+url.PathEscape(id), nil, nil, nil)
+}
@@ -0,0 +1,208 @@
+// Package client is a Go client of the films library API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 100 * time.Millisecond
+	maxBackoff        = 5 * time.Second
+)
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	username   string
+	password   string
+	maxRetries int
+	backoff    time.Duration
+
+	Films  *FilmsService
+	Actors *ActorsService
+	Users  *UsersService
+	Search *SearchService
+}
+
+type Option func(c *Client)
+
+// WithBasicAuth sets the credentials sent with every request.
+func WithBasicAuth(username string, password string) Option {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times a request is repeated after a 429 or 5xx response, waiting
+// backoff before the first repetition and twice as long before each next one.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// New returns a client of the API at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: http.DefaultClient,
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.init()
+
+	return c, nil
+}
+
+func (c *Client) init() {
+	c.Films = &FilmsService{client: c}
+	c.Actors = &ActorsService{client: c}
+	c.Users = &UsersService{client: c}
+	c.Search = &SearchService{client: c}
+}
+
+// WithCredentials returns a copy of c that authenticates as another user.
+func (c *Client) WithCredentials(username string, password string) *Client {
+	clone := *c
+	clone.username = username
+	clone.password = password
+	clone.init()
+
+	return &clone
+}
+
+// do sends a request with in encoded as the JSON body, if not nil, and decodes the response into out,
+// if not nil. Responses other than 2xx become an *Error.
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, in any, out any) error {
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+		body = b
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, method, u, body)
+		if err != nil {
+			return err
+		}
+
+		if attempt < c.maxRetries && retryable(method, res.StatusCode) {
+			wait := retryAfter(res, backoff)
+			res.Body.Close()
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+
+			backoff = min(2*backoff, maxBackoff)
+			continue
+		}
+
+		return decodeResponse(res, out)
+	}
+}
+
+func (c *Client) send(ctx context.Context, method string, u string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func decodeResponse(res *http.Response, out any) error {
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return decodeError(res)
+	}
+
+	if out == nil {
+		io.Copy(io.Discard, res.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// retryable reports whether a response is worth repeating the request for. 429 and 503 mean the
+// request was not processed, so any method is repeated; other server errors only for methods
+// that are safe to repeat.
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete
+	default:
+		return false
+	}
+}
+
+// retryAfter honours the Retry-After header given in seconds, falling back to backoff.
+func retryAfter(res *http.Response, backoff time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return backoff
+	}
+
+	return min(time.Duration(seconds)*time.Second, maxBackoff)
+}
@@ -0,0 +1,270 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/controller"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/repository"
+	"vk-test-spring/internal/repository/repositorytest"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/client"
+)
+
+// fakeSearch pages over a fixed list of results.
+type fakeSearch struct {
+	results []models.SearchResult
+}
+
+func (s fakeSearch) Search(ctx context.Context, input service.SearchInput) (models.SearchPage, error) {
+	from := min((input.Page-1)*input.Limit, len(s.results))
+	to := min(from+input.Limit, len(s.results))
+
+	return models.SearchPage{Items: s.results[from:to], Total: len(s.results), Page: input.Page, Limit: input.Limit}, nil
+}
+
+func (s fakeSearch) Suggest(ctx context.Context, input service.SuggestInput) ([]models.Suggestion, error) {
+	return []models.Suggestion{{ID: uuid.New(), Name: "Брат", Year: 1997}}, nil
+}
+
+// newServer serves the real handlers over the memory storage, passing every request through wrap.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) *httptest.Server {
+	users := []config.StorageUserConfig{
+		{Name: "admin", PasswordHash: repositorytest.PasswordHash(t, "admin"), Role: "администратор"},
+		{Name: "user", PasswordHash: repositorytest.PasswordHash(t, "user"), Role: "пользователь"},
+	}
+	services := service.NewServices(repository.NewMemoryRepositories(users, config.SearchConfig{SimilarityThreshold: 0.3}), nil)
+
+	var results []models.SearchResult
+	for i := 0; i < 5; i++ {
+		results = append(results, models.SearchResult{Type: models.SearchTypeFilm, ID: uuid.New(), Title: "Брат", Rank: float64(5 - i)})
+	}
+	services.Search = fakeSearch{results: results}
+
+	var handler http.Handler = controller.NewHandler(&config.Config{}, nil).Init(services, zerolog.Nop())
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newClient(t *testing.T, url string, opts ...client.Option) *client.Client {
+	opts = append([]client.Option{client.WithBasicAuth("admin", "admin"), client.WithRetries(3, time.Millisecond)}, opts...)
+
+	c, err := client.New(url, opts...)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestFilmsAndActors(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, newServer(t, nil).URL)
+
+	require.NoError(t, c.Actors.Create(ctx, client.ActorInput{
+		Name: "Сергей", SecondName: "Бодров", Patronymic: "Сергеевич", Sex: client.SexMale, DateOfBirth: "1971-12-27",
+	}))
+
+	actors, err := c.Actors.SearchByName(ctx, "Бодров")
+	require.NoError(t, err)
+	require.Len(t, actors, 1)
+	actor := actors[0]
+
+	require.NoError(t, c.Films.Create(ctx, client.FilmInput{
+		Name: "Брат", Description: "Данила едет в Петербург", Date: "1997-12-12", Rating: 8.3, Actors: []string{actor.ID},
+	}))
+	require.NoError(t, c.Films.Create(ctx, client.FilmInput{
+		Name: "Асса", Description: "Бананан и Алика", Date: "1987-01-01", Rating: 7.1,
+	}))
+
+	t.Run("list and get", func(t *testing.T) {
+		films, err := c.Films.List(ctx, client.FilmsListOptions{Sort: client.SortByRating, Desc: true})
+		require.NoError(t, err)
+		require.Len(t, films, 2)
+		assert.Equal(t, "Брат", films[0].Name)
+		assert.Equal(t, []client.FilmActor{{ID: actor.ID, Name: "Сергей", SecondName: "Бодров", Patronymic: "Сергеевич"}}, films[0].Actors)
+
+		film, err := c.Films.Get(ctx, films[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, films[0].ID, film.ID)
+
+		films, err = c.Films.SearchByActor(ctx, "Бодров")
+		require.NoError(t, err)
+		require.Len(t, films, 1)
+
+		actor, err := c.Actors.Get(ctx, actor.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []client.ActorFilm{{ID: film.ID, Name: "Брат"}}, actor.Films)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		films, err := c.Films.SearchByName(ctx, "Асса")
+		require.NoError(t, err)
+		require.Len(t, films, 1)
+		id := films[0].ID
+
+		require.NoError(t, c.Films.Update(ctx, id, client.FilmUpdate{Rating: 9}))
+		film, err := c.Films.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 9.0, film.Rating)
+
+		require.NoError(t, c.Films.Delete(ctx, id))
+		_, err = c.Films.Get(ctx, id)
+		assert.ErrorIs(t, err, client.ErrNotFound)
+	})
+
+	t.Run("typed errors", func(t *testing.T) {
+		_, err := c.Actors.Get(ctx, uuid.NewString())
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Contains(t, apiErr.Message, "not found actor")
+		assert.NotEmpty(t, apiErr.RequestID)
+		assert.False(t, errors.Is(err, client.ErrServer))
+	})
+}
+
+func TestAuth(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, newServer(t, nil).URL)
+
+	t.Run("role", func(t *testing.T) {
+		err := c.WithCredentials("user", "user").Films.Delete(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, client.ErrForbidden)
+
+		_, err = c.WithCredentials("user", "user").Films.List(ctx, client.FilmsListOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := c.WithCredentials("admin", "wrong").Films.List(ctx, client.FilmsListOptions{})
+		assert.ErrorIs(t, err, client.ErrUnauthorized)
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		_, err := c.WithCredentials("", "").Actors.List(ctx)
+		assert.ErrorIs(t, err, client.ErrUnauthorized)
+	})
+
+	t.Run("lists are not available with the memory storage", func(t *testing.T) {
+		_, err := c.Users.Lists(ctx)
+		assert.ErrorIs(t, err, client.ErrNotImplemented)
+
+		err = c.Users.UpdateFilm(ctx, "watchlist", uuid.NewString(), client.ListFilmUpdate{Position: 1})
+		assert.ErrorIs(t, err, client.ErrNotImplemented)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	var requests atomic.Int32
+	server := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	})
+	c := newClient(t, server.URL)
+
+	it := c.Search.All(context.Background(), "брат", 2)
+
+	var ranks []float64
+	for it.Next() {
+		ranks = append(ranks, it.Result().Rank)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []float64{5, 4, 3, 2, 1}, ranks)
+	assert.Equal(t, 5, it.Total())
+	assert.Equal(t, int32(3), requests.Load())
+
+	suggestions, err := c.Search.Suggest(context.Background(), "бр", client.SuggestFilms, 0)
+	require.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+}
+
+// failing answers the first n requests with status instead of passing them on.
+func failing(n int32, status int, requests *atomic.Int32) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= n {
+				w.Header().Set("Retry-After", "0")
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reads are retried on server errors", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, newServer(t, failing(2, http.StatusBadGateway, &requests)).URL)
+
+		_, err := c.Films.List(ctx, client.FilmsListOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("writes are retried on 429", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, newServer(t, failing(1, http.StatusTooManyRequests, &requests)).URL)
+
+		err := c.Actors.Create(ctx, client.ActorInput{Name: "Виктор", SecondName: "Цой", Patronymic: "Робертович", Sex: client.SexMale, DateOfBirth: "1962-06-21"})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("writes are not retried on 500", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, newServer(t, failing(1, http.StatusInternalServerError, &requests)).URL)
+
+		err := c.Actors.Create(ctx, client.ActorInput{})
+		assert.ErrorIs(t, err, client.ErrServer)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newClient(t, newServer(t, failing(10, http.StatusServiceUnavailable, &requests)).URL)
+
+		_, err := c.Films.List(ctx, client.FilmsListOptions{})
+		assert.ErrorIs(t, err, client.ErrServer)
+		assert.Equal(t, int32(4), requests.Load())
+	})
+}
+
+func TestProblemDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"type": "https://example.com/probs/duplicate", "title": "Duplicate", "status": 409, "detail": "film already exists"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	err := newClient(t, server.URL).Films.Create(context.Background(), client.FilmInput{Name: "Брат"})
+
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, client.ErrConflict)
+	assert.Equal(t, "https://example.com/probs/duplicate", apiErr.Type)
+	assert.Equal(t, "409 film already exists", err.Error())
+}
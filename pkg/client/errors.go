@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Error is a response other than 2xx. It carries both the {"error", "request_id"} body the API
+// answers with and the fields of RFC 7807 problem details, so it decodes either.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"error"`
+	RequestID  string `json:"request_id"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Detail     string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Detail
+	}
+	if message == "" {
+		message = e.Title
+	}
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	if e.RequestID != "" {
+		return fmt.Sprintf("%d %s (request %s)", e.StatusCode, message, e.RequestID)
+	}
+
+	return fmt.Sprintf("%d %s", e.StatusCode, message)
+}
+
+// Is matches the sentinel errors below by status code: errors.Is(err, client.ErrNotFound).
+// ErrServer matches every 5xx.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	if t == ErrServer {
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return t.StatusCode == e.StatusCode
+}
+
+var (
+	ErrBadRequest      = &Error{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized    = &Error{StatusCode: http.StatusUnauthorized}
+	ErrForbidden       = &Error{StatusCode: http.StatusForbidden}
+	ErrNotFound        = &Error{StatusCode: http.StatusNotFound}
+	ErrConflict        = &Error{StatusCode: http.StatusConflict}
+	ErrTooManyRequests = &Error{StatusCode: http.StatusTooManyRequests}
+	ErrNotImplemented  = &Error{StatusCode: http.StatusNotImplemented}
+	ErrServer          = &Error{StatusCode: http.StatusInternalServerError}
+)
+
+func decodeError(res *http.Response) error {
+	e := &Error{StatusCode: res.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if json.Unmarshal(body, e) != nil {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	e.StatusCode = res.StatusCode
+
+	return e
+}
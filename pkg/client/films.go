@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Film struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Date        string      `json:"date"`
+	Rating      float64     `json:"rating"`
+	Actors      []FilmActor `json:"actors"`
+	Score       float64     `json:"score,omitempty"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type FilmActor struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	SecondName string `json:"second_name"`
+	Patronymic string `json:"patronymic"`
+}
+
+type FilmInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Date        string   `json:"date"`
+	Rating      float64  `json:"rating"`
+	Actors      []string `json:"actors,omitempty"`
+	// NewActors are created together with the film.
+	NewActors []ActorInput `json:"new_actors,omitempty"`
+}
+
+// FilmUpdate changes the fields that are set.
+type FilmUpdate struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Date        string   `json:"date,omitempty"`
+	Rating      float64  `json:"rating,omitempty"`
+	ActorsToAdd []string `json:"actors_to_add,omitempty"`
+	ActorsToDel []string `json:"actors_to_del,omitempty"`
+}
+
+// Sort values of FilmsListOptions.
+const (
+	SortByName   = "name"
+	SortByDate   = "date"
+	SortByRating = "rating"
+)
+
+type FilmsListOptions struct {
+	Sort string
+	// Desc sorts in descending order.
+	Desc bool
+}
+
+type FilmsService struct {
+	client *Client
+}
+
+// List returns every film, sorted as opts asks or, without opts.Sort, in the order of the server.
+func (s *FilmsService) List(ctx context.Context, opts FilmsListOptions) ([]Film, error) {
+	query := url.Values{}
+	if opts.Sort != "" {
+		order := "asc"
+		if opts.Desc {
+			order = "desc"
+		}
+
+		query.Set("sort", opts.Sort)
+		query.Set("order", order)
+	}
+
+	var films []Film
+	err := s.client.do(ctx, http.MethodGet, "/films", query, nil, &films)
+
+	return films, err
+}
+
+// SearchByName returns the films with a name similar to name, the most similar first.
+func (s *FilmsService) SearchByName(ctx context.Context, name string) ([]Film, error) {
+	var films []Film
+	err := s.client.do(ctx, http.MethodGet, "/films", url.Values{"name": {name}}, nil, &films)
+
+	return films, err
+}
+
+// SearchByActor returns the films with an actor whose name is similar to actorName.
+func (s *FilmsService) SearchByActor(ctx context.Context, actorName string) ([]Film, error) {
+	var films []Film
+	err := s.client.do(ctx, http.MethodGet, "/films", url.Values{"actor-name": {actorName}}, nil, &films)
+
+	return films, err
+}
+
+func (s *FilmsService) Get(ctx context.Context, id string) (Film, error) {
+	var film Film
+	err := s.client.do(ctx, http.MethodGet, "/films/"+url.PathEscape(id), nil, nil, &film)
+
+	return film, err
+}
+
+// Create adds a film. The API does not return the new id; find the film with SearchByName.
+func (s *FilmsService) Create(ctx context.Context, input FilmInput) error {
+	return s.client.do(ctx, http.MethodPost, "/films", nil, input, nil)
+}
+
+func (s *FilmsService) Update(ctx context.Context, id string, input FilmUpdate) error {
+	return s.client.do(ctx, http.MethodPatch, "/films/"+url.PathEscape(id), nil, input, nil)
+}
+
+func (s *FilmsService) Delete(ctx context.Context, id string) error {
+	return s.client.do(ctx, http.MethodDelete, "/films/"+url.PathEscape(id), nil, nil, nil)
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type SearchResult struct {
+	Type     string  `json:"type"`
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Headline string  `json:"headline"`
+	Rank     float64 `json:"rank"`
+}
+
+type SearchPage struct {
+	Items []SearchResult `json:"items"`
+	Total int            `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+type Suggestion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Year int    `json:"year"`
+}
+
+// Suggestion types.
+const (
+	SuggestFilms  = "films"
+	SuggestActors = "actors"
+)
+
+type SearchService struct {
+	client *Client
+}
+
+// Page returns one page of the full-text search results, page counting from 1. A zero limit
+// leaves the page size to the server.
+func (s *SearchService) Page(ctx context.Context, query string, page int, limit int) (SearchPage, error) {
+	params := url.Values{"q": {query}}
+	if page > 0 {
+		params.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var result SearchPage
+	err := s.client.do(ctx, http.MethodGet, "/search", params, nil, &result)
+
+	return result, err
+}
+
+// All returns an iterator over every search result, fetching pages of limit results as it goes.
+func (s *SearchService) All(ctx context.Context, query string, limit int) *SearchIterator {
+	return &SearchIterator{
+		ctx:     ctx,
+		service: s,
+		query:   query,
+		limit:   limit,
+	}
+}
+
+func (s *SearchService) Suggest(ctx context.Context, query string, suggestType string, limit int) ([]Suggestion, error) {
+	params := url.Values{"q": {query}, "type": {suggestType}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var suggestions []Suggestion
+	err := s.client.do(ctx, http.MethodGet, "/suggest", params, nil, &suggestions)
+
+	return suggestions, err
+}
+
+// SearchIterator walks the search results page by page:
+//
+//	it := c.Search.All(ctx, "brat", 50)
+//	for it.Next() {
+//		fmt.Println(it.Result().Title)
+//	}
+//	err := it.Err()
+type SearchIterator struct {
+	ctx     context.Context
+	service *SearchService
+	query   string
+	limit   int
+
+	page    SearchPage
+	index   int
+	fetched int
+	done    bool
+	err     error
+}
+
+// Next advances to the next result and reports whether there is one.
+func (it *SearchIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.page.Items) {
+		return true
+	}
+
+	if it.done {
+		return false
+	}
+
+	page, err := it.service.Page(it.ctx, it.query, it.page.Page+1, it.limit)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page = page
+	it.index = 0
+	it.fetched += len(page.Items)
+	it.done = len(page.Items) == 0 || it.fetched >= page.Total
+
+	return len(page.Items) > 0
+}
+
+// Result returns the current result.
+func (it *SearchIterator) Result() SearchResult {
+	return it.page.Items[it.index]
+}
+
+// Total is the number of results the server reported with the last page.
+func (it *SearchIterator) Total() int {
+	return it.page.Total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
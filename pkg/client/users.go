@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type List struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	Public     bool       `json:"public"`
+	ShareToken string     `json:"share_token,omitempty"`
+	CreatedAt  string     `json:"created_at"`
+	FilmsCount int        `json:"films_count"`
+	Films      []ListFilm `json:"films,omitempty"`
+}
+
+type ListFilm struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Date     string  `json:"date"`
+	Rating   float64 `json:"rating"`
+	Position int     `json:"position"`
+	Note     string  `json:"note"`
+	AddedAt  string  `json:"added_at"`
+}
+
+// ListUpdate changes the fields that are set; Public set to true shares the list.
+type ListUpdate struct {
+	Name   string `json:"name,omitempty"`
+	Public *bool  `json:"public,omitempty"`
+}
+
+type ListFilmInput struct {
+	FilmID   string `json:"film_id"`
+	Position int    `json:"position,omitempty"`
+	Note     string `json:"note,omitempty"`
+}
+
+// ListFilmUpdate moves a film of a list to Position and changes its note, for the fields that are set;
+// an empty Note clears it.
+type ListFilmUpdate struct {
+	Position int     `json:"position,omitempty"`
+	Note     *string `json:"note,omitempty"`
+}
+
+// UsersService works with the data of the user the client authenticates as. Lists are referred to
+// by id or by kind (watchlist, favourites, seen).
+type UsersService struct {
+	client *Client
+}
+
+func (s *UsersService) Lists(ctx context.Context) ([]List, error) {
+	var lists []List
+	err := s.client.do(ctx, http.MethodGet, "/users/me/lists", nil, nil, &lists)
+
+	return lists, err
+}
+
+func (s *UsersService) List(ctx context.Context, ref string) (List, error) {
+	var list List
+	err := s.client.do(ctx, http.MethodGet, listPath(ref), nil, nil, &list)
+
+	return list, err
+}
+
+func (s *UsersService) CreateList(ctx context.Context, name string) (List, error) {
+	var list List
+	err := s.client.do(ctx, http.MethodPost, "/users/me/lists", nil, map[string]string{"name": name}, &list)
+
+	return list, err
+}
+
+func (s *UsersService) UpdateList(ctx context.Context, ref string, input ListUpdate) (List, error) {
+	var list List
+	err := s.client.do(ctx, http.MethodPatch, listPath(ref), nil, input, &list)
+
+	return list, err
+}
+
+func (s *UsersService) DeleteList(ctx context.Context, ref string) error {
+	return s.client.do(ctx, http.MethodDelete, listPath(ref), nil, nil, nil)
+}
+
+func (s *UsersService) AddFilm(ctx context.Context, ref string, input ListFilmInput) error {
+	return s.client.do(ctx, http.MethodPost, listPath(ref)+"/films", nil, input, nil)
+}
+
+func (s *UsersService) UpdateFilm(ctx context.Context, ref string, filmId string, input ListFilmUpdate) error {
+	return s.client.do(ctx, http.MethodPatch, listPath(ref)+"/films/"+url.PathEscape(filmId), nil, input, nil)
+}
+
+func (s *UsersService) RemoveFilm(ctx context.Context, ref string, filmId string) error {
+	return s.client.do(ctx, http.MethodDelete, listPath(ref)+"/films/"+url.PathEscape(filmId), nil, nil, nil)
+}
+
+// SharedList returns a public list of any user by its share token; it needs no credentials.
+func (s *UsersService) SharedList(ctx context.Context, token string) (List, error) {
+	var list List
+	err := s.client.do(ctx, http.MethodGet, "/lists/shared/"+url.PathEscape(token), nil, nil, &list)
+
+	return list, err
+}
+
+func listPath(ref string) string {
+	return "/users/me/lists/" + url.PathEscape(ref)
+}
@@ -0,0 +1,171 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"sync/atomic"
+	"time"
+	"vk-test-spring/pkg/logger"
+)
+
+// Cluster routes queries between the primary pool and an optional replica pool. Writes always use
+// the primary. Reads use the replica while it is healthy, unless the request has written before
+// or asked for primary reads, see WithReadYourWrites.
+type Cluster struct {
+	primary        *pgxpool.Pool
+	replica        *pgxpool.Pool
+	replicaHealthy atomic.Bool
+	checkInterval  time.Duration
+	maxLag         time.Duration
+}
+
+// NewCluster returns a cluster over primary and replica. replica may be nil, then every query goes
+// to the primary. The replica only receives reads once RunHealthChecks has found it healthy.
+func NewCluster(primary *pgxpool.Pool, replica *pgxpool.Pool, checkInterval time.Duration, maxLag time.Duration) *Cluster {
+	return &Cluster{
+		primary:       primary,
+		replica:       replica,
+		checkInterval: checkInterval,
+		maxLag:        maxLag,
+	}
+}
+
+func (c *Cluster) Primary() *pgxpool.Pool {
+	return c.primary
+}
+
+// Replica returns the replica pool, or nil when none is configured.
+func (c *Cluster) Replica() *pgxpool.Pool {
+	return c.replica
+}
+
+// Reader returns the pool for read-only queries made with ctx.
+func (c *Cluster) Reader(ctx context.Context) *pgxpool.Pool {
+	if c.replica == nil || !c.replicaHealthy.Load() || ReadsPrimary(ctx) {
+		return c.primary
+	}
+
+	if read, ok := ctx.Value(replicaReadsKey{}).(*atomic.Bool); ok {
+		read.Store(true)
+	}
+
+	return c.replica
+}
+
+// Writer returns the primary pool and marks ctx, so later reads of the same request see the write.
+func (c *Cluster) Writer(ctx context.Context) *pgxpool.Pool {
+	markWritten(ctx)
+	return c.primary
+}
+
+func (c *Cluster) ReplicaHealthy() bool {
+	return c.replica != nil && c.replicaHealthy.Load()
+}
+
+// RunHealthChecks checks the replica every check interval until ctx is done. A replica that does
+// not answer or lags behind the primary by more than the allowed lag is taken out of rotation
+// until it recovers. State changes are logged through the logger of ctx.
+func (c *Cluster) RunHealthChecks(ctx context.Context) {
+	if c.replica == nil {
+		return
+	}
+
+	ticker := time.NewTicker(c.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		err := c.checkReplica(ctx)
+		healthy := err == nil
+
+		if c.replicaHealthy.Swap(healthy) != healthy {
+			if healthy {
+				logger.FromContext(ctx).Info().Msg("replica is healthy, routing reads to it")
+			} else {
+				logger.FromContext(ctx).Warn().Err(err).Msg("replica is unhealthy, routing reads to the primary")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Cluster) checkReplica(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.checkInterval)
+	defer cancel()
+
+	if err := c.replica.Ping(ctx); err != nil {
+		return err
+	}
+
+	if c.maxLag <= 0 {
+		return nil
+	}
+
+	// The replay timestamp only moves when the primary commits, so an idle primary looks like lag.
+	// Replicas whose WAL is fully replayed are therefore treated as up to date.
+	var lag float64
+	err := c.replica.QueryRow(ctx, `SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END::float8`).Scan(&lag)
+	if err != nil {
+		return err
+	}
+
+	if lagDuration := time.Duration(lag * float64(time.Second)); lagDuration > c.maxLag {
+		return fmt.Errorf("replica lags by %v, allowed %v", lagDuration.Round(time.Millisecond), c.maxLag)
+	}
+
+	return nil
+}
+
+// Close closes both pools.
+func (c *Cluster) Close() {
+	if c.replica != nil {
+		c.replica.Close()
+	}
+
+	c.primary.Close()
+}
+
+type consistencyKey struct{}
+
+type consistency struct {
+	primary atomic.Bool
+}
+
+// WithReadYourWrites prepares ctx for one request: once the request writes through Writer, its
+// later reads go to the primary. primary set to true sends all reads of the request there from
+// the start, for clients that need to read what they wrote in an earlier request.
+func WithReadYourWrites(ctx context.Context, primary bool) context.Context {
+	state := &consistency{}
+	state.primary.Store(primary)
+
+	return context.WithValue(ctx, consistencyKey{}, state)
+}
+
+func markWritten(ctx context.Context) {
+	if state, ok := ctx.Value(consistencyKey{}).(*consistency); ok {
+		state.primary.Store(true)
+	}
+}
+
+// ReadsPrimary reports whether every read made with ctx goes to the primary, see WithReadYourWrites.
+func ReadsPrimary(ctx context.Context) bool {
+	state, ok := ctx.Value(consistencyKey{}).(*consistency)
+	return ok && state.primary.Load()
+}
+
+type replicaReadsKey struct{}
+
+// TrackReplicaReads returns a ctx whose reads are recorded and a func that reports whether any of
+// them went to the replica. Data read from the replica may predate writes already committed.
+func TrackReplicaReads(ctx context.Context) (context.Context, func() bool) {
+	read := &atomic.Bool{}
+	return context.WithValue(ctx, replicaReadsKey{}, read), read.Load
+}
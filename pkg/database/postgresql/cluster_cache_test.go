@@ -0,0 +1,117 @@
+package postgresql_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"vk-test-spring/internal/models"
+	"vk-test-spring/internal/service"
+	"vk-test-spring/pkg/cache"
+	database "vk-test-spring/pkg/database/postgresql"
+)
+
+// replicatedFilms serves films from whichever pool the cluster picks. The replica lags: it only
+// sees a film, and the change time of the library, as they were before the last edit until catchUp.
+type replicatedFilms struct {
+	service.Films
+	cluster        *database.Cluster
+	primary        models.Film
+	replica        models.Film
+	primaryChanged time.Time
+	replicaChanged time.Time
+	reads          int
+}
+
+func (f *replicatedFilms) EditFilm(ctx context.Context, input service.FilmUpdateInput) error {
+	f.cluster.Writer(ctx)
+	f.primary.Name = input.FilmInfo.Name
+	f.primaryChanged = f.primaryChanged.Add(time.Second)
+	return nil
+}
+
+func (f *replicatedFilms) GetFilmById(ctx context.Context, _ uuid.UUID) (models.Film, error) {
+	f.reads++
+	if f.cluster.Reader(ctx) == f.cluster.Replica() {
+		return f.replica, nil
+	}
+
+	return f.primary, nil
+}
+
+func (f *replicatedFilms) LastModified(ctx context.Context) (time.Time, error) {
+	if f.cluster.Reader(ctx) == f.cluster.Replica() {
+		return f.replicaChanged, nil
+	}
+
+	return f.primaryChanged, nil
+}
+
+func (f *replicatedFilms) catchUp() {
+	f.replica, f.replicaChanged = f.primary, f.primaryChanged
+}
+
+func TestClusterWithCache(t *testing.T) {
+	pool := func(host string) *pgxpool.Pool {
+		p, err := pgxpool.New(context.Background(), "postgresql://postgres@"+host+":5432/films_library")
+		require.NoError(t, err)
+		t.Cleanup(p.Close)
+		return p
+	}
+
+	cluster := database.NewCluster(pool("primary.invalid"), pool("replica.invalid"), time.Second, 0)
+	cluster.SetReplicaHealthy(true)
+
+	film := models.Film{ID: uuid.New(), Name: "Брат"}
+	changed := time.Now()
+	films := &replicatedFilms{cluster: cluster, primary: film, replica: film, primaryChanged: changed, replicaChanged: changed}
+	cached := service.NewCachedFilmsService(films, cache.NewLRU(10, time.Minute), new(service.WriteMark))
+
+	request := func(primary bool) context.Context {
+		return database.WithReadYourWrites(context.Background(), primary)
+	}
+	name := func(ctx context.Context) string {
+		f, err := cached.GetFilmById(ctx, film.ID)
+		require.NoError(t, err)
+		return f.Name
+	}
+
+	t.Run("replica reads are cached", func(t *testing.T) {
+		assert.Equal(t, "Брат", name(request(false)))
+		assert.Equal(t, "Брат", name(request(false)))
+		assert.Equal(t, 1, films.reads)
+	})
+
+	t.Run("writes are read back", func(t *testing.T) {
+		ctx := request(false)
+		require.NoError(t, cached.EditFilm(ctx, service.FilmUpdateInput{ID: film.ID, FilmInfo: service.FilmInfo{Name: "Брат 2"}}))
+
+		assert.Equal(t, "Брат 2", name(ctx))
+	})
+
+	t.Run("reads of a lagging replica are not cached", func(t *testing.T) {
+		require.NoError(t, cached.EditFilm(request(false), service.FilmUpdateInput{ID: film.ID, FilmInfo: service.FilmInfo{Name: "Брат 3"}}))
+		reads := films.reads
+
+		assert.Equal(t, "Брат", name(request(false)))
+		assert.Equal(t, "Брат", name(request(false)))
+		assert.Equal(t, reads+2, films.reads)
+	})
+
+	t.Run("replica reads are cached once it caught up", func(t *testing.T) {
+		films.catchUp()
+		reads := films.reads
+
+		assert.Equal(t, "Брат 3", name(request(false)))
+		assert.Equal(t, "Брат 3", name(request(false)))
+		assert.Equal(t, reads+1, films.reads)
+	})
+
+	t.Run("primary reads skip cached values", func(t *testing.T) {
+		films.primary.Name = "Брат 4"
+		assert.Equal(t, "Брат 4", name(request(true)))
+	})
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newLazyPool(t *testing.T, host string) *pgxpool.Pool {
+	cfg, err := pgxpool.ParseConfig("postgresql://postgres@" + host + ":5432/films_library")
+	require.NoError(t, err)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+func TestClusterRouting(t *testing.T) {
+	primary := newLazyPool(t, "primary.invalid")
+	replica := newLazyPool(t, "replica.invalid")
+
+	t.Run("without replica", func(t *testing.T) {
+		c := NewCluster(primary, nil, time.Second, 0)
+
+		assert.Same(t, primary, c.Reader(context.Background()))
+		assert.False(t, c.ReplicaHealthy())
+	})
+
+	t.Run("replica not checked yet", func(t *testing.T) {
+		c := NewCluster(primary, replica, time.Second, 0)
+
+		assert.Same(t, primary, c.Reader(context.Background()))
+	})
+
+	t.Run("healthy replica serves reads", func(t *testing.T) {
+		c := NewCluster(primary, replica, time.Second, 0)
+		c.replicaHealthy.Store(true)
+
+		assert.Same(t, replica, c.Reader(context.Background()))
+		assert.Same(t, primary, c.Writer(context.Background()))
+	})
+
+	t.Run("reads after a write go to the primary", func(t *testing.T) {
+		c := NewCluster(primary, replica, time.Second, 0)
+		c.replicaHealthy.Store(true)
+
+		ctx := WithReadYourWrites(context.Background(), false)
+		assert.Same(t, replica, c.Reader(ctx))
+
+		c.Writer(ctx)
+		assert.Same(t, primary, c.Reader(ctx))
+
+		assert.Same(t, replica, c.Reader(WithReadYourWrites(context.Background(), false)))
+	})
+
+	t.Run("primary reads requested", func(t *testing.T) {
+		c := NewCluster(primary, replica, time.Second, 0)
+		c.replicaHealthy.Store(true)
+
+		assert.Same(t, primary, c.Reader(WithReadYourWrites(context.Background(), true)))
+	})
+
+	t.Run("unreachable replica is taken out", func(t *testing.T) {
+		c := NewCluster(primary, replica, 50*time.Millisecond, 0)
+		c.replicaHealthy.Store(true)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			c.RunHealthChecks(ctx)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return !c.ReplicaHealthy() }, 5*time.Second, 10*time.Millisecond)
+		assert.Same(t, primary, c.Reader(context.Background()))
+
+		cancel()
+		<-done
+	})
+}
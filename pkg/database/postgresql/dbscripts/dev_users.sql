@@ -0,0 +1,5 @@
+-- dev_users.sql adds the users admin/admin and user/user to a database built from public_schema.sql.
+-- For local development and tests only: never load it into a shared or production database.
+INSERT INTO users (name, password_hash, role) VALUES
+    ('admin', '$2a$10$Et/9IbyOeXnpcAiwbzfOFeSnY8NrjxHvlJO8b8VaMFy8Ib.FQozLm', 'администратор'),
+    ('user', '$2a$10$x45zWAl1RiQ8hphDL28a1.l6BDKP2hD9CdsysaoGme3DtHlxR7Iue', 'пользователь');
@@ -48,9 +48,233 @@ CREATE TYPE ROLES AS ENUM (
 CREATE TABLE users (
     id uuid NOT NULL DEFAULT uuid_generate_v1mc(),
     name varchar(50),
+    -- bcrypt hash of the password; users with an empty one cannot authenticate
+    password_hash varchar(60) NOT NULL DEFAULT '',
     role ROLES NOT NULL,
     CONSTRAINT users_pk PRIMARY KEY (id)
 );
 
-insert into users (name, role) values ('admin', 'администратор');
-insert into users (name, role) values ('user', 'пользователь');
\ No newline at end of file
+CREATE TYPE LIST_KIND AS ENUM (
+    'custom', 'watchlist', 'favourites', 'seen'
+);
+
+CREATE TABLE users_lists (
+    id uuid NOT NULL DEFAULT uuid_generate_v1mc(),
+    fk_user_id uuid NOT NULL,
+    name varchar(150) NOT NULL,
+    kind LIST_KIND NOT NULL DEFAULT 'custom',
+    share_token varchar(64),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT users_lists_pk PRIMARY KEY (id),
+    CONSTRAINT users_lists_share_token_uq UNIQUE (share_token),
+    FOREIGN KEY (fk_user_id) REFERENCES users(id) ON DELETE CASCADE ON UPDATE RESTRICT
+);
+
+CREATE UNIQUE INDEX users_lists_builtin_uq ON users_lists (fk_user_id, kind) WHERE kind <> 'custom';
+
+CREATE TABLE users_lists_films (
+    fk_list_id uuid NOT NULL,
+    fk_film_id uuid NOT NULL,
+    position integer NOT NULL,
+    note varchar(1000) NOT NULL DEFAULT '',
+    added_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (fk_list_id, fk_film_id),
+    FOREIGN KEY (fk_list_id) REFERENCES users_lists(id) ON DELETE CASCADE ON UPDATE RESTRICT,
+    FOREIGN KEY (fk_film_id) REFERENCES films(id) ON DELETE CASCADE ON UPDATE RESTRICT
+);
+
+CREATE INDEX users_lists_films_position_idx ON users_lists_films (fk_list_id, position);
+
+ALTER TABLE films ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', name), 'A') ||
+    setweight(to_tsvector('english', name), 'A') ||
+    setweight(to_tsvector('russian', description), 'B') ||
+    setweight(to_tsvector('english', description), 'B')
+) STORED;
+
+CREATE INDEX films_search_vector_idx ON films USING GIN (search_vector);
+
+ALTER TABLE actors ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('russian', f_name || ' ' || s_name || ' ' || patronymic) ||
+    to_tsvector('english', f_name || ' ' || s_name || ' ' || patronymic)
+) STORED;
+
+CREATE INDEX actors_search_vector_idx ON actors USING GIN (search_vector);
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+CREATE TABLE transliteration (
+    cyrillic varchar(1) NOT NULL,
+    latin varchar(4) NOT NULL,
+    CONSTRAINT transliteration_pk PRIMARY KEY (cyrillic)
+);
+
+INSERT INTO transliteration (cyrillic, latin) VALUES
+    ('а', 'a'), ('А', 'a'), ('б', 'b'), ('Б', 'b'), ('в', 'v'), ('В', 'v'),
+    ('г', 'g'), ('Г', 'g'), ('д', 'd'), ('Д', 'd'), ('е', 'e'), ('Е', 'e'),
+    ('ё', 'e'), ('Ё', 'e'), ('ж', 'zh'), ('Ж', 'zh'), ('з', 'z'), ('З', 'z'),
+    ('и', 'i'), ('И', 'i'), ('й', 'y'), ('Й', 'y'), ('к', 'k'), ('К', 'k'),
+    ('л', 'l'), ('Л', 'l'), ('м', 'm'), ('М', 'm'), ('н', 'n'), ('Н', 'n'),
+    ('о', 'o'), ('О', 'o'), ('п', 'p'), ('П', 'p'), ('р', 'r'), ('Р', 'r'),
+    ('с', 's'), ('С', 's'), ('т', 't'), ('Т', 't'), ('у', 'u'), ('У', 'u'),
+    ('ф', 'f'), ('Ф', 'f'), ('х', 'kh'), ('Х', 'kh'), ('ц', 'ts'), ('Ц', 'ts'),
+    ('ч', 'ch'), ('Ч', 'ch'), ('ш', 'sh'), ('Ш', 'sh'), ('щ', 'shch'), ('Щ', 'shch'),
+    ('ъ', ''), ('Ъ', ''), ('ы', 'y'), ('Ы', 'y'), ('ь', ''), ('Ь', ''),
+    ('э', 'e'), ('Э', 'e'), ('ю', 'yu'), ('Ю', 'yu'), ('я', 'ya'), ('Я', 'ya');
+
+CREATE FUNCTION to_latin(input text) RETURNS text
+    LANGUAGE sql STABLE AS $$
+    SELECT lower(string_agg(COALESCE(t.latin, c.ch), '' ORDER BY c.pos))
+    FROM unnest(string_to_array(input, NULL)) WITH ORDINALITY AS c(ch, pos)
+    LEFT JOIN transliteration AS t ON t.cyrillic = c.ch
+$$;
+
+ALTER TABLE films ADD COLUMN name_latin text NOT NULL DEFAULT '';
+ALTER TABLE actors ADD COLUMN name_latin text NOT NULL DEFAULT '';
+
+CREATE FUNCTION films_set_name_latin() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.name_latin := to_latin(NEW.name);
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER films_name_latin_trg BEFORE INSERT OR UPDATE OF name ON films
+    FOR EACH ROW EXECUTE FUNCTION films_set_name_latin();
+
+CREATE FUNCTION actors_set_name_latin() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.name_latin := to_latin(concat_ws(' ', NEW.f_name, NEW.s_name, NEW.patronymic));
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER actors_name_latin_trg BEFORE INSERT OR UPDATE OF f_name, s_name, patronymic ON actors
+    FOR EACH ROW EXECUTE FUNCTION actors_set_name_latin();
+
+UPDATE films SET name_latin = to_latin(name);
+UPDATE actors SET name_latin = to_latin(concat_ws(' ', f_name, s_name, patronymic));
+
+CREATE INDEX films_name_latin_trgm_idx ON films USING GIN (name_latin gin_trgm_ops);
+CREATE INDEX actors_name_latin_trgm_idx ON actors USING GIN (name_latin gin_trgm_ops);
+
+ALTER TABLE films ADD COLUMN popularity integer NOT NULL DEFAULT 0;
+
+CREATE FUNCTION films_update_popularity() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE films SET popularity = popularity + 1 WHERE id = NEW.fk_film_id;
+    ELSE
+        UPDATE films SET popularity = popularity - 1 WHERE id = OLD.fk_film_id;
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER films_popularity_trg AFTER INSERT OR DELETE ON users_lists_films
+    FOR EACH ROW EXECUTE FUNCTION films_update_popularity();
+
+UPDATE films SET popularity = (SELECT count(*) FROM users_lists_films WHERE fk_film_id = films.id);
+
+-- suggestions are matched by prefix of name_latin through the trigram indexes above
+CREATE INDEX films_popularity_rating_idx ON films (popularity DESC, rating DESC);
+
+ALTER TABLE films ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE actors ADD COLUMN updated_at timestamptz NOT NULL DEFAULT now();
+
+CREATE FUNCTION set_updated_at() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.updated_at := now();
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER films_updated_at_trg BEFORE UPDATE OF name, description, date, rating ON films
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER actors_updated_at_trg BEFORE UPDATE OF f_name, s_name, patronymic, birthday, sex ON actors
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- a film lists its cast and an actor lists its films, so changing a link modifies both records
+CREATE FUNCTION actors_films_touch() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+DECLARE
+    link actors_films%ROWTYPE;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        link := NEW;
+    ELSE
+        link := OLD;
+    END IF;
+    UPDATE films SET updated_at = now() WHERE id = link.fk_film_id;
+    UPDATE actors SET updated_at = now() WHERE id = link.fk_actor_id;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER actors_films_touch_trg AFTER INSERT OR DELETE ON actors_films
+    FOR EACH ROW EXECUTE FUNCTION actors_films_touch();
+
+CREATE FUNCTION films_touch_actors() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE actors SET updated_at = now()
+    WHERE id IN (SELECT fk_actor_id FROM actors_films WHERE fk_film_id = NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER films_touch_actors_trg AFTER UPDATE OF name ON films
+    FOR EACH ROW EXECUTE FUNCTION films_touch_actors();
+
+CREATE FUNCTION actors_touch_films() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE films SET updated_at = now()
+    WHERE id IN (SELECT fk_film_id FROM actors_films WHERE fk_actor_id = NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER actors_touch_films_trg AFTER UPDATE OF f_name, s_name, patronymic ON actors
+    FOR EACH ROW EXECUTE FUNCTION actors_touch_films();
+
+-- table_changes holds when each table was last written, deletes included, for the Last-Modified and ETag of lists.
+-- The time only moves forward, even for two statements within the same microsecond
+CREATE TABLE table_changes (
+    table_name text NOT NULL PRIMARY KEY,
+    changed_at timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO table_changes (table_name) VALUES ('films'), ('actors'), ('actors_films');
+
+CREATE FUNCTION table_changes_touch() RETURNS trigger
+    LANGUAGE plpgsql AS $$
+BEGIN
+    UPDATE table_changes SET changed_at = greatest(clock_timestamp(), changed_at + interval '1 microsecond')
+    WHERE table_name = TG_TABLE_NAME;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER films_changes_trg AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON films
+    FOR EACH STATEMENT EXECUTE FUNCTION table_changes_touch();
+
+CREATE TRIGGER actors_changes_trg AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON actors
+    FOR EACH STATEMENT EXECUTE FUNCTION table_changes_touch();
+
+CREATE TRIGGER actors_films_changes_trg AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON actors_films
+    FOR EACH STATEMENT EXECUTE FUNCTION table_changes_touch();
+
+-- schema_migrations records the revision of this script the database was built from, in the layout golang-migrate uses.
+-- bump the version here and postgresql.SchemaVersion together whenever the script changes
+CREATE TABLE schema_migrations (
+    version bigint NOT NULL PRIMARY KEY,
+    dirty boolean NOT NULL DEFAULT false
+);
+
+INSERT INTO schema_migrations (version) VALUES (4);
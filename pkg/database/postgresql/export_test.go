@@ -0,0 +1,5 @@
+package postgresql
+
+func (c *Cluster) SetReplicaHealthy(healthy bool) {
+	c.replicaHealthy.Store(healthy)
+}
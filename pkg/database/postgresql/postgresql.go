@@ -5,42 +5,196 @@ import (
 	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 	"vk-test-spring/internal/config"
+	"vk-test-spring/pkg/tracing"
 )
 
+// timeout bounds a single connection attempt.
 const timeout = 10 * time.Second
 
-func NewConnectionPool(cfg config.PostgreSQLConfig) *pgxpool.Pool {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+// SchemaVersion is the revision of dbscripts/public_schema.sql the code expects.
+// Bump it together with the INSERT INTO schema_migrations at the end of the script.
+const SchemaVersion = 4
 
-	pool, err := pgxpool.New(ctx, getConnectionString(cfg))
+// sslModes are the values libpq accepts. "disabled" is kept as an alias of "disable",
+// since older configs of this project used it.
+var sslModes = map[string]string{
+	"":            "prefer",
+	"disabled":    "disable",
+	"disable":     "disable",
+	"allow":       "allow",
+	"prefer":      "prefer",
+	"require":     "require",
+	"verify-ca":   "verify-ca",
+	"verify-full": "verify-full",
+}
+
+// NewConnectionPool opens a pool and pings it. Failed attempts are retried with exponential
+// backoff up to cfg.ConnectAttempts times; the last error is returned if all of them fail.
+func NewConnectionPool(ctx context.Context, cfg config.PostgreSQLConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := getPoolConfig(cfg)
 	if err != nil {
+		return nil, err
 	}
 
-	err = pool.Ping(context.Background())
+	var pool *pgxpool.Pool
+	err = retry(ctx, cfg, func(ctx context.Context) error {
+		pool, err = pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			return err
+		}
+
+		if err = pool.Ping(ctx); err != nil {
+			pool.Close()
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
+		return nil, err
 	}
 
-	return pool
+	return pool, nil
 }
 
-func NewConnection(cfg config.PostgreSQLConfig) *pgx.Conn {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+// NewReplicaPool opens a pool to the replica at cfg.Replica without waiting for it to answer,
+// so an unavailable replica does not block startup. Cluster health checks decide when it is used.
+func NewReplicaPool(cfg config.PostgreSQLConfig) (*pgxpool.Pool, error) {
+	cfg.Host = cfg.Replica.Host
+	cfg.Port = cfg.Replica.Port
+
+	poolConfig, err := getPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgxpool.NewWithConfig(context.Background(), poolConfig)
+}
 
-	conn, err := pgx.Connect(ctx, getConnectionString(cfg))
+func NewConnection(ctx context.Context, cfg config.PostgreSQLConfig) (*pgx.Conn, error) {
+	connConfig, err := pgx.ParseConfig(getConnectionString(cfg))
 	if err != nil {
+		return nil, err
 	}
 
-	err = conn.Ping(context.Background())
+	var conn *pgx.Conn
+	err = retry(ctx, cfg, func(ctx context.Context) error {
+		conn, err = pgx.ConnectConfig(ctx, connConfig)
+		if err != nil {
+			return err
+		}
+
+		if err = conn.Ping(ctx); err != nil {
+			conn.Close(ctx)
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
+		return nil, err
 	}
 
-	return conn
+	return conn, nil
 }
 
+func getPoolConfig(cfg config.PostgreSQLConfig) (*pgxpool.Config, error) {
+	if _, ok := sslModes[cfg.SSLMode]; !ok {
+		return nil, fmt.Errorf("postgresql.sslmode: unknown mode %q", cfg.SSLMode)
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(getConnectionString(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("postgresql: %w", err)
+	}
+
+	if cfg.MaxOpenConnections > 0 {
+		poolConfig.MaxConns = int32(cfg.MaxOpenConnections)
+	}
+
+	// pgxpool has no idle limit; MinConns keeps that many connections open and ready instead.
+	if cfg.MaxIdleConnections > 0 {
+		poolConfig.MinConns = min(int32(cfg.MaxIdleConnections), poolConfig.MaxConns)
+	}
+
+	if cfg.ConnectionMaxLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.ConnectionMaxLifetime
+	}
+
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	poolConfig.ConnConfig.ConnectTimeout = timeout
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
+
+	return poolConfig, nil
+}
+
+// retry calls connect until it succeeds, doubling the pause between attempts up to cfg.ConnectMaxBackoff.
+func retry(ctx context.Context, cfg config.PostgreSQLConfig, connect func(ctx context.Context) error) error {
+	attempts := max(cfg.ConnectAttempts, 1)
+	backoff := cfg.ConnectBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = connect(attemptCtx)
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("connecting to postgresql at %s: %w", net.JoinHostPort(cfg.Host, cfg.Port), ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if cfg.ConnectMaxBackoff > 0 && backoff > cfg.ConnectMaxBackoff {
+			backoff = cfg.ConnectMaxBackoff
+		}
+	}
+
+	return fmt.Errorf("connecting to postgresql at %s failed after %d attempts: %w",
+		net.JoinHostPort(cfg.Host, cfg.Port), attempts, err)
+}
+
+// getConnectionString builds a URL, so credentials with reserved characters such as @, : or / are escaped.
 func getConnectionString(cfg config.PostgreSQLConfig) string {
-	return fmt.Sprintf("postgresql://%v:%v@%v:%v/%v", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+	query := url.Values{}
+	query.Set("sslmode", sslModes[cfg.SSLMode])
+
+	if cfg.SSLRootCert != "" {
+		query.Set("sslrootcert", cfg.SSLRootCert)
+	}
+
+	if cfg.SSLCert != "" {
+		query.Set("sslcert", cfg.SSLCert)
+	}
+
+	if cfg.SSLKey != "" {
+		query.Set("sslkey", cfg.SSLKey)
+	}
+
+	u := url.URL{
+		Scheme:   "postgresql",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:     "/" + cfg.DBName,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
 }
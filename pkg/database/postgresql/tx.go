@@ -0,0 +1,120 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+type txKey struct{}
+
+// TxManager runs several repository calls in one transaction on the primary. The transaction is
+// carried by the context, so repositories join it through BeginTx instead of opening their own.
+type TxManager struct {
+	cluster    *Cluster
+	isolation  pgx.TxIsoLevel
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewTxManager returns a manager opening transactions with the given isolation level. A transaction
+// failing with a serialization failure or a deadlock is run again up to maxRetries times, waiting
+// backoff, doubled after every attempt, in between.
+func NewTxManager(cluster *Cluster, isolation string, maxRetries int, backoff time.Duration) *TxManager {
+	return &TxManager{
+		cluster:    cluster,
+		isolation:  pgx.TxIsoLevel(isolation),
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+}
+
+// WithinTx runs fn in a transaction and commits it when fn returns nil. Called inside another
+// WithinTx it runs fn in a savepoint of the outer transaction, so a failed fn only undoes its own
+// changes; retries are left to the outermost call, which owns the transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return m.run(ctx, fn)
+	}
+
+	backoff := m.backoff
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || attempt >= m.maxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	var tx pgx.Tx
+	var err error
+
+	if outer, ok := txFromContext(ctx); ok {
+		tx, err = outer.Begin(ctx)
+	} else {
+		tx, err = m.cluster.Writer(ctx).BeginTx(ctx, pgx.TxOptions{IsoLevel: m.isolation})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// IsRetryable reports whether err is a serialization failure or a deadlock, after which the whole
+// transaction may succeed when run again.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == serializationFailure || pgErr.Code == deadlockDetected
+}
+
+// BeginTx starts a transaction on pool, or a savepoint when ctx carries a transaction of TxManager.
+// Committing the savepoint releases it; the changes are committed with the outer transaction.
+func BeginTx(ctx context.Context, pool *pgxpool.Pool, opts pgx.TxOptions) (pgx.Tx, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.Begin(ctx)
+	}
+
+	return pool.BeginTx(ctx, opts)
+}
+
+// BeginWrite starts a transaction on the primary, see BeginTx.
+func (c *Cluster) BeginWrite(ctx context.Context) (pgx.Tx, error) {
+	return BeginTx(ctx, c.Writer(ctx), pgx.TxOptions{})
+}
+
+// BeginRead starts a read-only transaction on the reader pool. Inside a transaction of TxManager
+// it reads through that transaction instead, to see its uncommitted changes.
+func (c *Cluster) BeginRead(ctx context.Context) (pgx.Tx, error) {
+	return BeginTx(ctx, c.Reader(ctx), pgx.TxOptions{AccessMode: pgx.ReadOnly})
+}
+
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
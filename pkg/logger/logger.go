@@ -2,27 +2,144 @@ package logger
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/rs/zerolog"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"io"
 	"net"
 	"net/http"
+	"os"
+	"time"
 )
 
 //type Logs struct {
 //	Logger zerolog.Logger
 //}
 
-func InitLogs(filename string, maxSize int, maxBackups int, maxAge int) zerolog.Logger {
-	z := zerolog.New(&lumberjack.Logger{
-		Filename:   filename,
-		MaxSize:    maxSize,
-		MaxBackups: maxBackups,
-		MaxAge:     maxAge,
-		Compress:   true,
+// Log outputs and formats supported by InitLogs.
+const (
+	OutputFile   = "file"
+	OutputStdout = "stdout"
+	OutputBoth   = "both"
+
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Options select the level (trace, debug, info, warn, error), the format (FormatJSON or FormatConsole)
+// and the output (OutputFile, OutputStdout or OutputBoth) of the logger.
+type Options struct {
+	Level  string
+	Format string
+	Output string
+	File   FileOptions
+}
+
+// FileOptions are passed to lumberjack. MaxSize is in megabytes, MaxAge in days.
+type FileOptions struct {
+	Path       string
+	MaxSize    int
+	MaxBackups int
+	MaxAge     int
+	Compress   bool
+}
+
+// InitLogs builds the application logger from opts and sets the global level. The logger itself
+// accepts every level, so SetLevel can later raise or lower verbosity without rebuilding it.
+func InitLogs(opts Options) (zerolog.Logger, error) {
+	level, err := zerolog.ParseLevel(opts.Level)
+	if err != nil || level == zerolog.NoLevel {
+		return zerolog.Logger{}, fmt.Errorf("logger.level: unknown level %q", opts.Level)
+	}
+
+	var out io.Writer
+	switch opts.Output {
+	case OutputFile:
+		out = newFileWriter(opts.File)
+	case OutputStdout:
+		out = os.Stdout
+	case OutputBoth:
+		out = io.MultiWriter(os.Stdout, newFileWriter(opts.File))
+	default:
+		return zerolog.Logger{}, fmt.Errorf("logger.output: unknown output %q", opts.Output)
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+	case FormatConsole:
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339, NoColor: opts.Output != OutputStdout}
+	default:
+		return zerolog.Logger{}, fmt.Errorf("logger.format: unknown format %q", opts.Format)
+	}
+
+	zerolog.SetGlobalLevel(level)
+
+	return zerolog.New(out).Level(zerolog.TraceLevel).With().Caller().Timestamp().Logger(), nil
+}
+
+func newFileWriter(opts FileOptions) io.Writer {
+	return &lumberjack.Logger{
+		Filename:   opts.Path,
+		MaxSize:    opts.MaxSize,
+		MaxBackups: opts.MaxBackups,
+		MaxAge:     opts.MaxAge,
+		Compress:   opts.Compress,
+	}
+}
+
+// SetLevel changes the level of every logger at runtime.
+func SetLevel(level string) error {
+	l, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	if l == zerolog.NoLevel {
+		return fmt.Errorf("empty log level")
+	}
+
+	zerolog.SetGlobalLevel(l)
+	return nil
+}
+
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler reports the current level on GET and changes it on PUT with a {"level": "debug"} body.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, "error while decoding request body", http.StatusBadRequest)
+				return
+			}
+
+			if err := SetLevel(body.Level); err != nil {
+				http.Error(w, fmt.Sprintf("unknown log level: %q", body.Level), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(levelBody{Level: zerolog.GlobalLevel().String()})
 	})
+}
 
-	return z.With().Caller().Timestamp().Logger()
+// FromContext returns the request scoped logger stored by the HTTP middleware. It already carries
+// the request_id field, so services and repositories should log through it rather than a global logger.
+// Outside of a request it returns a disabled logger.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx)
 }
 
 func NewWrapResponseWriter(w http.ResponseWriter, protoMajor int) WrapResponseWriter {
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitLogs(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+
+	t.Run("file output", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "app.log")
+
+		logs, err := InitLogs(Options{
+			Level:  "warn",
+			Format: FormatJSON,
+			Output: OutputFile,
+			File:   FileOptions{Path: path, MaxSize: 1},
+		})
+		require.NoError(t, err)
+
+		logs.Info().Msg("hidden")
+		logs.Warn().Msg("shown")
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "hidden")
+		assert.Contains(t, string(data), `"message":"shown"`)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		valid := Options{Level: "info", Format: FormatJSON, Output: OutputStdout}
+
+		cfg := valid
+		cfg.Level = "loud"
+		_, err := InitLogs(cfg)
+		assert.ErrorContains(t, err, "logger.level")
+
+		cfg = valid
+		cfg.Format = "xml"
+		_, err = InitLogs(cfg)
+		assert.ErrorContains(t, err, "logger.format")
+
+		cfg = valid
+		cfg.Output = "syslog"
+		_, err = InitLogs(cfg)
+		assert.ErrorContains(t, err, "logger.output")
+	})
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		expectedCode int
+		expectedBody string
+		level        zerolog.Level
+	}{
+		{"get", http.MethodGet, "", http.StatusOK, `{"level":"info"}`, zerolog.InfoLevel},
+		{"set", http.MethodPut, `{"level":"debug"}`, http.StatusOK, `{"level":"debug"}`, zerolog.DebugLevel},
+		{"unknown level", http.MethodPut, `{"level":"loud"}`, http.StatusBadRequest, "", zerolog.DebugLevel},
+		{"empty level", http.MethodPut, `{}`, http.StatusBadRequest, "", zerolog.DebugLevel},
+		{"wrong method", http.MethodDelete, "", http.StatusMethodNotAllowed, "", zerolog.DebugLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			LevelHandler().ServeHTTP(w, httptest.NewRequest(tt.method, "/log/level", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			assert.Equal(t, tt.level, zerolog.GlobalLevel())
+		})
+	}
+}
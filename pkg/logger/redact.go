@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/rs/zerolog"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	redacted = "[REDACTED]"
+
+	// maxInspectedBody bounds how much of a body is buffered for redaction. Larger bodies are never logged,
+	// because a body that cannot be inspected as a whole cannot be redacted reliably.
+	maxInspectedBody = 1 << 20
+)
+
+// formKeyRe matches the field names of a form body. Keys with other characters mean the body is
+// not a form, e.g. broken JSON, which url.ParseQuery would otherwise accept.
+var formKeyRe = regexp.MustCompile(`^[\w.\-\[\]]+$`)
+
+// Built-in rules. They are always applied, configured rules are added on top of them.
+var (
+	defaultRedactedHeaders     = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+	defaultRedactedQueryParams = []string{"password", "token", "api_key", "access_token"}
+	defaultRedactedBodyFields  = []string{"password", "token", "secret", "share_token", "access_token", "refresh_token"}
+)
+
+// Redactor turns a request into a log field with credentials masked and the body capped.
+type Redactor struct {
+	headers     map[string]bool
+	queryParams map[string]bool
+	bodyFields  map[string]bool
+	maxBodySize int
+	sampleRate  float64
+	random      func() float64
+}
+
+// RequestOptions control what the request log keeps. The Redact* lists add names to the built-in rules.
+// Bodies are cut to MaxBodySize bytes (0 disables body logging); FullBodySampleRate is the share
+// of requests whose body is logged without the cut.
+type RequestOptions struct {
+	RedactHeaders      []string
+	RedactQueryParams  []string
+	RedactBodyFields   []string
+	MaxBodySize        int
+	FullBodySampleRate float64
+}
+
+func NewRedactor(opts RequestOptions) *Redactor {
+	r := &Redactor{
+		headers:     make(map[string]bool),
+		queryParams: make(map[string]bool),
+		bodyFields:  make(map[string]bool),
+		maxBodySize: opts.MaxBodySize,
+		sampleRate:  opts.FullBodySampleRate,
+		random:      rand.Float64,
+	}
+
+	for _, h := range append(defaultRedactedHeaders, opts.RedactHeaders...) {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+
+	for _, p := range append(defaultRedactedQueryParams, opts.RedactQueryParams...) {
+		r.queryParams[strings.ToLower(p)] = true
+	}
+
+	for _, f := range append(defaultRedactedBodyFields, opts.RedactBodyFields...) {
+		r.bodyFields[strings.ToLower(f)] = true
+	}
+
+	return r
+}
+
+// Request describes req for the request log. The body is read ahead and put back,
+// so the handler still receives it unchanged.
+func (r *Redactor) Request(req *http.Request) *zerolog.Event {
+	headers := zerolog.Dict()
+	for name, values := range req.Header {
+		if r.headers[http.CanonicalHeaderKey(name)] {
+			headers.Str(name, redacted)
+			continue
+		}
+
+		headers.Str(name, strings.Join(values, ", "))
+	}
+
+	u := *req.URL
+	u.RawQuery = r.redactValues(u.Query()).Encode()
+
+	event := zerolog.Dict().
+		Str("method", req.Method).
+		Str("url", u.RequestURI()).
+		Str("proto", req.Proto).
+		Dict("headers", headers)
+
+	return r.body(event, req)
+}
+
+func (r *Redactor) body(event *zerolog.Event, req *http.Request) *zerolog.Event {
+	if req.Body == nil || req.Body == http.NoBody || r.maxBodySize <= 0 {
+		return event
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxInspectedBody+1))
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), req.Body), Closer: req.Body}
+	if err != nil {
+		return event.Str("body", "[unreadable]")
+	}
+
+	if len(data) == 0 {
+		return event
+	}
+
+	if len(data) > maxInspectedBody {
+		return event.Str("body", "[omitted: too large]")
+	}
+
+	body, ok := r.redactBody(data)
+	if !ok {
+		return event.Int("body_size", len(data)).Str("body", "[omitted: cannot be redacted]")
+	}
+
+	event = event.Int("body_size", len(data))
+	if len(body) > r.maxBodySize && !r.sampled() {
+		return event.Bool("body_truncated", true).Str("body", string(body[:r.maxBodySize]))
+	}
+
+	return event.Str("body", string(body))
+}
+
+// redactBody masks configured fields of JSON and form bodies. The Content-Type is not trusted, the
+// handlers decode JSON whatever it says, so the body is tried as JSON and then as a form. A form
+// without a field to mask is returned as is. ok is false when the body is neither, as it cannot be
+// told apart from a body that holds credentials.
+func (r *Redactor) redactBody(data []byte) ([]byte, bool) {
+	var v any
+	if err := json.Unmarshal(data, &v); err == nil {
+		out, err := json.Marshal(r.redactJSON(v))
+		return out, err == nil
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return nil, false
+	}
+
+	masked := false
+	for k := range values {
+		if !formKeyRe.MatchString(k) {
+			return nil, false
+		}
+		masked = masked || r.queryParams[strings.ToLower(k)] || r.bodyFields[strings.ToLower(k)]
+	}
+	if !masked {
+		return data, true
+	}
+
+	return []byte(r.redactValues(values).Encode()), true
+}
+
+func (r *Redactor) redactJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if r.bodyFields[strings.ToLower(k)] {
+				t[k] = redacted
+				continue
+			}
+
+			t[k] = r.redactJSON(val)
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = r.redactJSON(val)
+		}
+	}
+
+	return v
+}
+
+func (r *Redactor) redactValues(values url.Values) url.Values {
+	for k := range values {
+		if r.queryParams[strings.ToLower(k)] || r.bodyFields[strings.ToLower(k)] {
+			values[k] = []string{redacted}
+		}
+	}
+
+	return values
+}
+
+// sampled reports whether this request is picked for full, untruncated body capture.
+func (r *Redactor) sampled() bool {
+	return r.sampleRate > 0 && r.random() < r.sampleRate
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func logRequest(t *testing.T, redactor *Redactor, r *http.Request) map[string]any {
+	var buf bytes.Buffer
+	l := zerolog.New(&buf)
+	l.Log().Dict("request", redactor.Request(r)).Send()
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+
+	return line["request"].(map[string]any)
+}
+
+func TestRedactor(t *testing.T) {
+	t.Run("default rules mask credentials", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{MaxBodySize: 1024})
+
+		r := httptest.NewRequest(http.MethodPost, "/users?token=abc&name=ivan",
+			strings.NewReader(`{"username":"ivan","password":"qwerty","profile":{"secret":"s"}}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.SetBasicAuth("ivan", "qwerty")
+
+		logged := logRequest(t, redactor, r)
+		out, _ := json.Marshal(logged)
+
+		assert.NotContains(t, string(out), "qwerty")
+		assert.NotContains(t, string(out), "abc")
+		assert.Equal(t, redacted, logged["headers"].(map[string]any)["Authorization"])
+		assert.Contains(t, logged["url"], "name=ivan")
+		assert.JSONEq(t, `{"username":"ivan","password":"[REDACTED]","profile":{"secret":"[REDACTED]"}}`, logged["body"].(string))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "qwerty")
+	})
+
+	t.Run("configured rules", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{
+			RedactHeaders:     []string{"x-session"},
+			RedactQueryParams: []string{"email"},
+			RedactBodyFields:  []string{"Note"},
+			MaxBodySize:       1024,
+		})
+
+		r := httptest.NewRequest(http.MethodPost, "/lists?email=a@b.c", strings.NewReader(`[{"note":"private"}]`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("X-Session", "sid")
+
+		logged := logRequest(t, redactor, r)
+
+		assert.Equal(t, redacted, logged["headers"].(map[string]any)["X-Session"])
+		assert.NotContains(t, logged["url"], "a@b.c")
+		assert.JSONEq(t, `[{"note":"[REDACTED]"}]`, logged["body"].(string))
+	})
+
+	t.Run("broken json is not logged", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{MaxBodySize: 1024})
+
+		r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"password":"qwerty"`))
+		r.Header.Set("Content-Type", "application/json")
+
+		logged := logRequest(t, redactor, r)
+
+		assert.NotContains(t, logged["body"], "qwerty")
+	})
+
+	t.Run("json is redacted whatever the content type", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{MaxBodySize: 1024})
+
+		for _, contentType := range []string{"", "text/plain"} {
+			r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"ivan","password":"qwerty"}`))
+			if contentType != "" {
+				r.Header.Set("Content-Type", contentType)
+			}
+
+			logged := logRequest(t, redactor, r)
+
+			assert.JSONEq(t, `{"name":"ivan","password":"[REDACTED]"}`, logged["body"].(string), contentType)
+		}
+	})
+
+	t.Run("forms are redacted", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{MaxBodySize: 1024})
+
+		logged := logRequest(t, redactor, httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("name=ivan&password=qwerty")))
+
+		assert.Equal(t, "name=ivan&password=%5BREDACTED%5D", logged["body"])
+	})
+
+	t.Run("bodies that cannot be redacted are omitted", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{MaxBodySize: 1024})
+
+		for _, body := range []string{`{"password":"qwerty"`, "password qwerty", "%zz"} {
+			logged := logRequest(t, redactor, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+
+			assert.Equal(t, "[omitted: cannot be redacted]", logged["body"], body)
+		}
+	})
+
+	t.Run("body size cap and sampling", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{MaxBodySize: 4, FullBodySampleRate: 0.5})
+		body := "0123456789"
+
+		redactor.random = func() float64 { return 0.9 }
+		logged := logRequest(t, redactor, httptest.NewRequest(http.MethodPost, "/films", strings.NewReader(body)))
+		assert.Equal(t, "0123", logged["body"])
+		assert.Equal(t, true, logged["body_truncated"])
+
+		redactor.random = func() float64 { return 0.1 }
+		logged = logRequest(t, redactor, httptest.NewRequest(http.MethodPost, "/films", strings.NewReader(body)))
+		assert.Equal(t, body, logged["body"])
+	})
+
+	t.Run("bodies disabled", func(t *testing.T) {
+		redactor := NewRedactor(RequestOptions{})
+
+		logged := logRequest(t, redactor, httptest.NewRequest(http.MethodPost, "/films", strings.NewReader("data")))
+		assert.NotContains(t, logged, "body")
+	})
+}
@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"regexp"
+)
+
+// Header carries the request identifier in both directions.
+const Header = "X-Request-ID"
+
+// validRe limits accepted identifiers to a length and alphabet that are safe to log and echo back.
+var validRe = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request identifier stored in ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Resolve returns the incoming identifier when it is well-formed, otherwise a freshly generated one.
+func Resolve(incoming string) string {
+	if validRe.MatchString(incoming) {
+		return incoming
+	}
+
+	return uuid.NewString()
+}
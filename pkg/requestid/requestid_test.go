@@ -0,0 +1,35 @@
+package requestid
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	t.Run("accepts incoming id", func(t *testing.T) {
+		assert.Equal(t, "req-42.abc:1", Resolve("req-42.abc:1"))
+	})
+
+	t.Run("generates id when missing", func(t *testing.T) {
+		_, err := uuid.Parse(Resolve(""))
+		assert.NoError(t, err)
+	})
+
+	t.Run("replaces malformed id", func(t *testing.T) {
+		for _, incoming := range []string{"id with spaces", "id\nX-Injected: 1", strings.Repeat("a", 129)} {
+			id := Resolve(incoming)
+			assert.NotEqual(t, incoming, id)
+
+			_, err := uuid.Parse(id)
+			assert.NoError(t, err)
+		}
+	})
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, "", FromContext(context.Background()))
+	assert.Equal(t, "abc", FromContext(NewContext(context.Background(), "abc")))
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"vk-test-spring/internal/config"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("file exporter", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "traces.json")
+
+		shutdown, err := Init(config.TracingConfig{
+			Enabled:     true,
+			ServiceName: "films-library-test",
+			Exporter:    ExporterFile,
+			File:        file,
+			SampleRatio: 1,
+		})
+		require.NoError(t, err)
+
+		ctx, parent := Start(context.Background(), "FilmsService.GetAllFilms")
+		_, child := Start(ctx, "FilmsRepo.GetAllFilms")
+		err = errors.New("connection refused")
+		End(child, &err)
+		parent.End()
+
+		require.NoError(t, shutdown(context.Background()))
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"Name":"FilmsService.GetAllFilms"`)
+		assert.Contains(t, string(data), `"Name":"FilmsRepo.GetAllFilms"`)
+		assert.Contains(t, string(data), "connection refused")
+		assert.Contains(t, string(data), parent.SpanContext().TraceID().String())
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := Init(config.TracingConfig{Enabled: true, Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		shutdown, err := Init(config.TracingConfig{Enabled: false, Exporter: "zipkin"})
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+}
@@ -0,0 +1,377 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"vk-test-spring/internal/config"
+	"vk-test-spring/internal/models"
+)
+
+const (
+	unknownId = "00000000-0000-4000-8000-000000000000"
+	actorBody = `{"name": "Сергей", "second_name": "Бодров", "patronymic": "Сергеевич", "sex": "Мужчина", "date_of_birth": "1971-12-27"}`
+	filmBody  = `{"name": "Брат", "description": "Демобилизованный Данила едет в Петербург", "date": "1997-12-12", "rating": 8.3, "actors": [%q]}`
+)
+
+type idOnly struct {
+	ID string `json:"id"`
+}
+
+// TestAPI runs the same requests over the memory storage and over PostgreSQL, when dsnVariable is set.
+func TestAPI(t *testing.T) {
+	for _, driver := range []string{config.StorageMemory, config.StoragePostgreSQL} {
+		t.Run(driver, func(t *testing.T) {
+			testAPI(t, newAPI(t, driver))
+		})
+	}
+}
+
+// testAPI runs requests against the whole stack in order, each step building on the state left
+// by the previous ones, and compares every response with its golden file.
+func testAPI(t *testing.T, a *api) {
+	var actorId, filmId string
+
+	t.Run("authentication", func(t *testing.T) {
+		t.Run("missing credentials", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films"})
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("wrong password", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films", user: "intruder"})
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("debug vars need an administrator", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/debug/vars", user: "user"})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assertGolden(t, res)
+
+			res = a.do(t, request{method: http.MethodGet, path: "/debug/vars", user: "admin"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		})
+	})
+
+	t.Run("empty library", func(t *testing.T) {
+		for _, path := range []string{"/films", "/actors"} {
+			res := a.do(t, request{method: http.MethodGet, path: path, user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode, path)
+			assert.JSONEq(t, `[]`, string(res.body), path)
+		}
+	})
+
+	t.Run("actors", func(t *testing.T) {
+		t.Run("create as user", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPost, path: "/actors", user: "user", body: actorBody})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("create with a malformed body", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPost, path: "/actors", user: "admin", body: `{`})
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("create", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPost, path: "/actors", user: "admin", body: actorBody})
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("list", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/actors", user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			var actors []idOnly
+			res.decode(t, &actors)
+			require.Len(t, actors, 1)
+			actorId = actors[0].ID
+		})
+
+		t.Run("search by name", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/actors?name=bodrov", user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("unknown method", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPut, path: "/actors", user: "admin", body: actorBody})
+			assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+			assertGolden(t, res)
+		})
+	})
+
+	t.Run("films", func(t *testing.T) {
+		t.Run("create as user", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPost, path: "/films", user: "user", body: fmt.Sprintf(filmBody, actorId)})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("create with a malformed body", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPost, path: "/films", user: "admin", body: `{`})
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("create", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPost, path: "/films", user: "admin", body: fmt.Sprintf(filmBody, actorId)})
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assertGolden(t, res)
+
+			res = a.do(t, request{method: http.MethodPost, path: "/films", user: "admin",
+				body: `{"name": "Асса", "description": "Бананан и Алика", "date": "1987-01-01", "rating": 7.1}`})
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+		})
+
+		t.Run("list", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films", user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			var films []idOnly
+			res.decode(t, &films)
+			require.Len(t, films, 2)
+			filmId = films[0].ID
+		})
+
+		t.Run("list sorted by name", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films?sort=name&order=asc", user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("list sorted by an unknown field", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films?sort=rating%20DESC,%20name&order=asc", user: "user"})
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("list as csv", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films?sort=name&order=asc", user: "user",
+				header: map[string]string{"Accept": "text/csv"}})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			json := a.do(t, request{method: http.MethodGet, path: "/films?sort=name&order=asc", user: "user"})
+			assert.NotEqual(t, json.Header.Get("ETag"), res.Header.Get("ETag"))
+			assert.Equal(t, []string{"Accept", "Authorization"}, res.Header.Values("Vary"))
+		})
+
+		t.Run("search by name", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films?name=brat", user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("search by actor", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films?actor-name=Бодров", user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("get", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films/" + filmId, user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			res = a.do(t, request{method: http.MethodGet, path: "/films/" + filmId, user: "user",
+				header: map[string]string{"If-None-Match": res.Header.Get("ETag")}})
+			assert.Equal(t, http.StatusNotModified, res.StatusCode)
+			assert.Empty(t, res.body)
+		})
+
+		t.Run("get as csv", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films/" + filmId, user: "user",
+				header: map[string]string{"Accept": "text/csv"}})
+			assert.Equal(t, http.StatusNotAcceptable, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("get unknown", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films/" + unknownId, user: "user"})
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("update as user", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPatch, path: "/films/" + filmId, user: "user", body: `{"rating": 9}`})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("update", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPatch, path: "/films/" + filmId, user: "admin", body: `{"rating": 9}`})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			res = a.do(t, request{method: http.MethodGet, path: "/films/" + filmId, user: "user"})
+			assert.Contains(t, string(res.body), `"rating":9`)
+		})
+
+		t.Run("unknown method", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPut, path: "/films/" + filmId, user: "admin"})
+			assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("unknown path", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/films/not-an-id", user: "user"})
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+			assertGolden(t, res)
+		})
+	})
+
+	t.Run("actor with films", func(t *testing.T) {
+		t.Run("get", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/actors/" + actorId, user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("update as user", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPatch, path: "/actors/" + actorId, user: "user", body: `{"sex": "Женщина"}`})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("update", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodPatch, path: "/actors/" + actorId, user: "admin", body: `{"films_to_del": ["` + filmId + `"]}`})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+		})
+	})
+
+	if a.driver == config.StoragePostgreSQL {
+		t.Run("lists", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/users/me/lists", user: "user"})
+			require.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			var lists []models.UserList
+			res.decode(t, &lists)
+			kinds := make([]string, 0, len(lists))
+			for _, l := range lists {
+				kinds = append(kinds, l.Kind)
+			}
+			assert.ElementsMatch(t, []string{models.ListKindWatchlist, models.ListKindFavourites, models.ListKindSeen}, kinds)
+
+			res = a.do(t, request{method: http.MethodPost, path: "/users/me/lists/watchlist/films", user: "user",
+				body: `{"film_id": "` + filmId + `", "note": "посмотреть"}`})
+			assert.Equal(t, http.StatusCreated, res.StatusCode, string(res.body))
+
+			res = a.do(t, request{method: http.MethodPatch, path: "/users/me/lists/watchlist/films/" + filmId, user: "user",
+				body: `{"note": "пересмотреть"}`})
+			assert.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			res = a.do(t, request{method: http.MethodPatch, path: "/users/me/lists/watchlist", user: "user", body: `{"public": true}`})
+			require.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			var watchlist models.UserList
+			res.decode(t, &watchlist)
+			require.NotEmpty(t, watchlist.ShareToken)
+
+			res = a.do(t, request{method: http.MethodGet, path: "/lists/shared/" + watchlist.ShareToken, user: "admin"})
+			require.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			var shared models.UserList
+			res.decode(t, &shared)
+			require.Len(t, shared.Films, 1)
+			assert.Equal(t, filmId, shared.Films[0].ID.String())
+			assert.Equal(t, "пересмотреть", shared.Films[0].Note)
+
+			res = a.do(t, request{method: http.MethodDelete, path: "/users/me/lists/watchlist/films/" + filmId, user: "user"})
+			assert.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			res = a.do(t, request{method: http.MethodGet, path: "/users/me/lists/watchlist", user: "user"})
+			require.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+			assert.NotContains(t, string(res.body), filmId)
+		})
+
+		t.Run("search", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodGet, path: "/search?q=Брат", user: "user"})
+			require.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			var page models.SearchPage
+			res.decode(t, &page)
+			require.NotEmpty(t, page.Items)
+			assert.Equal(t, "Брат", page.Items[0].Title)
+
+			res = a.do(t, request{method: http.MethodGet, path: "/suggest?q=Бр&type=films", user: "user"})
+			require.Equal(t, http.StatusOK, res.StatusCode, string(res.body))
+
+			var suggestions []models.Suggestion
+			res.decode(t, &suggestions)
+			require.NotEmpty(t, suggestions)
+			assert.Equal(t, "Брат", suggestions[0].Name)
+		})
+	}
+
+	t.Run("deletion", func(t *testing.T) {
+		t.Run("film as user", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodDelete, path: "/films/" + filmId, user: "user"})
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			assertGolden(t, res)
+		})
+
+		t.Run("film", func(t *testing.T) {
+			list := a.do(t, request{method: http.MethodGet, path: "/films", user: "user"})
+			require.Equal(t, http.StatusOK, list.StatusCode)
+
+			res := a.do(t, request{method: http.MethodDelete, path: "/films/" + filmId, user: "admin"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			res = a.do(t, request{method: http.MethodDelete, path: "/films/" + filmId, user: "admin"})
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+			// a deletion moves the version of the list, although no remaining film changed
+			res = a.do(t, request{method: http.MethodGet, path: "/films", user: "user",
+				header: map[string]string{"If-None-Match": list.Header.Get("ETag")}})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.NotEqual(t, list.Header.Get("ETag"), res.Header.Get("ETag"))
+			assert.NotContains(t, string(res.body), filmId)
+		})
+
+		t.Run("actor", func(t *testing.T) {
+			res := a.do(t, request{method: http.MethodDelete, path: "/actors/" + actorId, user: "admin"})
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assertGolden(t, res)
+
+			res = a.do(t, request{method: http.MethodGet, path: "/actors/" + actorId, user: "user"})
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	})
+
+	t.Run("unavailable in memory", func(t *testing.T) {
+		if a.driver != config.StorageMemory {
+			t.Skip("lists and search are checked above")
+		}
+
+		for name, path := range map[string]string{"lists": "/users/me/lists", "search": "/search?q=brat", "suggest": "/suggest?q=br&type=films"} {
+			t.Run(name, func(t *testing.T) {
+				res := a.do(t, request{method: http.MethodGet, path: path, user: "user"})
+				assert.Equal(t, http.StatusNotImplemented, res.StatusCode)
+				assertGolden(t, res)
+			})
+		}
+	})
+
+	t.Run("users are not implemented", func(t *testing.T) {
+		res := a.do(t, request{method: http.MethodPost, path: "/users", user: "admin"})
+		assert.Equal(t, http.StatusNotImplemented, res.StatusCode)
+		assertGolden(t, res)
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		res := a.do(t, request{method: http.MethodGet, path: "/directors", user: "admin"})
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assertGolden(t, res)
+	})
+}